@@ -19,7 +19,6 @@ import (
 	"time"
 
 	"github.com/codeskyblue/kexec"
-	"github.com/fuseml/fuseml/cli/helpers"
 	"github.com/onsi/ginkgo/config"
 
 	. "github.com/onsi/ginkgo"
@@ -78,11 +77,11 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 
 	if os.Getenv("REGISTRY_USERNAME") != "" && os.Getenv("REGISTRY_PASSWORD") != "" {
 		fmt.Printf("Creating image pull secret for Dockerhub on node %d\n", config.GinkgoConfig.ParallelNode)
-		helpers.Kubectl(fmt.Sprintf("create secret docker-registry regcred --docker-server=%s --docker-username=%s --docker-password=%s",
+		RunProc(fmt.Sprintf("kubectl create secret docker-registry regcred --docker-server=%s --docker-username=%s --docker-password=%s",
 			"https://index.docker.io/v1/",
 			os.Getenv("REGISTRY_USERNAME"),
 			os.Getenv("REGISTRY_PASSWORD"),
-		))
+		), nodeTmpDir, false)
 	}
 
 	if serve == "knative" {
@@ -0,0 +1,45 @@
+package acceptance_test
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pipeline triggers", func() {
+	var appName string
+
+	BeforeEach(func() {
+		appName = "unsigned-" + strconv.Itoa(int(time.Now().Nanosecond()))
+	})
+
+	// pushHook posts a GitLab push event for the app to the event listener,
+	// from inside the cluster, with the given extra curl arguments
+	pushHook := func(args string) {
+		body := fmt.Sprintf(`{"checkout_sha":"main","project":{"name":"%s","git_http_url":"http://example.com/%s.git"}}`, appName, appName)
+		cmd := fmt.Sprintf(`kubectl run -n fuseml-workloads webhook-%s --rm -i --restart=Never --image=curlimages/curl -- `+
+			`curl -sS -H 'Content-Type: application/json' -H 'X-Gitlab-Event: Push Hook' %s -d '%s' http://el-mlflow-listener:8080`,
+			appName, args, body)
+		out, err := RunProc(cmd, "", false)
+		Expect(err).ToNot(HaveOccurred(), out)
+	}
+
+	pipelineRuns := func() string {
+		out, err := RunProc("kubectl get pipelineruns -n fuseml-workloads -o name -l fuseml/app-name="+appName+" 2>/dev/null", "", false)
+		Expect(err).ToNot(HaveOccurred(), out)
+		return out
+	}
+
+	It("rejects an unsigned GitLab push", func() {
+		pushHook("")
+		Consistently(pipelineRuns, 30*time.Second, 5*time.Second).Should(BeEmpty())
+	})
+
+	It("rejects a GitLab push with the wrong token", func() {
+		pushHook("-H 'X-Gitlab-Token: not-the-secret'")
+		Consistently(pipelineRuns, 30*time.Second, 5*time.Second).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"github.com/fuseml/fuseml/cli/paas"
+	"github.com/fuseml/fuseml/cli/paas/ui"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CmdBundle implements the fuseml bundle command
+var CmdBundle = &cobra.Command{
+	Use:   "bundle",
+	Short: "Manage air-gapped installation bundles",
+	Long: `Manage air-gapped installation bundles, holding every chart and image
+needed to install Fuseml in a cluster without internet access.`,
+	Args: cobra.ExactArgs(0),
+}
+
+// CmdBundleCreate implements the fuseml bundle create command
+var CmdBundleCreate = &cobra.Command{
+	Use:   "create FILE",
+	Short: "Creates an installation bundle",
+	Long: `Downloads every chart and image Fuseml installs into the bundle FILE.
+Install from it with:
+
+  fuseml install --bundle FILE --image-registry MIRROR`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := paas.CreateBundle(ui.NewUI(), args[0])
+		if err != nil {
+			return errors.Wrap(err, "error creating bundle")
+		}
+
+		return nil
+	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+func init() {
+	CmdBundle.AddCommand(CmdBundleCreate)
+}
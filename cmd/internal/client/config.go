@@ -0,0 +1,128 @@
+package client
+
+import (
+	"strings"
+
+	"github.com/fuseml/fuseml/cli/paas"
+	"github.com/fuseml/fuseml/cli/paas/config"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CmdConfig implements the fuseml config command
+var CmdConfig = &cobra.Command{
+	Use:   "config",
+	Short: "Show and change the settings of the cli",
+	Long: `Show and change the settings of the cli, kept in its configuration file.
+Settings are taken from the current context, the FUSEML_* env, the file, or
+their default, in that order. Set and unset change the current context for
+the settings it holds.
+
+Settings: ` + strings.Join(config.Keys(), ", "),
+	Args: cobra.ExactArgs(0),
+	// Only the config file is changed, the dependencies are not needed
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+}
+
+// CmdConfigView implements the fuseml config view command
+var CmdConfigView = &cobra.Command{
+	Use:   "view",
+	Short: "Shows all settings, with where their values come from",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cmd.Flags())
+		if err != nil {
+			return errors.Wrap(err, "error loading config")
+		}
+
+		return paas.ViewConfig(paas.NewContextUI(cfg), cfg)
+	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+// CmdConfigGet implements the fuseml config get command
+var CmdConfigGet = &cobra.Command{
+	Use:   "get KEY",
+	Short: "Prints the value of a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cmd.Flags())
+		if err != nil {
+			return errors.Wrap(err, "error loading config")
+		}
+
+		return paas.GetConfig(cfg, args[0])
+	},
+	SilenceErrors:     true,
+	SilenceUsage:      true,
+	ValidArgsFunction: completeKeys,
+}
+
+// CmdConfigSet implements the fuseml config set command
+var CmdConfigSet = &cobra.Command{
+	Use:   "set KEY VALUE",
+	Short: "Changes a setting",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cmd.Flags())
+		if err != nil {
+			return errors.Wrap(err, "error loading config")
+		}
+
+		err = paas.SetConfig(paas.NewContextUI(cfg), cfg, args[0], args[1])
+		if err != nil {
+			return errors.Wrap(err, "failed to change setting")
+		}
+
+		return nil
+	},
+	SilenceErrors:     true,
+	SilenceUsage:      true,
+	ValidArgsFunction: completeKeys,
+}
+
+// CmdConfigUnset implements the fuseml config unset command
+var CmdConfigUnset = &cobra.Command{
+	Use:   "unset KEY",
+	Short: "Removes a setting, so that it follows its default again",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cmd.Flags())
+		if err != nil {
+			return errors.Wrap(err, "error loading config")
+		}
+
+		err = paas.UnsetConfig(paas.NewContextUI(cfg), cfg, args[0])
+		if err != nil {
+			return errors.Wrap(err, "failed to remove setting")
+		}
+
+		return nil
+	},
+	SilenceErrors:     true,
+	SilenceUsage:      true,
+	ValidArgsFunction: completeKeys,
+}
+
+func completeKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	matches := []string{}
+	for _, key := range config.Keys() {
+		if strings.HasPrefix(key, toComplete) {
+			matches = append(matches, key)
+		}
+	}
+
+	return matches, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	CmdConfig.AddCommand(CmdConfigView)
+	CmdConfig.AddCommand(CmdConfigGet)
+	CmdConfig.AddCommand(CmdConfigSet)
+	CmdConfig.AddCommand(CmdConfigUnset)
+}
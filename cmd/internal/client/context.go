@@ -0,0 +1,167 @@
+package client
+
+import (
+	"github.com/fuseml/fuseml/cli/paas"
+	"github.com/fuseml/fuseml/cli/paas/config"
+	"github.com/fuseml/fuseml/cli/paas/ui"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// CmdContext implements the fuseml context command
+var CmdContext = &cobra.Command{
+	Use:   "context",
+	Short: "Manage the installations the cli talks to",
+	Long: `Manage named contexts, each holding the kubeconfig, namespace, org and
+credentials of one Fuseml installation, e.g. on a dev, a staging and a prod
+cluster. The current context is used by all commands, unless another one is
+chosen with --context.`,
+	Args: cobra.ExactArgs(0),
+	// Only the config file is changed, the dependencies are not needed
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+}
+
+// CmdContextList implements the fuseml context list command
+var CmdContextList = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the contexts",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cmd.Flags())
+		if err != nil {
+			return errors.Wrap(err, "error loading config")
+		}
+
+		return paas.ListContexts(ui.NewUI(), cfg)
+	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+// CmdContextUse implements the fuseml context use command
+var CmdContextUse = &cobra.Command{
+	Use:   "use [NAME]",
+	Short: "Makes a context current",
+	Long: `Makes the context NAME current. Without NAME, the settings outside of
+any context are used again.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cmd.Flags())
+		if err != nil {
+			return errors.Wrap(err, "error loading config")
+		}
+
+		name := ""
+		if len(args) > 0 {
+			name = args[0]
+		}
+
+		err = paas.UseContext(ui.NewUI(), cfg, name)
+		if err != nil {
+			return errors.Wrap(err, "failed to switch context")
+		}
+
+		return nil
+	},
+	SilenceErrors:     true,
+	SilenceUsage:      true,
+	ValidArgsFunction: completeContexts,
+}
+
+// CmdContextAdd implements the fuseml context add command
+var CmdContextAdd = &cobra.Command{
+	Use:   "add NAME",
+	Short: "Adds a context",
+	Long: `Adds the context NAME, for the installation in the cluster of the
+--kubeconfig and --kube-context given. Settings which are not given are
+taken from outside of any context.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cmd.Flags())
+		if err != nil {
+			return errors.Wrap(err, "error loading config")
+		}
+
+		ctx := &config.Context{Kubeconfig: viper.GetString("kubeconfig")}
+		for flag, setting := range map[string]*string{
+			"kube-context":   &ctx.KubeContext,
+			"namespace":      &ctx.FusemlWorkloadsNamespace,
+			"org":            &ctx.Org,
+			"git-provider":   &ctx.GitProvider,
+			"gitlab-url":     &ctx.GitlabURL,
+			"gitlab-token":   &ctx.GitlabToken,
+			"gitea-protocol": &ctx.GiteaProtocol,
+		} {
+			*setting, err = cmd.Flags().GetString(flag)
+			if err != nil {
+				return err
+			}
+		}
+
+		use, err := cmd.Flags().GetBool("use")
+		if err != nil {
+			return err
+		}
+
+		err = paas.AddContext(ui.NewUI(), cfg, args[0], ctx, use)
+		if err != nil {
+			return errors.Wrap(err, "failed to add context")
+		}
+
+		return nil
+	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+// CmdContextRemove implements the fuseml context remove command
+var CmdContextRemove = &cobra.Command{
+	Use:   "remove NAME",
+	Short: "Removes a context",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cmd.Flags())
+		if err != nil {
+			return errors.Wrap(err, "error loading config")
+		}
+
+		err = paas.RemoveContext(ui.NewUI(), cfg, args[0])
+		if err != nil {
+			return errors.Wrap(err, "failed to remove context")
+		}
+
+		return nil
+	},
+	SilenceErrors:     true,
+	SilenceUsage:      true,
+	ValidArgsFunction: completeContexts,
+}
+
+func completeContexts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cfg, err := config.Load(cmd.Flags())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cfg.ContextNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	CmdContextAdd.Flags().String("kube-context", "", "the context of the kubeconfig, its current context if empty")
+	CmdContextAdd.Flags().String("namespace", "", "the namespace of the workloads")
+	CmdContextAdd.Flags().String("org", "", "the targeted organization")
+	CmdContextAdd.Flags().String("git-provider", "", "the git provider, gitea or gitlab")
+	CmdContextAdd.Flags().String("gitlab-url", "", "the URL of the GitLab instance")
+	CmdContextAdd.Flags().String("gitlab-token", "", "the access token for GitLab")
+	CmdContextAdd.Flags().String("gitea-protocol", "", "the protocol Gitea is reached with, http or https")
+	CmdContextAdd.Flags().Bool("use", false, "make the context current")
+
+	CmdContext.AddCommand(CmdContextList)
+	CmdContext.AddCommand(CmdContextUse)
+	CmdContext.AddCommand(CmdContextAdd)
+	CmdContext.AddCommand(CmdContextRemove)
+}
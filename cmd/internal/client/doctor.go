@@ -0,0 +1,29 @@
+package client
+
+import (
+	"github.com/fuseml/fuseml/cli/paas"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CmdDoctor implements the fuseml doctor command
+var CmdDoctor = &cobra.Command{
+	Use:   "doctor",
+	Short: "check whether Fuseml can be installed on your configured kubernetes cluster",
+	Long: `check the configured kubernetes cluster, and the commands Fuseml runs, before installing:
+the Kubernetes version, a default storage class, load balancers, allocatable resources,
+conflicting namespaces, and the CRDs of the serving types`,
+	Args: cobra.NoArgs,
+	// The missing commands are reported as a check
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		installClient, _, err := paas.NewInstallClient(cmd.Flags(), nil)
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		return installClient.Doctor()
+	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
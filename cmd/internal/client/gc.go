@@ -0,0 +1,83 @@
+package client
+
+import (
+	"time"
+
+	"github.com/fuseml/fuseml/cli/paas"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CmdGC implements the fuseml gc command
+var CmdGC = &cobra.Command{
+	Use:   "gc [APP]",
+	Short: "Removes old application builds and their workspace volumes",
+	Long: `Removes the PipelineRuns of an application, or of all applications, together
+with their workspace volumes, keeping only the most recent runs. The retention
+policy defaults to the gc_keep_runs and gc_max_age configuration values.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, cleanup, err := paas.NewFusemlClient(cmd.Flags(), nil)
+		defer func() {
+			if cleanup != nil {
+				cleanup()
+			}
+		}()
+
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		policy, err := client.DefaultRetentionPolicy()
+		if err != nil {
+			return errors.Wrap(err, "error reading retention policy")
+		}
+
+		if cmd.Flags().Changed("keep-runs") {
+			policy.KeepRuns, err = cmd.Flags().GetInt("keep-runs")
+			if err != nil {
+				return err
+			}
+		}
+		if cmd.Flags().Changed("max-age") {
+			policy.MaxAge, err = cmd.Flags().GetDuration("max-age")
+			if err != nil {
+				return err
+			}
+		}
+
+		app := ""
+		if len(args) > 0 {
+			app = args[0]
+		}
+
+		err = client.GC(app, policy)
+		if err != nil {
+			return errors.Wrap(err, "error collecting garbage")
+		}
+
+		return nil
+	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		app, cleanup, _ := paas.NewFusemlClient(cmd.Flags(), nil)
+		defer func() {
+			if cleanup != nil {
+				cleanup()
+			}
+		}()
+
+		matches := app.AppsMatching(toComplete)
+
+		return matches, cobra.ShellCompDirectiveNoFileComp
+	},
+}
+
+func init() {
+	CmdGC.Flags().Int("keep-runs", 0, "number of most recent runs to keep per application, 0 keeps all (default from config)")
+	CmdGC.Flags().Duration("max-age", time.Duration(0), "remove runs older than this, 0 disables the age limit (default from config)")
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"github.com/fuseml/fuseml/cli/paas"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CmdImages implements the fuseml images command
+var CmdImages = &cobra.Command{
+	Use:   "images [APP]",
+	Short: "Lists the application images stored in the registry",
+	Long: `Lists the images built for an application, or for all applications, with
+their tags and digests, marking the ones currently deployed. With --prune,
+images which are neither tagged nor deployed are removed from the registry.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, cleanup, err := paas.NewFusemlClient(cmd.Flags(), nil)
+		defer func() {
+			if cleanup != nil {
+				cleanup()
+			}
+		}()
+
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		prune, err := cmd.Flags().GetBool("prune")
+		if err != nil {
+			return err
+		}
+
+		app := ""
+		if len(args) > 0 {
+			app = args[0]
+		}
+
+		err = client.Images(app, prune)
+		if err != nil {
+			return errors.Wrap(err, "error listing images")
+		}
+
+		return nil
+	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		app, cleanup, _ := paas.NewFusemlClient(cmd.Flags(), nil)
+		defer func() {
+			if cleanup != nil {
+				cleanup()
+			}
+		}()
+
+		matches := app.AppsMatching(toComplete)
+
+		return matches, cobra.ShellCompDirectiveNoFileComp
+	},
+}
+
+func init() {
+	CmdImages.Flags().Bool("prune", false, "remove images which are neither tagged nor deployed and garbage collect the registry")
+}
@@ -1,8 +1,13 @@
 package client
 
 import (
+	"strings"
+
+	"github.com/fuseml/fuseml/cli/deployments"
 	"github.com/fuseml/fuseml/cli/kubernetes"
 	"github.com/fuseml/fuseml/cli/paas"
+	"github.com/fuseml/fuseml/cli/paas/bundle"
+	"github.com/fuseml/fuseml/cli/paas/ui"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -10,11 +15,61 @@ import (
 var NeededOptions = kubernetes.InstallationOptions{
 	{
 		Name:        "system_domain",
-		Description: "The domain you are planning to use for Fuseml. Should be pointing to the traefik public IP (Leave empty to use a omg.howdoi.website domain).",
+		Description: "The domain you are planning to use for Fuseml. Should be pointing to the traefik public IP (Leave empty to use a wildcard DNS domain of the dns_provider).",
+		Type:        kubernetes.StringType,
+		Default:     "",
+		Value:       "",
+		Validators:  []kubernetes.InstallationOptionValidator{kubernetes.DNSNameValidator()},
+	},
+	{
+		Name: "dns_provider",
+		Description: "The wildcard DNS service the system_domain defaults to, resolving <IP>.<provider> to the IP of the ingress: " +
+			strings.Join(kubernetes.WildcardDNSProviders, ", ") + ", or the domain of a custom one",
+		Type:       kubernetes.StringType,
+		Default:    kubernetes.WildcardDNSProviders[0],
+		Value:      "",
+		Validators: []kubernetes.InstallationOptionValidator{kubernetes.DNSNameValidator()},
+	},
+	{
+		Name:        "storage_class",
+		Description: "The storage class of the volumes of Gitea, MLflow and the registry (Leave empty to use the default storage class of the cluster)",
+		Type:        kubernetes.StringType,
+		Default:     "",
+		Value:       "",
+	},
+	{
+		Name:        "ingress_class",
+		Description: "The class of the ingresses, served by the installed Traefik, or by the ingress controller the platform provides",
+		Type:        kubernetes.StringType,
+		Default:     deployments.DefaultIngressClass,
+		Value:       "",
+	},
+	{
+		Name:        "bundle",
+		Description: "Install from an air-gapped bundle created with `fuseml bundle create`, pushing its images to the image registry",
 		Type:        kubernetes.StringType,
 		Default:     "",
 		Value:       "",
 	},
+	{
+		Name:        "image_registry",
+		Description: "The registry mirror (HOST[:PORT][/PATH]) all images are pulled from. Credentials are taken from the docker config.",
+		Type:        kubernetes.StringType,
+		Default:     "",
+		Value:       "",
+		Validators:  []kubernetes.InstallationOptionValidator{validateImageRegistry},
+	},
+}
+
+// validateImageRegistry checks that the image registry mirror is a valid
+// registry location, if set
+func validateImageRegistry(o *kubernetes.InstallationOption) error {
+	mirror := o.Value.(string)
+	if mirror == "" {
+		return nil
+	}
+
+	return bundle.ValidateMirror(mirror)
 }
 
 const (
@@ -33,7 +88,14 @@ var CmdInstall = &cobra.Command{
 
 func init() {
 	CmdInstall.Flags().BoolP("interactive", "i", false, "Whether to ask the user or not (default not)")
-
+	CmdInstall.Flags().StringSlice("only", []string{}, "Install only the given components, e.g. --only gitea,tekton")
+	CmdInstall.Flags().StringSlice("skip", []string{}, "Do not install the given components, e.g. to bring your own --skip traefik")
+	CmdInstall.Flags().Int("parallel", 3, "Number of components deployed at the same time, as far as their dependencies allow, 0 for no limit. 1 deploys them one after another, showing all their output")
+	CmdInstall.Flags().String("values", "", "A YAML or JSON file with option values. Options given on the command line take precedence.")
+	CmdInstall.Flags().Bool("dry-run", false, "Write everything the installation would apply into --render-dir, without modifying the cluster")
+	CmdInstall.Flags().String("render-dir", "fuseml-install", "The directory the manifests of a dry run are written to")
+
+	NeededOptions = append(NeededOptions, paas.DeploymentOptions()...)
 	NeededOptions.AsCobraFlagsFor(CmdInstall)
 }
 
@@ -55,6 +117,24 @@ func Install(cmd *cobra.Command, args []string) error {
 		return errors.Wrap(err, "error installing Fuseml")
 	}
 
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
+	}
+
+	// The default org needs Gitea, which may be skipped
+	hasGitHost, err := install_client.HasGitHost()
+	if err != nil {
+		return errors.Wrap(err, "error checking for Gitea")
+	}
+	if !hasGitHost {
+		ui.NewUI().Note().Msg("Gitea is not installed, the default org " + DefaultOrganization + " is not created")
+		return nil
+	}
+
 	// Installation complete. Run `create-org`
 
 	fuseml_client, fuseml_cleanup, err := paas.NewFusemlClient(cmd.Flags(), nil)
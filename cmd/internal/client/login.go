@@ -1 +1,119 @@
 package client
+
+import (
+	"github.com/fuseml/fuseml/cli/paas"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CmdLogin implements the fuseml login command
+var CmdLogin = &cobra.Command{
+	Use:   "login",
+	Short: "Logs in to Gitea as yourself",
+	Long: `Logs in to the Gitea of the installation with your own credentials, and
+keeps a personal access token in the config, used by all commands and git
+pushes. The password is read from the terminal, or from stdin.
+
+With --oauth2, you authorize the cli in the browser instead, with the OAuth2
+application registered for it in Gitea. Its redirect URI has to be
+http://127.0.0.1:PORT/callback, and its client ID and secret are taken from
+the gitea_oauth2_client_id and gitea_oauth2_client_secret settings.
+
+Without logging in, the admin credentials of Gitea are read from the cluster.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, cleanup, err := paas.NewLoginClient(cmd.Flags(), nil)
+		defer func() {
+			if cleanup != nil {
+				cleanup()
+			}
+		}()
+
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		oauth2, err := cmd.Flags().GetBool("oauth2")
+		if err != nil {
+			return err
+		}
+		if oauth2 {
+			port, err := cmd.Flags().GetInt("port")
+			if err != nil {
+				return err
+			}
+			err = client.LoginOAuth2(port)
+			if err != nil {
+				return errors.Wrap(err, "failed to log in")
+			}
+			return nil
+		}
+
+		username, err := cmd.Flags().GetString("username")
+		if err != nil {
+			return err
+		}
+		otp, err := cmd.Flags().GetString("otp")
+		if err != nil {
+			return err
+		}
+
+		err = client.Login(username, otp)
+		if err != nil {
+			return errors.Wrap(err, "failed to log in")
+		}
+
+		return nil
+	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+// CmdLogout implements the fuseml logout command
+var CmdLogout = &cobra.Command{
+	Use:   "logout",
+	Short: "Logs out of Gitea",
+	Long: `Removes the token of fuseml login from the config. With --revoke, the
+personal access token is deleted in Gitea too, which asks for your password.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, cleanup, err := paas.NewLoginClient(cmd.Flags(), nil)
+		defer func() {
+			if cleanup != nil {
+				cleanup()
+			}
+		}()
+
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		revoke, err := cmd.Flags().GetBool("revoke")
+		if err != nil {
+			return err
+		}
+		otp, err := cmd.Flags().GetString("otp")
+		if err != nil {
+			return err
+		}
+
+		err = client.Logout(revoke, otp)
+		if err != nil {
+			return errors.Wrap(err, "failed to log out")
+		}
+
+		return nil
+	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+func init() {
+	CmdLogin.Flags().StringP("username", "u", "", "the Gitea user, asked for if not given")
+	CmdLogin.Flags().String("otp", "", "the one-time password, if two-factor authentication is enabled")
+	CmdLogin.Flags().Bool("oauth2", false, "authorize the cli in the browser, with its OAuth2 application")
+	CmdLogin.Flags().Int("port", 8585, "the local port the browser is redirected to by --oauth2")
+
+	CmdLogout.Flags().Bool("revoke", false, "delete the personal access token in Gitea too")
+	CmdLogout.Flags().String("otp", "", "the one-time password for --revoke, if two-factor authentication is enabled")
+}
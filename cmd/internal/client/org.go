@@ -0,0 +1,145 @@
+package client
+
+import (
+	"github.com/fuseml/fuseml/cli/paas"
+	"github.com/fuseml/fuseml/cli/paas/githost"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CmdOrg implements the fuseml org command
+var CmdOrg = &cobra.Command{
+	Use:   "org",
+	Short: "Manage an organization",
+	Args:  cobra.ExactArgs(0),
+}
+
+// CmdOrgMembers implements the fuseml org members command
+var CmdOrgMembers = &cobra.Command{
+	Use:   "members",
+	Short: "Manage the members of an organization",
+	Long: `Manage the members of an organization and their roles. Members with the
+read role can clone its applications, with write they can push them too and
+with admin they can also manage the repositories. The targeted organization is
+used, unless another one is chosen with --org.`,
+	Args: cobra.ExactArgs(0),
+}
+
+// CmdOrgMembersList implements the fuseml org members list command
+var CmdOrgMembersList = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the members of an organization",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, cleanup, err := paas.NewFusemlClient(cmd.Flags(), nil)
+		defer func() {
+			if cleanup != nil {
+				cleanup()
+			}
+		}()
+
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		org, err := cmd.Flags().GetString("org")
+		if err != nil {
+			return errors.Wrap(err, "could not read org parameter")
+		}
+
+		role, err := cmd.Flags().GetString("role")
+		if err != nil {
+			return errors.Wrap(err, "could not read role parameter")
+		}
+
+		err = client.OrgMembers(org, role)
+		if err != nil {
+			return errors.Wrap(err, "error listing members")
+		}
+
+		return nil
+	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+// CmdOrgMembersAdd implements the fuseml org members add command
+var CmdOrgMembersAdd = &cobra.Command{
+	Use:   "add USER",
+	Short: "Adds a member to an organization, or changes their role",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, cleanup, err := paas.NewFusemlClient(cmd.Flags(), nil)
+		defer func() {
+			if cleanup != nil {
+				cleanup()
+			}
+		}()
+
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		org, err := cmd.Flags().GetString("org")
+		if err != nil {
+			return errors.Wrap(err, "could not read org parameter")
+		}
+		role, err := cmd.Flags().GetString("role")
+		if err != nil {
+			return errors.Wrap(err, "could not read role parameter")
+		}
+
+		err = client.AddOrgMember(org, args[0], role)
+		if err != nil {
+			return errors.Wrap(err, "error adding member")
+		}
+
+		return nil
+	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+// CmdOrgMembersRemove implements the fuseml org members remove command
+var CmdOrgMembersRemove = &cobra.Command{
+	Use:   "remove USER",
+	Short: "Removes a member from an organization",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, cleanup, err := paas.NewFusemlClient(cmd.Flags(), nil)
+		defer func() {
+			if cleanup != nil {
+				cleanup()
+			}
+		}()
+
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		org, err := cmd.Flags().GetString("org")
+		if err != nil {
+			return errors.Wrap(err, "could not read org parameter")
+		}
+
+		err = client.RemoveOrgMember(org, args[0])
+		if err != nil {
+			return errors.Wrap(err, "error removing member")
+		}
+
+		return nil
+	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+func init() {
+	CmdOrgMembers.PersistentFlags().String("org", "", "the organization, the targeted one if empty")
+	CmdOrgMembersAdd.Flags().String("role", githost.RoleWrite, "the role of the member (read, write, admin)")
+	CmdOrgMembersList.Flags().String("role", "", "list only the members with the role (owner, read, write, admin)")
+
+	CmdOrgMembers.AddCommand(CmdOrgMembersList)
+	CmdOrgMembers.AddCommand(CmdOrgMembersAdd)
+	CmdOrgMembers.AddCommand(CmdOrgMembersRemove)
+	CmdOrg.AddCommand(CmdOrgMembers)
+}
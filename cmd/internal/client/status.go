@@ -0,0 +1,44 @@
+package client
+
+import (
+	"os"
+
+	"github.com/fuseml/fuseml/cli/paas"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CmdStatus implements the fuseml status command
+var CmdStatus = &cobra.Command{
+	Use:   "status",
+	Short: "check the health of the Fuseml installation",
+	Long: `check the health of every Fuseml component: ready pods, deployed helm releases,
+reachable ingresses or gateways, the Tekton event listener and the registry TLS secrets.
+Exits with a non-zero code when any of them is degraded.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return errors.Wrap(err, "could not read output flag")
+		}
+
+		installClient, _, err := paas.NewInstallClient(cmd.Flags(), nil)
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = installClient.Status(output)
+		if err == paas.ErrDegraded && output == "json" {
+			// Keep the output valid JSON
+			os.Exit(1)
+		}
+
+		return err
+	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+func init() {
+	CmdStatus.Flags().StringP("output", "o", "table", "Output format, table or json")
+}
@@ -7,15 +7,20 @@ import (
 )
 
 var CmdUninstall = &cobra.Command{
-	Use:           "uninstall",
+	Use:           "uninstall [COMPONENT...]",
 	Short:         "uninstall Fuseml from your configured kubernetes cluster",
-	Long:          `uninstall Fuseml PaaS from your configured kubernetes cluster`,
-	Args:          cobra.ExactArgs(0),
+	Long:          `uninstall Fuseml PaaS, or only the given components of it, from your configured kubernetes cluster`,
+	Args:          cobra.ArbitraryArgs,
 	RunE:          Uninstall,
 	SilenceErrors: true,
 	SilenceUsage:  true,
 }
 
+func init() {
+	CmdUninstall.Flags().Bool("dry-run", false, "List what would be removed, without modifying the cluster")
+	CmdUninstall.Flags().Bool("keep-data", false, "Keep the volumes of Gitea, MLflow and the registry, i.e. repositories, databases and buckets, for a later reinstall")
+}
+
 // Uninstall command removes fuseml from a configured cluster
 func Uninstall(cmd *cobra.Command, args []string) error {
 	installClient, _, err := paas.NewInstallClient(cmd.Flags(), nil)
@@ -23,7 +28,7 @@ func Uninstall(cmd *cobra.Command, args []string) error {
 		return errors.Wrap(err, "error initializing cli")
 	}
 
-	err = installClient.Uninstall(cmd)
+	err = installClient.Uninstall(cmd, args)
 	if err != nil {
 		return err
 	}
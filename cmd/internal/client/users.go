@@ -0,0 +1,131 @@
+package client
+
+import (
+	"github.com/fuseml/fuseml/cli/paas"
+	"github.com/fuseml/fuseml/cli/paas/githost"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CmdUsers implements the fuseml users command
+var CmdUsers = &cobra.Command{
+	Use:   "users",
+	Short: "Manage the users of the git host",
+	Args:  cobra.ExactArgs(0),
+}
+
+// CmdUsersList implements the fuseml users list command
+var CmdUsersList = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the users",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, cleanup, err := paas.NewFusemlClient(cmd.Flags(), nil)
+		defer func() {
+			if cleanup != nil {
+				cleanup()
+			}
+		}()
+
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.Users()
+		if err != nil {
+			return errors.Wrap(err, "error listing users")
+		}
+
+		return nil
+	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+// CmdUsersCreate implements the fuseml users create command
+var CmdUsersCreate = &cobra.Command{
+	Use:   "create NAME",
+	Short: "Creates a user",
+	Long: `Creates the user NAME. Without --password, a random initial password is
+generated and shown. Either way, the user has to change it when logging in
+first.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, cleanup, err := paas.NewFusemlClient(cmd.Flags(), nil)
+		defer func() {
+			if cleanup != nil {
+				cleanup()
+			}
+		}()
+
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		email, err := cmd.Flags().GetString("email")
+		if err != nil {
+			return errors.Wrap(err, "could not read email parameter")
+		}
+		fullName, err := cmd.Flags().GetString("full-name")
+		if err != nil {
+			return errors.Wrap(err, "could not read full-name parameter")
+		}
+		password, err := cmd.Flags().GetString("password")
+		if err != nil {
+			return errors.Wrap(err, "could not read password parameter")
+		}
+
+		user := githost.User{
+			Username: args[0],
+			FullName: fullName,
+			Email:    email,
+		}
+		err = client.CreateUser(user, password)
+		if err != nil {
+			return errors.Wrap(err, "error creating user")
+		}
+
+		return nil
+	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+// CmdUsersDelete implements the fuseml users delete command
+var CmdUsersDelete = &cobra.Command{
+	Use:   "delete NAME",
+	Short: "Deletes a user",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, cleanup, err := paas.NewFusemlClient(cmd.Flags(), nil)
+		defer func() {
+			if cleanup != nil {
+				cleanup()
+			}
+		}()
+
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.DeleteUser(args[0])
+		if err != nil {
+			return errors.Wrap(err, "error deleting user")
+		}
+
+		return nil
+	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+func init() {
+	CmdUsersCreate.Flags().String("email", "", "the email address of the user (required)")
+	CmdUsersCreate.Flags().String("full-name", "", "the full name of the user")
+	CmdUsersCreate.Flags().String("password", "", "the initial password, generated if empty")
+	CmdUsersCreate.MarkFlagRequired("email")
+
+	CmdUsers.AddCommand(CmdUsersList)
+	CmdUsers.AddCommand(CmdUsersCreate)
+	CmdUsers.AddCommand(CmdUsersDelete)
+}
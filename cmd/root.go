@@ -8,6 +8,7 @@ import (
 
 	"github.com/fuseml/fuseml/cli/cmd/internal/client"
 	"github.com/fuseml/fuseml/cli/kubernetes/config"
+	"github.com/fuseml/fuseml/cli/paas"
 	pconfig "github.com/fuseml/fuseml/cli/paas/config"
 	"github.com/kyokomi/emoji"
 	"github.com/spf13/cobra"
@@ -26,14 +27,15 @@ var (
 // Execute adds all child commands to the root command sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	ExitfIfError(checkDependencies(), "Cannot operate")
-
 	rootCmd := &cobra.Command{
 		Use:           "fuseml",
 		Short:         "Fuseml cli",
 		Long:          `fuseml cli is the official command line interface for Fuseml PaaS `,
 		Version:       fmt.Sprintf("%s", Version),
 		SilenceErrors: true,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			ExitfIfError(checkDependencies(), "Cannot operate")
+		},
 	}
 
 	pf := rootCmd.PersistentFlags()
@@ -44,6 +46,10 @@ func Execute() {
 	viper.BindPFlag("config-file", pf.Lookup("config-file"))
 	argToEnv["config-file"] = "FUSEML_CONFIG"
 
+	pf.StringP("context", "", "", "use this context instead of the current one, see fuseml context")
+	viper.BindPFlag("context", pf.Lookup("context"))
+	argToEnv["context"] = "FUSEML_CONTEXT"
+
 	config.KubeConfigFlags(pf, argToEnv)
 	config.LoggerFlags(pf, argToEnv)
 
@@ -56,15 +62,26 @@ func Execute() {
 	config.AddEnvToUsage(rootCmd, argToEnv)
 
 	rootCmd.AddCommand(CmdCompletion)
+	rootCmd.AddCommand(client.CmdDoctor)
 	rootCmd.AddCommand(client.CmdInstall)
 	rootCmd.AddCommand(client.CmdUninstall)
 	rootCmd.AddCommand(client.CmdInfo)
+	rootCmd.AddCommand(client.CmdStatus)
 	rootCmd.AddCommand(client.CmdOrgs)
 	rootCmd.AddCommand(client.CmdCreateOrg)
+	rootCmd.AddCommand(client.CmdOrg)
 	rootCmd.AddCommand(client.CmdPush)
 	rootCmd.AddCommand(client.CmdDeleteApp)
 	rootCmd.AddCommand(client.CmdApps)
 	rootCmd.AddCommand(client.CmdTarget)
+	rootCmd.AddCommand(client.CmdGC)
+	rootCmd.AddCommand(client.CmdImages)
+	rootCmd.AddCommand(client.CmdBundle)
+	rootCmd.AddCommand(client.CmdContext)
+	rootCmd.AddCommand(client.CmdConfig)
+	rootCmd.AddCommand(client.CmdLogin)
+	rootCmd.AddCommand(client.CmdLogout)
+	rootCmd.AddCommand(client.CmdUsers)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -75,20 +92,10 @@ func Execute() {
 func checkDependencies() error {
 	ok := true
 
-	dependencies := []struct {
-		CommandName string
-	}{
-		{CommandName: "kubectl"},
-		{CommandName: "helm"},
-		{CommandName: "sh"},
-		{CommandName: "git"},
-		{CommandName: "openssl"},
-	}
-
-	for _, dependency := range dependencies {
-		_, err := exec.LookPath(dependency.CommandName)
+	for _, dependency := range paas.Commands {
+		_, err := exec.LookPath(dependency)
 		if err != nil {
-			fmt.Println(emoji.Sprintf(":fire:Not found: %s", dependency.CommandName))
+			fmt.Println(emoji.Sprintf(":fire:Not found: %s", dependency))
 			ok = false
 		}
 	}
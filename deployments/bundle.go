@@ -0,0 +1,107 @@
+package deployments
+
+import (
+	"bytes"
+
+	"github.com/fuseml/fuseml/cli/helpers"
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	"github.com/fuseml/fuseml/cli/paas/bundle"
+	"github.com/pkg/errors"
+)
+
+// BundleCharts returns the URLs of the charts downloaded at install time,
+// which an installation bundle has to carry
+func BundleCharts() []string {
+	return []string{quarksChartURL, traefikChartURL, giteaChartURL}
+}
+
+// EmbeddedCharts returns the charts embedded in the binary
+func EmbeddedCharts() []string {
+	return []string{mlflowChartFile, registryChartFile}
+}
+
+// EmbeddedManifests returns the embedded manifests applied at install time
+func EmbeddedManifests() []string {
+	return []string{
+		tektonAdminRoleYamlPath,
+		tektonPipelineYamlPath,
+		tektonTriggersYamlPath,
+		tektonDashboardYamlPath,
+		tektonFusemlYamlPath,
+		tektonKanikoYamlPath,
+		appIngressYamlPath,
+	}
+}
+
+// chartSource returns the chart helm installs: the given URL, or its copy
+// when installing from a bundle
+func chartSource(options kubernetes.InstallationOptions, url string) (string, error) {
+	path, err := options.GetString("bundle", "")
+	if err != nil || path == "" {
+		return url, nil
+	}
+
+	b, err := bundle.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer b.Close()
+
+	chart, ok := b.Chart(url)
+	if !ok {
+		return "", errors.Errorf("bundle %s does not contain the chart %s", path, url)
+	}
+
+	return chart, nil
+}
+
+// imageRegistry returns the registry mirror to pull all images from, if
+// one is configured
+func imageRegistry(options kubernetes.InstallationOptions) string {
+	mirror, err := options.GetString("image_registry", "")
+	if err != nil {
+		return ""
+	}
+
+	return mirror
+}
+
+// imageRewriter is the helm post-renderer pointing the images of a chart to
+// the image registry mirror
+type imageRewriter struct {
+	mirror string
+}
+
+func (r imageRewriter) Run(manifests *bytes.Buffer) (*bytes.Buffer, error) {
+	rewritten, err := bundle.RewriteImages(manifests.Bytes(), r.mirror)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBuffer(rewritten), nil
+}
+
+// applyEmbeddedYaml applies an embedded manifest, with its images pointed
+// to the image registry mirror, if one is configured
+func applyEmbeddedYaml(c *kubernetes.Cluster, options kubernetes.InstallationOptions, yamlPath, namespace string) error {
+	manifest, err := helpers.ReadEmbeddedFile(yamlPath)
+	if err != nil {
+		return errors.New("Failed to extract embedded file: " + yamlPath + " - " + err.Error())
+	}
+
+	return applyManifest(c, options, manifest, namespace)
+}
+
+// applyManifest applies the manifest, with its images pointed to the image
+// registry mirror, if one is configured
+func applyManifest(c *kubernetes.Cluster, options kubernetes.InstallationOptions, manifest []byte, namespace string) error {
+	if mirror := imageRegistry(options); mirror != "" {
+		var err error
+		manifest, err = bundle.RewriteImages(manifest, mirror)
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.ApplyManifest(manifest, namespace)
+}
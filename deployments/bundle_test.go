@@ -0,0 +1,37 @@
+package deployments_test
+
+import (
+	"bytes"
+
+	. "github.com/fuseml/fuseml/cli/deployments"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ImageRewriter", func() {
+	It("points the images of the rendered charts to the mirror", func() {
+		manifests := bytes.NewBufferString(`---
+# Source: traefik/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: traefik
+spec:
+  template:
+    spec:
+      containers:
+      - name: traefik
+        image: "traefik:2.4.8"
+`)
+
+		rewritten, err := NewImageRewriter("mirror.local").Run(manifests)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rewritten.String()).To(ContainSubstring(`image: "mirror.local/library/traefik:2.4.8"`))
+		Expect(rewritten.String()).To(ContainSubstring("# Source: traefik/templates/deployment.yaml"))
+	})
+
+	It("fails for an invalid mirror", func() {
+		_, err := NewImageRewriter("https://mirror.local").Run(bytes.NewBufferString("image: alpine\n"))
+		Expect(err).To(HaveOccurred())
+	})
+})
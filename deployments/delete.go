@@ -0,0 +1,63 @@
+package deployments
+
+import (
+	"strings"
+
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	"github.com/fuseml/fuseml/cli/paas/ui"
+)
+
+// removal describes what deleting a deployment removes from the cluster
+type removal struct {
+	// name of the deployment, for display
+	name string
+	// resources lists what is removed, for dry runs
+	resources []string
+	// dataNamespace holds the volume claims of the deployment's data,
+	// matching any of dataSelectors, if it keeps any
+	dataNamespace string
+	dataSelectors []string
+}
+
+// prepare handles the delete options common to all deployments. With
+// DryRun it shows what would be removed, and returns false as nothing more
+// is to be done. With KeepData it retains the data volumes, before their
+// claims are removed.
+func (r removal) prepare(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.DeleteOptions) (bool, error) {
+	var claims []string
+	if r.dataNamespace != "" {
+		var err error
+		if options.DryRun {
+			claims, err = c.VolumeClaims(r.dataNamespace, r.dataSelectors...)
+		} else if options.KeepData {
+			claims, err = c.RetainVolumes(r.dataNamespace, r.dataSelectors...)
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if !options.DryRun {
+		if len(claims) > 0 {
+			ui.Note().
+				WithStringValue("Volume claims", strings.Join(claims, ", ")).
+				Msg("Keeping the data of " + r.name + " for a later reinstall")
+		}
+		return true, nil
+	}
+
+	msg := ui.Normal().WithTable("Would remove")
+	for _, resource := range r.resources {
+		msg = msg.WithTableRow(resource)
+	}
+	for _, claim := range claims {
+		if options.KeepData {
+			msg = msg.WithTableRow("volume claim " + r.dataNamespace + "/" + claim + ", keeping its volume")
+		} else {
+			msg = msg.WithTableRow("volume claim " + r.dataNamespace + "/" + claim + ", with its data")
+		}
+	}
+	msg.Msg(r.name + ":")
+
+	return false, nil
+}
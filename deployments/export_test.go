@@ -0,0 +1,38 @@
+package deployments
+
+import (
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	"helm.sh/helm/v3/pkg/postrender"
+	corev1 "k8s.io/api/core/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// Unexported helpers, exported for the tests of package deployments_test
+
+var (
+	WithPlaceholders = withPlaceholders
+	URLValidator     = urlValidator
+	BundledConfig    = bundledConfig
+	StorageSecret    = storageSecret
+
+	TektonFusemlManifest = tektonFusemlManifest
+	TektonIngress        = tektonIngress
+)
+
+// NewImageRewriter returns the helm post-renderer pointing the images to
+// the mirror
+func NewImageRewriter(mirror string) postrender.PostRenderer {
+	return imageRewriter{mirror: mirror}
+}
+
+func (k Gitea) CredsSecret(options kubernetes.InstallationOptions) (*corev1.Secret, error) {
+	return k.credsSecret(options)
+}
+
+func (k MLflow) ExternalConfig(options kubernetes.InstallationOptions) (*corev1.Secret, error) {
+	return k.externalConfig(options)
+}
+
+func EnsureWebhookSecretIn(secrets typedcorev1.SecretInterface) (string, error) {
+	return ensureWebhookSecret(secrets)
+}
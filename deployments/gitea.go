@@ -2,15 +2,17 @@ package deployments
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/fuseml/fuseml/cli/helpers"
 	"github.com/fuseml/fuseml/cli/kubernetes"
 	"github.com/fuseml/fuseml/cli/paas/ui"
 	"github.com/kyokomi/emoji"
 	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/cli/values"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -23,12 +25,62 @@ const (
 	GiteaDeploymentID = "gitea"
 	giteaVersion      = "2.1.3"
 	giteaChartURL     = "https://dl.gitea.io/charts/gitea-2.1.3.tgz"
+
+	// giteaPasswordPlaceholder names the admin password in rendered
+	// manifests
+	giteaPasswordPlaceholder = "GITEA_ADMIN_PASSWORD"
+
+	// giteaCredsSecret holds the admin credentials in the workloads namespace
+	giteaCredsSecret = "gitea-creds"
 )
 
 func (k *Gitea) ID() string {
 	return GiteaDeploymentID
 }
 
+// Needs returns the workloads deployment, which holds the Gitea credentials
+func (k Gitea) Needs() []string {
+	return []string{WorkloadsDeploymentID}
+}
+
+// Options returns the Gitea admin credentials, used by FuseML to access
+// Gitea, and the size of the repositories volume
+func (k Gitea) Options() kubernetes.InstallationOptions {
+	return kubernetes.InstallationOptions{
+		{
+			Name:         "admin_username",
+			Description:  "The name of the Gitea admin user, which FuseML uses to manage organizations and repositories",
+			Type:         kubernetes.StringType,
+			Default:      "dev",
+			Value:        "",
+			DeploymentID: GiteaDeploymentID,
+			Validators: []kubernetes.InstallationOptionValidator{
+				kubernetes.RequiredValidator(),
+				kubernetes.RegexpValidator(`^[a-zA-Z0-9_.-]+$`),
+			},
+		},
+		{
+			Name:         "admin_password",
+			Description:  "The password of the Gitea admin user",
+			Type:         kubernetes.StringType,
+			Default:      "changeme",
+			Value:        "",
+			DeploymentID: GiteaDeploymentID,
+			Validators:   []kubernetes.InstallationOptionValidator{kubernetes.RequiredValidator()},
+			Sensitive:    true,
+		},
+		{
+			Name:         "storage_size",
+			Description:  "The size of the volume holding the Gitea repositories",
+			Type:         kubernetes.StringType,
+			Default:      "10Gi",
+			Value:        "",
+			DeploymentID: GiteaDeploymentID,
+			Validators:   []kubernetes.InstallationOptionValidator{storageSizeValidator},
+		},
+	}
+}
+
 func (k *Gitea) Backup(c *kubernetes.Cluster, ui *ui.UI, d string) error {
 	return nil
 }
@@ -41,8 +93,27 @@ func (k Gitea) Describe() string {
 	return emoji.Sprintf(":cloud:Gitea version: %s\n:clipboard:Gitea chart: %s", giteaVersion, giteaChartURL)
 }
 
+// Health checks the Gitea release, its pods, its endpoint and the admin
+// credentials
+func (k Gitea) Health(c *kubernetes.Cluster) []kubernetes.HealthCheck {
+	namespace := c.NamespaceHealth(GiteaDeploymentID)
+	if !namespace.Healthy {
+		return []kubernetes.HealthCheck{namespace}
+	}
+
+	checks := []kubernetes.HealthCheck{namespace, releaseHealth(c, "gitea", GiteaDeploymentID)}
+	for _, podname := range []string{"memcached", "postgresql", "gitea"} {
+		checks = append(checks, c.PodsHealth(GiteaDeploymentID, "app.kubernetes.io/name="+podname))
+	}
+
+	return append(checks,
+		c.EndpointHealth(GiteaDeploymentID, "app.kubernetes.io/name=gitea"),
+		c.SecretsHealth(WorkloadsDeploymentID, giteaCredsSecret),
+	)
+}
+
 // Delete removes Gitea from kubernetes cluster
-func (k Gitea) Delete(c *kubernetes.Cluster, ui *ui.UI) error {
+func (k Gitea) Delete(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.DeleteOptions) error {
 	ui.Note().KeeplineUnder(1).Msg("Removing Gitea...")
 
 	existsAndOwned, err := c.NamespaceExistsAndOwned(GiteaDeploymentID)
@@ -54,24 +125,42 @@ func (k Gitea) Delete(c *kubernetes.Cluster, ui *ui.UI) error {
 		return nil
 	}
 
-	currentdir, err := os.Getwd()
-	if err != nil {
-		return errors.New("Failed uninstalling Gitea: " + err.Error())
+	proceed, err := removal{
+		name: "Gitea",
+		resources: []string{
+			"helm release " + GiteaDeploymentID + "/gitea",
+			"secret " + WorkloadsDeploymentID + "/" + giteaCredsSecret,
+			k.gateway("").String(),
+			"namespace " + GiteaDeploymentID,
+		},
+		dataNamespace: GiteaDeploymentID,
+	}.prepare(c, ui, options)
+	if !proceed || err != nil {
+		return err
 	}
 
 	message := "Removing helm release " + GiteaDeploymentID
-	out, err := helpers.WaitForCommandCompletion(ui, message,
+	found := true
+	_, err = helpers.WaitForCommandCompletion(ui, message,
 		func() (string, error) {
-			helmCmd := fmt.Sprintf("helm uninstall gitea --namespace %s", GiteaDeploymentID)
-			return helpers.RunProc(helmCmd, currentdir, k.Debug)
+			var err error
+			found, err = uninstallRelease(c, "gitea", GiteaDeploymentID, k.Debug)
+			return "", err
 		},
 	)
 	if err != nil {
-		if strings.Contains(out, "release: not found") {
-			ui.Exclamation().Msgf("%s helm release not found, skipping.\n", GiteaDeploymentID)
-		} else {
-			return errors.Wrapf(err, "Failed uninstalling helm release %s: %s", GiteaDeploymentID, out)
-		}
+		return errors.Wrap(err, "Failed uninstalling Gitea")
+	}
+	if !found {
+		ui.Exclamation().Msgf("%s helm release not found, skipping.\n", GiteaDeploymentID)
+	}
+
+	if err := deleteSecret(c, WorkloadsDeploymentID, giteaCredsSecret); err != nil {
+		return errors.Wrap(err, "Failed deleting the Gitea credentials")
+	}
+
+	if err := k.gateway("").delete(c); err != nil {
+		return errors.Wrap(err, "Failed deleting the Gitea istio gateway")
 	}
 
 	message = "Deleting Gitea namespace " + GiteaDeploymentID
@@ -89,35 +178,24 @@ func (k Gitea) Delete(c *kubernetes.Cluster, ui *ui.UI) error {
 	return nil
 }
 
-func (k Gitea) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions, upgrade bool) error {
-	action := "install"
-	if upgrade {
-		action = "upgrade"
-	}
+// gateway returns the istio gateway exposing Gitea
+func (k Gitea) gateway(domain string) istioGateway {
+	return istioGateway{name: "gitea", namespace: GiteaDeploymentID, host: GiteaDeploymentID + "." + domain, service: "gitea-http", port: 10080}
+}
 
-	currentdir, err := os.Getwd()
+// release returns the helm release of Gitea. The ingress is only enabled
+// without istio. The returned function removes its temporary files.
+func (k Gitea) release(options kubernetes.InstallationOptions, domain string, hasIstio bool) (helmRelease, func(), error) {
+	subdomain := GiteaDeploymentID + "." + domain
+
+	username, password, err := k.credentials(options)
 	if err != nil {
-		return err
+		return helmRelease{}, nil, err
 	}
-	if action == "install" {
-		helmCmd := fmt.Sprintf("helm list --namespace %s --deployed -q | grep gitea", GiteaDeploymentID)
-		out, _ := helpers.RunProc(helmCmd, currentdir, k.Debug)
-		if strings.TrimSpace(out) == "gitea" {
-			ui.Exclamation().Msg("gitea already present under " + GiteaDeploymentID + " namespace, skipping installation")
-			return nil
-		}
-	}
-
-	// Setup Gitea helm values
-	var helmArgs []string
-
-	domain, err := options.GetString("system_domain", GiteaDeploymentID)
+	storageSize, err := options.GetString("storage_size", GiteaDeploymentID)
 	if err != nil {
-		return err
+		return helmRelease{}, nil, err
 	}
-	subdomain := GiteaDeploymentID + "." + domain
-
-	hasIstio := c.HasIstio()
 
 	config := fmt.Sprintf(`
 ingress:
@@ -125,7 +203,7 @@ ingress:
   hosts:
     - %s
   annotations:
-    kubernetes.io/ingress.class: traefik
+    kubernetes.io/ingress.class: %s
 service:
   http:
     type: NodePort
@@ -135,10 +213,14 @@ service:
     port: 10022
   externalTrafficPolicy: Local
 
+persistence:
+  size: %s
+  storageClass: %q
+
 gitea:
   admin:
-    username: "dev"
-    password: "changeme"
+    username: %s
+    password: %s
     email: "admin@fuseml.sh"
   config:
     APP_NAME: "Fuseml"
@@ -163,29 +245,134 @@ gitea:
     oauth2:
       ENABLE: true
       JWT_SECRET: HLNn92qqtznZSMkD_TzR_XFVdiZ5E87oaus6pyH7tiI
-`, !hasIstio, subdomain, subdomain, "http://"+subdomain)
+`, !hasIstio, subdomain, ingressClass(options), storageSize, storageClass(options), yamlString(username), yamlString(password), subdomain, "http://"+subdomain)
 
 	configPath, err := helpers.CreateTmpFile(config)
+	if err != nil {
+		os.Remove(configPath)
+		return helmRelease{}, nil, err
+	}
+
+	chart, err := chartSource(options, giteaChartURL)
+	if err != nil {
+		os.Remove(configPath)
+		return helmRelease{}, nil, err
+	}
+
+	release := helmRelease{
+		name:          "gitea",
+		namespace:     GiteaDeploymentID,
+		chart:         chart,
+		values:        values.Options{ValueFiles: []string{configPath}},
+		imageRegistry: imageRegistry(options),
+	}
+
+	return release, func() { os.Remove(configPath) }, nil
+}
+
+// credentials returns the admin username and password from the options
+func (k Gitea) credentials(options kubernetes.InstallationOptions) (string, string, error) {
+	username, err := options.GetString("admin_username", GiteaDeploymentID)
+	if err != nil {
+		return "", "", err
+	}
+	password, err := options.GetString("admin_password", GiteaDeploymentID)
+	if err != nil {
+		return "", "", err
+	}
+
+	return username, password, nil
+}
+
+// credsSecret returns the secret holding the admin credentials in the
+// workloads namespace, where FuseML and the pipelines read them from
+func (k Gitea) credsSecret(options kubernetes.InstallationOptions) (*corev1.Secret, error) {
+	username, password, err := k.credentials(options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      giteaCredsSecret,
+			Namespace: WorkloadsDeploymentID,
+			Annotations: map[string]string{
+				//"kpack.io/git": fmt.Sprintf("http://%s.%s", GiteaDeploymentID, domain),
+				"tekton.dev/git-0": "http://gitea-http.gitea:10080", // TODO: Don't hardcode
+			},
+		},
+		StringData: map[string]string{
+			"username": username,
+			"password": password,
+		},
+		Type: "kubernetes.io/basic-auth",
+	}, nil
+}
+
+// applyCredsSecret creates the credentials secret, or updates it on
+// upgrades
+func (k Gitea) applyCredsSecret(c *kubernetes.Cluster, options kubernetes.InstallationOptions) error {
+	secret, err := k.credsSecret(options)
 	if err != nil {
 		return err
 	}
-	defer os.Remove(configPath)
 
-	helmCmd := fmt.Sprintf("helm %s gitea --create-namespace --values %s --namespace %s %s %s", action, configPath, GiteaDeploymentID, giteaChartURL, strings.Join(helmArgs, " "))
+	return applySecret(c, secret)
+}
+
+// GiteaInstalled returns whether the helm release of Gitea is deployed
+func GiteaInstalled(c *kubernetes.Cluster) (bool, error) {
+	return releaseDeployed(c, "gitea", GiteaDeploymentID)
+}
+
+func (k Gitea) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions, upgrade bool) error {
+	if !upgrade {
+		deployed, err := GiteaInstalled(c)
+		if err != nil {
+			return err
+		}
+		if deployed {
+			ui.Exclamation().Msg("gitea already present under " + GiteaDeploymentID + " namespace, skipping installation")
+			return nil
+		}
+	}
 
-	if out, err := helpers.RunProc(helmCmd, currentdir, k.Debug); err != nil {
-		return errors.New("Failed installing Gitea: " + out)
+	// Volumes kept by an uninstall with --keep-data hold the repositories
+	// and the database
+	if err := c.ReleaseRetainedVolumes(GiteaDeploymentID); err != nil {
+		return err
+	}
+
+	domain, err := options.GetString("system_domain", GiteaDeploymentID)
+	if err != nil {
+		return err
+	}
+
+	hasIstio := c.HasIstio()
+
+	release, cleanup, err := k.release(options, domain, hasIstio)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if err := release.install(c, upgrade, k.Debug); err != nil {
+		return errors.Wrap(err, "Failed installing Gitea")
 	}
 	err = c.LabelNamespace(GiteaDeploymentID, kubernetes.FusemlDeploymentLabelKey, kubernetes.FusemlDeploymentLabelValue)
 	if err != nil {
 		return err
 	}
 
+	if err := k.applyCredsSecret(c, options); err != nil {
+		return errors.Wrap(err, "failed to store the Gitea credentials")
+	}
+
 	if hasIstio {
 		message := "Creating istio ingress gateway"
 		out, err := helpers.WaitForCommandCompletion(ui, message,
 			func() (string, error) {
-				return helpers.CreateIstioIngressGateway("gitea", GiteaDeploymentID, subdomain, "gitea-http", 10080)
+				return "", k.gateway(domain).create(c)
 			},
 		)
 		if err != nil {
@@ -211,6 +398,64 @@ gitea:
 	return nil
 }
 
+// Render writes the Gitea namespace, chart, credentials and gateway into dir.
+// The admin password is left out, the placeholder ${GITEA_ADMIN_PASSWORD}
+// stands in for it.
+func (k Gitea) Render(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions, dir string) error {
+	options = withPlaceholders(options, GiteaDeploymentID, map[string]string{"admin_password": giteaPasswordPlaceholder})
+
+	domain, err := options.GetString("system_domain", GiteaDeploymentID)
+	if err != nil {
+		return err
+	}
+
+	r, err := newRenderer(dir, GiteaDeploymentID)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Objects("namespace", fusemlNamespace(GiteaDeploymentID, nil)); err != nil {
+		return err
+	}
+
+	hasIstio := c.HasIstio()
+
+	release, cleanup, err := k.release(options, domain, hasIstio)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if err := release.render(r); err != nil {
+		return err
+	}
+
+	secret, err := k.credsSecret(options)
+	if err != nil {
+		return err
+	}
+	if err := r.Objects(giteaCredsSecret, secret); err != nil {
+		return err
+	}
+
+	if hasIstio {
+		manifest, err := k.gateway(domain).manifest()
+		if err != nil {
+			return err
+		}
+		if err := r.Manifest("gitea-gateway", manifest); err != nil {
+			return err
+		}
+	}
+
+	ui.Success().
+		WithStringValue("Directory", r.Dir).
+		WithStringValue("Placeholder", secretPlaceholder(giteaPasswordPlaceholder)).
+		Msg("Gitea rendered, without the admin password")
+
+	return nil
+}
+
 func (k Gitea) GetVersion() string {
 	return giteaVersion
 }
@@ -250,3 +495,9 @@ func (k Gitea) Upgrade(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.Inst
 
 	return k.apply(c, ui, options, true)
 }
+
+// yamlString quotes s for use as a YAML string value
+func yamlString(s string) string {
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
@@ -0,0 +1,297 @@
+package deployments
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/postrender"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// helmTimeout is how long helm waits for the hooks of a chart, as the helm
+// binary does by default
+const helmTimeout = 5 * time.Minute
+
+// helmRelease is a helm chart installed by a deployment
+type helmRelease struct {
+	name      string
+	namespace string
+	// chart is the URL or the local path of the chart archive
+	chart string
+	// values are given as with the --values and --set flags of helm
+	values values.Options
+	// imageRegistry is the registry mirror the images of the chart are
+	// pointed to, if any
+	imageRegistry string
+}
+
+// install installs the release, or upgrades it
+func (r helmRelease) install(c *kubernetes.Cluster, upgrade bool, debug bool) error {
+	cfg, err := helmConfig(c, r.namespace, debug)
+	if err != nil {
+		return err
+	}
+
+	chart, vals, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	postRenderer, err := r.newPostRenderer()
+	if err != nil {
+		return err
+	}
+
+	if upgrade {
+		client := action.NewUpgrade(cfg)
+		client.Namespace = r.namespace
+		client.Timeout = helmTimeout
+		client.PostRenderer = postRenderer
+		_, err = client.Run(r.name, chart, vals)
+		return errors.Wrapf(err, "failed to upgrade helm release %s", r.name)
+	}
+
+	client := action.NewInstall(cfg)
+	client.ReleaseName = r.name
+	client.Namespace = r.namespace
+	client.CreateNamespace = true
+	client.Timeout = helmTimeout
+	client.PostRenderer = postRenderer
+	_, err = client.Run(chart, vals)
+	return errors.Wrapf(err, "failed to install helm release %s", r.name)
+}
+
+// template returns the manifests of the release, with its hooks, as
+// rendered by `helm template`. The cluster is not contacted.
+func (r helmRelease) template() ([]byte, error) {
+	chart, vals, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	postRenderer, err := r.newPostRenderer()
+	if err != nil {
+		return nil, err
+	}
+
+	client := action.NewInstall(&action.Configuration{Log: helmLog(false)})
+	client.ReleaseName = r.name
+	client.Namespace = r.namespace
+	client.DryRun = true
+	client.Replace = true
+	client.ClientOnly = true
+	client.PostRenderer = postRenderer
+	rel, err := client.Run(chart, vals)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to render helm release %s", r.name)
+	}
+
+	var manifests bytes.Buffer
+	fmt.Fprintln(&manifests, strings.TrimSpace(rel.Manifest))
+	for _, hook := range rel.Hooks {
+		fmt.Fprintf(&manifests, "---\n# Source: %s\n%s\n", hook.Path, hook.Manifest)
+	}
+
+	return manifests.Bytes(), nil
+}
+
+// render writes the manifests of the release, as rendered by `helm template`
+func (r helmRelease) render(renderer *kubernetes.Renderer) error {
+	manifests, err := r.template()
+	if err != nil {
+		return err
+	}
+
+	return renderer.Manifest(r.name, manifests)
+}
+
+// load returns the chart of the release, downloading it if needed, and its
+// values
+func (r helmRelease) load() (*chart.Chart, map[string]interface{}, error) {
+	chart, err := loadChart(r.chart)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to load the chart of helm release %s", r.name)
+	}
+
+	vals, err := r.values.MergeValues(getter.All(cli.New()))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to read the values of helm release %s", r.name)
+	}
+
+	return chart, vals, nil
+}
+
+// newPostRenderer returns the post-renderer of the release, or nil
+func (r helmRelease) newPostRenderer() (postrender.PostRenderer, error) {
+	if r.imageRegistry == "" {
+		return nil, nil
+	}
+
+	return imageRewriter{mirror: r.imageRegistry}, nil
+}
+
+// TemplateChart returns the manifests of the chart with its default values,
+// as rendered by `helm template`
+func TemplateChart(source string) ([]byte, error) {
+	return helmRelease{name: "fuseml", namespace: "default", chart: source}.template()
+}
+
+// loadChart loads a chart archive from its URL or local path
+func loadChart(source string) (*chart.Chart, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return loader.Load(source)
+	}
+
+	g, err := getter.NewHTTPGetter()
+	if err != nil {
+		return nil, err
+	}
+	data, err := g.Get(source)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to download %s", source)
+	}
+
+	return loader.LoadArchive(data)
+}
+
+// releaseDeployed returns whether the helm release is installed and deployed
+func releaseDeployed(c *kubernetes.Cluster, name, namespace string) (bool, error) {
+	cfg, err := helmConfig(c, namespace, false)
+	if err != nil {
+		return false, err
+	}
+
+	client := action.NewList(cfg)
+	client.Deployed = true
+	client.Filter = "^" + regexp.QuoteMeta(name) + "$"
+	client.SetStateMask()
+	releases, err := client.Run()
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to list helm releases in %s", namespace)
+	}
+
+	return len(releases) > 0, nil
+}
+
+// uninstallRelease removes the helm release, returning false if it is not
+// installed
+func uninstallRelease(c *kubernetes.Cluster, name, namespace string, debug bool) (bool, error) {
+	cfg, err := helmConfig(c, namespace, debug)
+	if err != nil {
+		return false, err
+	}
+
+	client := action.NewUninstall(cfg)
+	client.Timeout = helmTimeout
+	_, err = client.Run(name)
+	if errors.Cause(err) == driver.ErrReleaseNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return true, errors.Wrapf(err, "failed to uninstall helm release %s", name)
+	}
+
+	return true, nil
+}
+
+// releaseHealth checks that the helm release is deployed, as reported by
+// `helm status`
+func releaseHealth(c *kubernetes.Cluster, name, namespace string) kubernetes.HealthCheck {
+	check := "helm release " + namespace + "/" + name
+
+	cfg, err := helmConfig(c, namespace, false)
+	if err != nil {
+		return kubernetes.Unhealthy(check, "%s", err.Error())
+	}
+
+	rel, err := action.NewStatus(cfg).Run(name)
+	if errors.Cause(err) == driver.ErrReleaseNotFound {
+		return kubernetes.Unhealthy(check, "not found")
+	}
+	if err != nil {
+		return kubernetes.Unhealthy(check, "cannot read helm status: %s", err.Error())
+	}
+
+	if rel.Info.Status != release.StatusDeployed {
+		return kubernetes.Unhealthy(check, "%s, revision %d", rel.Info.Status, rel.Version)
+	}
+
+	return kubernetes.Healthy(check, "deployed, revision %d", rel.Version)
+}
+
+// helmConfig returns the helm configuration for the releases in the
+// namespace, stored in secrets as by the helm binary
+func helmConfig(c *kubernetes.Cluster, namespace string, debug bool) (*action.Configuration, error) {
+	cfg := &action.Configuration{}
+	getter := &restClientGetter{config: c.RestConfig, namespace: namespace}
+	if err := cfg.Init(getter, namespace, "secret", helmLog(debug)); err != nil {
+		return nil, errors.Wrap(err, "failed to initialize helm")
+	}
+
+	return cfg, nil
+}
+
+// helmLog returns the logger of helm, printing its messages only in debug
+// mode
+func helmLog(debug bool) action.DebugLog {
+	if !debug {
+		return func(format string, v ...interface{}) {}
+	}
+
+	return func(format string, v ...interface{}) {
+		fmt.Printf(format+"\n", v...)
+	}
+}
+
+// restClientGetter hands the rest config of the cluster to helm, instead of
+// having it read the kubeconfig again
+type restClientGetter struct {
+	config    *restclient.Config
+	namespace string
+}
+
+func (g *restClientGetter) ToRESTConfig() (*restclient.Config, error) {
+	return restclient.CopyConfig(g.config), nil
+}
+
+func (g *restClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	client, err := discovery.NewDiscoveryClientForConfig(g.config)
+	if err != nil {
+		return nil, err
+	}
+
+	return memory.NewMemCacheClient(client), nil
+}
+
+func (g *restClientGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	client, err := g.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return restmapper.NewDeferredDiscoveryRESTMapper(client), nil
+}
+
+func (g *restClientGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
+	overrides := &clientcmd.ConfigOverrides{Context: clientcmdapi.Context{Namespace: g.namespace}}
+	return clientcmd.NewDefaultClientConfig(*clientcmdapi.NewConfig(), overrides)
+}
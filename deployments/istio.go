@@ -0,0 +1,38 @@
+package deployments
+
+import (
+	"github.com/fuseml/fuseml/cli/helpers"
+	"github.com/fuseml/fuseml/cli/kubernetes"
+)
+
+// istioGateway exposes a service through the istio ingress gateway
+type istioGateway struct {
+	name      string
+	namespace string
+	host      string
+	service   string
+	port      int
+}
+
+func (g istioGateway) create(c *kubernetes.Cluster) error {
+	return helpers.CreateIstioIngressGateway(c, g.name, g.namespace, g.host, g.service, g.port)
+}
+
+func (g istioGateway) manifest() ([]byte, error) {
+	return helpers.IstioIngressGateway(g.name, g.namespace, g.host, g.service, g.port)
+}
+
+// delete removes the gateway and its virtual service, if they exist
+func (g istioGateway) delete(c *kubernetes.Cluster) error {
+	manifest, err := g.manifest()
+	if err != nil {
+		return err
+	}
+
+	return c.DeleteManifest(manifest, g.namespace, true)
+}
+
+// String names the gateway and its virtual service, for display
+func (g istioGateway) String() string {
+	return "istio gateway " + g.namespace + "/" + g.name + "-gateway and virtual service " + g.namespace + "/" + g.name
+}
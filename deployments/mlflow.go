@@ -3,15 +3,19 @@ package deployments
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
-	"strings"
 
 	"github.com/fuseml/fuseml/cli/helpers"
 	"github.com/fuseml/fuseml/cli/kubernetes"
 	"github.com/fuseml/fuseml/cli/paas/ui"
 	"github.com/kyokomi/emoji"
 	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/cli/values"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
 )
 
 type MLflow struct {
@@ -24,12 +28,120 @@ const (
 	mlflowNamespace    = "fuseml-workloads"
 	mlflowVersion      = "0.0.1"
 	mlflowChartFile    = "mlflow-0.0.1.tgz"
+
+	// MLflowConfigSecret holds the location and credentials of the MLflow
+	// tracking server and its artifact store, as the environment of the
+	// pipelines and the served applications
+	MLflowConfigSecret = "fuseml-mlflow"
+
+	// mlflowStorageSecret holds the credentials the chart generates for
+	// its minio storage
+	mlflowStorageSecret = "mlflow-minio"
+
+	// The placeholders naming the storage credentials in rendered manifests
+	mlflowS3AccessKeyPlaceholder    = "MLFLOW_S3_ACCESS_KEY"
+	mlflowS3SecretKeyPlaceholder    = "MLFLOW_S3_SECRET_KEY"
+	mlflowMinioAccessKeyPlaceholder = "MLFLOW_MINIO_ACCESS_KEY"
+	mlflowMinioSecretKeyPlaceholder = "MLFLOW_MINIO_SECRET_KEY"
 )
 
+// mlflowDataSelectors match the volume claims of the MLflow data: those of
+// the MySQL database, and that of the MinIO artifact store, whose subchart
+// sets only the legacy labels
+var mlflowDataSelectors = []string{
+	"app.kubernetes.io/instance=" + MLflowDeploymentID,
+	"app=minio,release=" + MLflowDeploymentID,
+}
+
 func (k *MLflow) ID() string {
 	return MLflowDeploymentID
 }
 
+// Needs returns the workloads deployment, as MLflow is installed into its
+// namespace
+func (k MLflow) Needs() []string {
+	return []string{WorkloadsDeploymentID}
+}
+
+// Options returns the backend store of the MLflow tracking server and the
+// sizes of its volumes, or the location of an existing tracking server and
+// its artifact store
+func (k MLflow) Options() kubernetes.InstallationOptions {
+	return kubernetes.InstallationOptions{
+		{
+			Name:         "backend_store",
+			Description:  "The database of the MLflow tracking server, a MySQL server or an sqlite file",
+			Type:         kubernetes.StringType,
+			Default:      "mysql",
+			Value:        "",
+			DeploymentID: MLflowDeploymentID,
+			Validators:   []kubernetes.InstallationOptionValidator{kubernetes.EnumValidator("mysql", "sqlite")},
+		},
+		{
+			Name:         "database_size",
+			Description:  "The size of the volume holding the MLflow tracking database",
+			Type:         kubernetes.StringType,
+			Default:      "2Gi",
+			Value:        "",
+			DeploymentID: MLflowDeploymentID,
+			Validators:   []kubernetes.InstallationOptionValidator{storageSizeValidator},
+		},
+		{
+			Name:         "artifacts_size",
+			Description:  "The size of the volume holding the MLflow artifacts, stored in minio",
+			Type:         kubernetes.StringType,
+			Default:      "5Gi",
+			Value:        "",
+			DeploymentID: MLflowDeploymentID,
+			Validators:   []kubernetes.InstallationOptionValidator{storageSizeValidator},
+		},
+		{
+			Name:         "tracking_uri",
+			Description:  "The URI of an existing MLflow tracking server to use instead of installing one",
+			Type:         kubernetes.StringType,
+			Default:      "",
+			Value:        "",
+			DeploymentID: MLflowDeploymentID,
+			Validators:   []kubernetes.InstallationOptionValidator{urlValidator},
+		},
+		{
+			Name:         "s3_endpoint",
+			Description:  "The URL of the S3 compatible object store of the existing MLflow tracking server",
+			Type:         kubernetes.StringType,
+			Default:      "",
+			Value:        "",
+			DeploymentID: MLflowDeploymentID,
+			Validators:   []kubernetes.InstallationOptionValidator{urlValidator},
+		},
+		{
+			Name:         "s3_bucket",
+			Description:  "The bucket the pipelines store their artifacts in, instead of the default of the existing MLflow tracking server",
+			Type:         kubernetes.StringType,
+			Default:      "",
+			Value:        "",
+			DeploymentID: MLflowDeploymentID,
+		},
+		{
+			Name:         "s3_access_key",
+			Description:  "The access key of the object store of the existing MLflow tracking server",
+			Type:         kubernetes.StringType,
+			Default:      "",
+			Value:        "",
+			DeploymentID: MLflowDeploymentID,
+			Sensitive:    true,
+		},
+		{
+			Name:         "s3_secret_key",
+			Description:  "The secret key of the object store of the existing MLflow tracking server",
+			Type:         kubernetes.StringType,
+			Default:      "",
+			Value:        "",
+			DeploymentID: MLflowDeploymentID,
+			Sensitive:    true,
+		},
+	}
+}
+
 func (k *MLflow) Backup(c *kubernetes.Cluster, ui *ui.UI, d string) error {
 	return nil
 }
@@ -42,8 +154,41 @@ func (k MLflow) Describe() string {
 	return emoji.Sprintf(":cloud:MLflow version: %s\n:clipboard:MLflow chart: %s", mlflowVersion, mlflowChartFile)
 }
 
+// Health checks the MLflow configuration, and either the release, pods and
+// endpoint of the installed tracking server, or that the existing one is
+// reachable
+func (k MLflow) Health(c *kubernetes.Cluster) []kubernetes.HealthCheck {
+	secret := c.SecretsHealth(mlflowNamespace, MLflowConfigSecret)
+	if !secret.Healthy {
+		return []kubernetes.HealthCheck{secret}
+	}
+
+	config, err := c.GetSecret(mlflowNamespace, MLflowConfigSecret)
+	if err != nil {
+		return []kubernetes.HealthCheck{kubernetes.Unhealthy(secret.Check, "%s", err.Error())}
+	}
+
+	trackingURI := string(config.Data["MLFLOW_TRACKING_URI"])
+	if trackingURI != "http://"+MLflowDeploymentID {
+		check := "tracking server"
+		client := &http.Client{Timeout: kubernetes.ReachableTimeout}
+		if err := kubernetes.Reachable(client, trackingURI); err != nil {
+			return []kubernetes.HealthCheck{secret, kubernetes.Unhealthy(check, "%s", err.Error())}
+		}
+		return []kubernetes.HealthCheck{secret, kubernetes.Healthy(check, "%s reachable", trackingURI)}
+	}
+
+	return []kubernetes.HealthCheck{
+		secret,
+		releaseHealth(c, MLflowDeploymentID, mlflowNamespace),
+		c.PodsHealth(mlflowNamespace, "app=minio"),
+		c.PodsHealth(mlflowNamespace, "app.kubernetes.io/name=mlflow"),
+		c.EndpointHealth(mlflowNamespace, "app.kubernetes.io/name=mlflow"),
+	}
+}
+
 // Delete removes MLflow from kubernetes cluster
-func (k MLflow) Delete(c *kubernetes.Cluster, ui *ui.UI) error {
+func (k MLflow) Delete(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.DeleteOptions) error {
 	ui.Note().KeeplineUnder(1).Msg("Removing MLflow...")
 
 	existsAndOwned, err := c.NamespaceExistsAndOwned(mlflowNamespace)
@@ -55,122 +200,360 @@ func (k MLflow) Delete(c *kubernetes.Cluster, ui *ui.UI) error {
 		return nil
 	}
 
-	currentdir, err := os.Getwd()
-	if err != nil {
-		return errors.New("Failed uninstalling MLflow: " + err.Error())
+	resources := []string{
+		"helm release " + mlflowNamespace + "/" + MLflowDeploymentID,
+		"secret " + mlflowNamespace + "/" + MLflowConfigSecret,
+	}
+	for _, gateway := range k.gateways("") {
+		resources = append(resources, gateway.String())
+	}
+	proceed, err := removal{
+		name:          "MLflow",
+		resources:     resources,
+		dataNamespace: mlflowNamespace,
+		dataSelectors: mlflowDataSelectors,
+	}.prepare(c, ui, options)
+	if !proceed || err != nil {
+		return err
 	}
 
 	message := "Removing helm release " + MLflowDeploymentID
-	out, err := helpers.WaitForCommandCompletion(ui, message,
+	found := true
+	_, err = helpers.WaitForCommandCompletion(ui, message,
 		func() (string, error) {
-			helmCmd := fmt.Sprintf("helm uninstall '%s' --namespace '%s'", MLflowDeploymentID, mlflowNamespace)
-			return helpers.RunProc(helmCmd, currentdir, k.Debug)
+			var err error
+			found, err = uninstallRelease(c, MLflowDeploymentID, mlflowNamespace, k.Debug)
+			return "", err
 		},
 	)
 	if err != nil {
-		if strings.Contains(out, "release: not found") {
-			ui.Exclamation().Msgf("%s helm release not found, skipping.\n", MLflowDeploymentID)
-		} else {
-			return errors.Wrapf(err, "Failed uninstalling helm release %s: %s", MLflowDeploymentID, out)
+		return errors.Wrap(err, "Failed uninstalling MLflow")
+	}
+	if !found {
+		ui.Exclamation().Msgf("%s helm release not found, skipping.\n", MLflowDeploymentID)
+	}
+
+	if err := deleteSecret(c, mlflowNamespace, MLflowConfigSecret); err != nil {
+		return errors.Wrap(err, "Failed deleting the MLflow configuration")
+	}
+
+	for _, gateway := range k.gateways("") {
+		if err := gateway.delete(c); err != nil {
+			return errors.Wrap(err, "Failed deleting the MLflow istio gateways")
 		}
 	}
 
-	message = "Deleting MLflow namespace " + MLflowDeploymentID
-	_, err = helpers.WaitForCommandCompletion(ui, message,
-		func() (string, error) {
-			return "", c.DeleteNamespace(mlflowNamespace)
+	// The namespace belongs to the workloads deployment, and holds the
+	// applications too, it is left alone
+
+	ui.Success().Msg("MLflow removed")
+
+	return nil
+}
+
+// mlflowConfig returns the configuration secret pointing to the given
+// tracking server and artifact store
+func mlflowConfig(trackingURI, s3Endpoint, s3Bucket, accessKey, secretKey string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      MLflowConfigSecret,
+			Namespace: mlflowNamespace,
 		},
-	)
+		StringData: map[string]string{
+			"MLFLOW_TRACKING_URI":    trackingURI,
+			"MLFLOW_S3_ENDPOINT_URL": s3Endpoint,
+			"MLFLOW_S3_BUCKET":       s3Bucket,
+			"AWS_ACCESS_KEY_ID":      accessKey,
+			"AWS_SECRET_ACCESS_KEY":  secretKey,
+		},
+		Type: corev1.SecretTypeOpaque,
+	}
+}
+
+// externalConfig returns the configuration pointing to the existing tracking
+// server given by the options, or nil if MLflow is to be installed
+func (k MLflow) externalConfig(options kubernetes.InstallationOptions) (*corev1.Secret, error) {
+	trackingURI, err := options.GetString("tracking_uri", MLflowDeploymentID)
+	if err != nil || trackingURI == "" {
+		return nil, err
+	}
+
+	values := map[string]string{}
+	for _, name := range []string{"s3_endpoint", "s3_bucket", "s3_access_key", "s3_secret_key"} {
+		value, err := options.GetString(name, MLflowDeploymentID)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = value
+	}
+	if values["s3_endpoint"] == "" {
+		return nil, errors.New("mlflow.s3_endpoint is required with mlflow.tracking_uri, as the pipelines store the models there")
+	}
+
+	return mlflowConfig(trackingURI, values["s3_endpoint"], values["s3_bucket"], values["s3_access_key"], values["s3_secret_key"]), nil
+}
+
+// applyExternalConfig stores the configuration pointing to the existing
+// tracking server given by the options. It returns false if none is given,
+// and MLflow is to be installed.
+func (k MLflow) applyExternalConfig(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions) (bool, error) {
+	config, err := k.externalConfig(options)
+	if err != nil || config == nil {
+		return false, err
+	}
+
+	if err := applySecret(c, config); err != nil {
+		return true, errors.Wrap(err, "failed to store the MLflow configuration")
+	}
+	ui.Success().
+		WithStringValue("Tracking server", config.StringData["MLFLOW_TRACKING_URI"]).
+		Msg("Using the existing MLflow tracking server, skipping installation")
+
+	return true, nil
+}
+
+// ApplyExternalConfig stores the configuration pointing to the existing
+// tracking server given by the options, for an installation skipping the
+// MLflow deployment. It does nothing if none is given.
+func (k MLflow) ApplyExternalConfig(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions) error {
+	_, err := k.applyExternalConfig(c, ui, options)
+	return err
+}
+
+// renderExternalConfig writes the configuration pointing to the existing
+// tracking server given by the options, with placeholders standing in for
+// its storage credentials. It returns false if none is given.
+func (k MLflow) renderExternalConfig(ui *ui.UI, options kubernetes.InstallationOptions, dir string) (bool, error) {
+	options = withPlaceholders(options, MLflowDeploymentID, map[string]string{
+		"s3_access_key": mlflowS3AccessKeyPlaceholder,
+		"s3_secret_key": mlflowS3SecretKeyPlaceholder,
+	})
+	config, err := k.externalConfig(options)
+	if err != nil || config == nil {
+		return false, err
+	}
+
+	r, err := newRenderer(dir, MLflowDeploymentID)
 	if err != nil {
-		return errors.Wrapf(err, "Failed deleting namespace %s", mlflowNamespace)
+		return true, err
+	}
+	if err := r.Objects(MLflowConfigSecret, config); err != nil {
+		return true, err
 	}
+	ui.Success().
+		WithStringValue("Directory", r.Dir).
+		WithStringValue("Placeholders", secretPlaceholder(mlflowS3AccessKeyPlaceholder)+", "+secretPlaceholder(mlflowS3SecretKeyPlaceholder)).
+		Msg("MLflow configuration rendered, without the storage credentials")
 
-	ui.Success().Msg("MLflow removed")
+	return true, nil
+}
+
+// RenderExternalConfig writes the configuration pointing to the existing
+// tracking server given by the options into dir, for an installation
+// skipping the MLflow deployment. It does nothing if none is given.
+func (k MLflow) RenderExternalConfig(ui *ui.UI, options kubernetes.InstallationOptions, dir string) error {
+	_, err := k.renderExternalConfig(ui, options, dir)
+	return err
+}
+
+// bundledConfig returns the configuration pointing to the installed tracking
+// server and its minio storage, with the given storage credentials
+func bundledConfig(accessKey, secretKey string) *corev1.Secret {
+	return mlflowConfig("http://"+MLflowDeploymentID, "http://mlflow-minio:9000", "", accessKey, secretKey)
+}
+
+// storageSecret returns the secret holding the credentials of the minio
+// storage, which the chart creates unless it is rendered
+func storageSecret(accessKey, secretKey string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mlflowStorageSecret,
+			Namespace: mlflowNamespace,
+		},
+		StringData: map[string]string{
+			"accesskey": accessKey,
+			"secretkey": secretKey,
+		},
+		Type: corev1.SecretTypeOpaque,
+	}
+}
+
+// applyBundledConfig stores the configuration pointing to the installed
+// tracking server, with the storage credentials the chart generated. They
+// are left empty if it did not generate them yet.
+func (k MLflow) applyBundledConfig(c *kubernetes.Cluster) error {
+	var accessKey, secretKey string
+	storage, err := c.Kubectl.CoreV1().Secrets(mlflowNamespace).Get(context.Background(), mlflowStorageSecret, metav1.GetOptions{})
+	if err == nil {
+		accessKey, secretKey = string(storage.Data["accesskey"]), string(storage.Data["secretkey"])
+	} else if !apierrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to read the MLflow storage credentials")
+	}
+
+	if err := applySecret(c, bundledConfig(accessKey, secretKey)); err != nil {
+		return errors.Wrap(err, "failed to store the MLflow configuration")
+	}
 
 	return nil
 }
 
-func (k MLflow) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions, upgrade bool) error {
-	action := "install"
-	if upgrade {
-		action = "upgrade"
+// gateways returns the istio gateways exposing MLflow and its minio storage
+func (k MLflow) gateways(domain string) []istioGateway {
+	return []istioGateway{
+		{name: "mlflow", namespace: mlflowNamespace, host: MLflowDeploymentID + "." + domain, service: "mlflow", port: 80},
+		{name: "minio", namespace: mlflowNamespace, host: "minio." + domain, service: "mlflow-minio", port: 9000},
+	}
+}
+
+// release returns the helm release of MLflow, from the embedded chart. The
+// ingresses are only enabled without istio. When rendering, the chart does
+// not generate the credentials of its storage, they are rendered apart. The
+// returned function removes its temporary files.
+func (k MLflow) release(options kubernetes.InstallationOptions, domain string, hasIstio bool, render bool) (helmRelease, func(), error) {
+	subdomain := MLflowDeploymentID + "." + domain
+	var files []string
+	cleanup := func() {
+		for _, file := range files {
+			os.Remove(file)
+		}
 	}
 
-	currentdir, err := os.Getwd()
+	backendStore, err := options.GetString("backend_store", MLflowDeploymentID)
 	if err != nil {
-		return err
+		return helmRelease{}, nil, err
+	}
+	databaseSize, err := options.GetString("database_size", MLflowDeploymentID)
+	if err != nil {
+		return helmRelease{}, nil, err
+	}
+	artifactsSize, err := options.GetString("artifacts_size", MLflowDeploymentID)
+	if err != nil {
+		return helmRelease{}, nil, err
+	}
+
+	class := storageClass(options)
+	minio := map[string]interface{}{
+		"persistence": map[string]interface{}{"size": artifactsSize, "storageClass": class},
+	}
+	chartValues := map[string]interface{}{
+		"mysql": map[string]interface{}{
+			"enabled": backendStore == "mysql",
+			"primary": map[string]interface{}{
+				"persistence": map[string]interface{}{"size": databaseSize, "storageClass": class},
+			},
+		},
+		"persistence": map[string]interface{}{
+			"persistentVolumeClaim": map[string]interface{}{
+				"sqlite":   map[string]interface{}{"size": databaseSize, "storageClass": class},
+				"artifact": map[string]interface{}{"storageClass": class},
+				"metrics":  map[string]interface{}{"storageClass": class},
+			},
+		},
+		"minio": minio,
+	}
+
+	if render {
+		minio["existingSecret"] = mlflowStorageSecret
+	}
+
+	if !hasIstio {
+		chartValues["ingress"] = map[string]interface{}{
+			"enabled": true,
+			"hosts":   []string{subdomain},
+			"annotations": map[string]string{
+				"kubernetes.io/ingress.class": ingressClass(options),
+			},
+			"tls": map[string]interface{}{"enabled": false},
+		}
+		minio["ingress"] = map[string]interface{}{
+			"enabled": true,
+			"hosts":   []string{"minio." + subdomain},
+			"annotations": map[string]string{
+				"kubernetes.io/ingress.class": ingressClass(options),
+			},
+		}
+	}
+
+	config, err := yaml.Marshal(chartValues)
+	if err != nil {
+		return helmRelease{}, nil, errors.Wrap(err, "failed to encode MLflow chart values")
 	}
 
 	tarPath, err := helpers.ExtractFile(mlflowChartFile)
 	if err != nil {
-		return errors.New("Failed to extract embedded file: " + tarPath + " - " + err.Error())
+		return helmRelease{}, nil, errors.New("Failed to extract embedded file: " + mlflowChartFile + " - " + err.Error())
+	}
+	files = append(files, tarPath)
+
+	configPath, err := helpers.CreateTmpFile(string(config))
+	files = append(files, configPath)
+	if err != nil {
+		cleanup()
+		return helmRelease{}, nil, err
+	}
+
+	release := helmRelease{
+		name:          MLflowDeploymentID,
+		namespace:     mlflowNamespace,
+		chart:         tarPath,
+		values:        values.Options{ValueFiles: []string{configPath}},
+		imageRegistry: imageRegistry(options),
 	}
-	defer os.Remove(tarPath)
 
+	return release, cleanup, nil
+}
+
+func (k MLflow) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions, upgrade bool) error {
 	domain, err := options.GetString("system_domain", MLflowDeploymentID)
 	if err != nil {
 		return err
 	}
-	subdomain := MLflowDeploymentID + "." + domain
-	configPath := ""
 
-	if action == "install" {
-		helmCmd := fmt.Sprintf("helm list --namespace %s --deployed -q | grep %s", mlflowNamespace, MLflowDeploymentID)
-		out, _ := helpers.RunProc(helmCmd, currentdir, k.Debug)
-		if strings.TrimSpace(out) == MLflowDeploymentID {
+	external, err := k.applyExternalConfig(c, ui, options)
+	if external || err != nil {
+		return err
+	}
+
+	if !upgrade {
+		deployed, err := releaseDeployed(c, MLflowDeploymentID, mlflowNamespace)
+		if err != nil {
+			return err
+		}
+		if deployed {
 			ui.Exclamation().Msg(MLflowDeploymentID + " already present under " + mlflowNamespace + " namespace, skipping installation")
-			return nil
+			return k.applyBundledConfig(c)
 		}
 	}
 
-	if c.HasIstio() {
+	// Volumes kept by an uninstall with --keep-data hold the database and
+	// the buckets of the artifacts
+	if err := c.ReleaseRetainedVolumes(mlflowNamespace); err != nil {
+		return err
+	}
+
+	hasIstio := c.HasIstio()
+	if hasIstio {
 		message := "Creating istio ingress gateway"
-		out, err := helpers.WaitForCommandCompletion(ui, message,
-			func() (string, error) {
-				return helpers.CreateIstioIngressGateway("mlflow", mlflowNamespace, subdomain, "mlflow", 80)
-			},
-		)
-		if err != nil {
-			return errors.Wrap(err, fmt.Sprintf("%s failed:\n%s", message, out))
+		for _, gateway := range k.gateways(domain) {
+			out, err := helpers.WaitForCommandCompletion(ui, message,
+				func() (string, error) {
+					return "", gateway.create(c)
+				},
+			)
+			if err != nil {
+				return errors.Wrap(err, fmt.Sprintf("%s failed:\n%s", message, out))
+			}
 		}
+	}
 
-		out, err = helpers.WaitForCommandCompletion(ui, message,
-			func() (string, error) {
-				return helpers.CreateIstioIngressGateway("minio", mlflowNamespace, "minio."+domain, "mlflow-minio", 9000)
-			},
-		)
-		if err != nil {
-			return errors.Wrap(err, fmt.Sprintf("%s failed:\n%s", message, out))
-		}
-	} else {
-		config := fmt.Sprintf(`
-ingress:
-    enabled: true
-    hosts:
-      - %s
-    annotations:
-      kubernetes.io/ingress.class: traefik
-    tls:
-      enabled: false
-minio:
-  ingress:
-    enabled: true
-    hosts:
-      - %s
-    annotations:
-      kubernetes.io/ingress.class: traefik
-`, subdomain, "minio."+subdomain)
-
-		configPath, err = helpers.CreateTmpFile(config)
-		if err != nil {
-			return err
-		}
-		defer os.Remove(configPath)
+	release, cleanup, err := k.release(options, domain, hasIstio, false)
+	if err != nil {
+		return err
 	}
+	defer cleanup()
 
-	helmCmd := fmt.Sprintf("helm %s %s --create-namespace --values '%s' --namespace %s %s", action, MLflowDeploymentID, configPath, mlflowNamespace, tarPath)
-	if out, err := helpers.RunProc(helmCmd, currentdir, k.Debug); err != nil {
-		return errors.New("Failed installing MLflow: " + out)
+	if err := release.install(c, upgrade, k.Debug); err != nil {
+		return errors.Wrap(err, "Failed installing MLflow")
 	}
 
 	err = c.LabelNamespace(mlflowNamespace, kubernetes.FusemlDeploymentLabelKey, kubernetes.FusemlDeploymentLabelValue)
@@ -188,10 +571,16 @@ minio:
 		return errors.Wrap(err, "failed waiting MLflow minio deployment to come up")
 	}
 
-	for _, podname := range []string{
-		"mysql",
-		"mlflow",
-	} {
+	backendStore, err := options.GetString("backend_store", MLflowDeploymentID)
+	if err != nil {
+		return err
+	}
+	podnames := []string{"mlflow"}
+	if backendStore == "mysql" {
+		podnames = []string{"mysql", "mlflow"}
+	}
+
+	for _, podname := range podnames {
 		if err := c.WaitUntilPodBySelectorExist(ui, mlflowNamespace, "app.kubernetes.io/name="+podname, k.Timeout); err != nil {
 			return errors.Wrap(err, "failed waiting MLflow "+podname+" deployment to exist")
 		}
@@ -200,11 +589,74 @@ minio:
 		}
 	}
 
+	if err := k.applyBundledConfig(c); err != nil {
+		return err
+	}
+
 	ui.Success().Msg("MLflow deployed")
 
 	return nil
 }
 
+// Render writes the MLflow gateways, chart and configuration into dir, or
+// only the configuration when using an existing tracking server. The storage
+// credentials are left out, placeholders stand in for them.
+func (k MLflow) Render(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions, dir string) error {
+	external, err := k.renderExternalConfig(ui, options, dir)
+	if external || err != nil {
+		return err
+	}
+
+	domain, err := options.GetString("system_domain", MLflowDeploymentID)
+	if err != nil {
+		return err
+	}
+
+	r, err := newRenderer(dir, MLflowDeploymentID)
+	if err != nil {
+		return err
+	}
+
+	hasIstio := c.HasIstio()
+	if hasIstio {
+		for _, gateway := range k.gateways(domain) {
+			manifest, err := gateway.manifest()
+			if err != nil {
+				return err
+			}
+			if err := r.Manifest(gateway.name+"-gateway", manifest); err != nil {
+				return err
+			}
+		}
+	}
+
+	release, cleanup, err := k.release(options, domain, hasIstio, true)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if err := release.render(r); err != nil {
+		return err
+	}
+
+	accessKey := secretPlaceholder(mlflowMinioAccessKeyPlaceholder)
+	secretKey := secretPlaceholder(mlflowMinioSecretKeyPlaceholder)
+	if err := r.Objects(mlflowStorageSecret, storageSecret(accessKey, secretKey)); err != nil {
+		return err
+	}
+	if err := r.Objects(MLflowConfigSecret, bundledConfig(accessKey, secretKey)); err != nil {
+		return err
+	}
+
+	ui.Success().
+		WithStringValue("Directory", r.Dir).
+		WithStringValue("Placeholders", accessKey+", "+secretKey).
+		Msg("MLflow rendered, without the storage credentials")
+
+	return nil
+}
+
 func (k MLflow) GetVersion() string {
 	return mlflowVersion
 }
@@ -0,0 +1,140 @@
+package deployments_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/fuseml/fuseml/cli/deployments"
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	"github.com/fuseml/fuseml/cli/paas/ui"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// mlflowOptions returns the options of MLflow, with the given values
+func mlflowOptions(values map[string]string) kubernetes.InstallationOptions {
+	options := (&MLflow{}).Options()
+	for i := range options {
+		if value, ok := values[options[i].Name]; ok {
+			options[i].Value = value
+		}
+	}
+
+	return options
+}
+
+var _ = Describe("URLValidator", func() {
+	validate := func(value string) error {
+		return URLValidator(&kubernetes.InstallationOption{Name: "tracking_uri", DeploymentID: MLflowDeploymentID, Value: value})
+	}
+
+	It("accepts an empty value", func() {
+		Expect(validate("")).To(Succeed())
+	})
+
+	It("accepts http and https URLs", func() {
+		Expect(validate("http://mlflow.example.com")).To(Succeed())
+		Expect(validate("https://mlflow.example.com:5000/path")).To(Succeed())
+	})
+
+	It("rejects other schemes and URLs without a host", func() {
+		Expect(validate("ftp://mlflow.example.com")).To(MatchError("mlflow.tracking_uri 'ftp://mlflow.example.com' is not an http(s) URL"))
+		Expect(validate("mlflow.example.com")).ToNot(Succeed())
+		Expect(validate("http://")).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("MLflow configuration", func() {
+	Describe("ExternalConfig", func() {
+		It("is nil without a tracking URI, as MLflow is installed then", func() {
+			config, err := MLflow{}.ExternalConfig(mlflowOptions(nil))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config).To(BeNil())
+		})
+
+		It("requires the object store", func() {
+			_, err := MLflow{}.ExternalConfig(mlflowOptions(map[string]string{"tracking_uri": "https://mlflow.example.com"}))
+			Expect(err).To(MatchError(ContainSubstring("mlflow.s3_endpoint is required")))
+		})
+
+		It("points to the existing tracking server and object store", func() {
+			config, err := MLflow{}.ExternalConfig(mlflowOptions(map[string]string{
+				"tracking_uri":  "https://mlflow.example.com",
+				"s3_endpoint":   "https://s3.example.com",
+				"s3_bucket":     "fuseml",
+				"s3_access_key": "key",
+				"s3_secret_key": "secret",
+			}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.Name).To(Equal(MLflowConfigSecret))
+			Expect(config.Namespace).To(Equal("fuseml-workloads"))
+			Expect(config.StringData).To(Equal(map[string]string{
+				"MLFLOW_TRACKING_URI":    "https://mlflow.example.com",
+				"MLFLOW_S3_ENDPOINT_URL": "https://s3.example.com",
+				"MLFLOW_S3_BUCKET":       "fuseml",
+				"AWS_ACCESS_KEY_ID":      "key",
+				"AWS_SECRET_ACCESS_KEY":  "secret",
+			}))
+		})
+	})
+
+	Describe("BundledConfig", func() {
+		It("points to the installed tracking server and its minio storage", func() {
+			config := BundledConfig("key", "secret")
+			Expect(config.Name).To(Equal(MLflowConfigSecret))
+			Expect(config.StringData).To(Equal(map[string]string{
+				"MLFLOW_TRACKING_URI":    "http://mlflow",
+				"MLFLOW_S3_ENDPOINT_URL": "http://mlflow-minio:9000",
+				"MLFLOW_S3_BUCKET":       "",
+				"AWS_ACCESS_KEY_ID":      "key",
+				"AWS_SECRET_ACCESS_KEY":  "secret",
+			}))
+		})
+
+		It("shares the credentials of the storage secret", func() {
+			storage := StorageSecret("key", "secret")
+			Expect(storage.Name).To(Equal("mlflow-minio"))
+			Expect(storage.StringData).To(Equal(map[string]string{"accesskey": "key", "secretkey": "secret"}))
+		})
+	})
+
+	Describe("RenderExternalConfig", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "fuseml-mlflow-test")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("writes the configuration with placeholders instead of the credentials", func() {
+			options := mlflowOptions(map[string]string{
+				"tracking_uri":  "https://mlflow.example.com",
+				"s3_endpoint":   "https://s3.example.com",
+				"s3_access_key": "key",
+				"s3_secret_key": "secret",
+			})
+			Expect(MLflow{}.RenderExternalConfig(ui.NewUI().WithOutput(&bytes.Buffer{}), options, dir)).To(Succeed())
+
+			manifest, err := ioutil.ReadFile(filepath.Join(dir, MLflowDeploymentID, "01-fuseml-mlflow.yaml"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(manifest)).To(ContainSubstring("AWS_ACCESS_KEY_ID: ${MLFLOW_S3_ACCESS_KEY}"))
+			Expect(string(manifest)).To(ContainSubstring("AWS_SECRET_ACCESS_KEY: ${MLFLOW_S3_SECRET_KEY}"))
+			Expect(string(manifest)).ToNot(ContainSubstring("secret\n"))
+		})
+
+		It("writes nothing without a tracking URI", func() {
+			Expect(MLflow{}.RenderExternalConfig(ui.NewUI().WithOutput(&bytes.Buffer{}), mlflowOptions(nil), dir)).To(Succeed())
+
+			files, err := ioutil.ReadDir(dir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(files).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,15 @@
+package deployments
+
+// Namespaces returns the namespaces the deployments create. Namespaces which
+// exist already, without belonging to FuseML, are skipped by them.
+func Namespaces() []string {
+	return []string{
+		TraefikDeploymentID,
+		QuarksDeploymentID,
+		WorkloadsDeploymentID,
+		"app-ingress",
+		GiteaDeploymentID,
+		RegistryDeploymentID,
+		tektonNamespace,
+	}
+}
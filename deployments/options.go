@@ -0,0 +1,70 @@
+package deployments
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// storageSizeValidator requires the option to be a storage size, as used
+// by persistent volume claims
+func storageSizeValidator(o *kubernetes.InstallationOption) error {
+	if _, err := resource.ParseQuantity(o.Value.(string)); err != nil {
+		return errors.Errorf("%s '%s' is not a storage size, e.g. 10Gi", o.QualifiedName(), o.Value)
+	}
+
+	return nil
+}
+
+// durationValidator requires the option to be a duration, e.g. 1h30m
+func durationValidator(o *kubernetes.InstallationOption) error {
+	if _, err := time.ParseDuration(o.Value.(string)); err != nil {
+		return errors.Errorf("%s '%s' is not a duration, e.g. 1h30m", o.QualifiedName(), o.Value)
+	}
+
+	return nil
+}
+
+// urlValidator requires the option to be empty or an http(s) URL
+func urlValidator(o *kubernetes.InstallationOption) error {
+	value := o.Value.(string)
+	if value == "" {
+		return nil
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.Errorf("%s '%s' is not an http(s) URL", o.QualifiedName(), value)
+	}
+
+	return nil
+}
+
+// DefaultIngressClass is the class of the ingresses, served by the Traefik
+// FuseML installs
+const DefaultIngressClass = "traefik"
+
+// storageClass returns the storage class of the volumes, empty for the
+// default storage class of the cluster
+func storageClass(options kubernetes.InstallationOptions) string {
+	class, err := options.GetString("storage_class", "")
+	if err != nil {
+		return ""
+	}
+
+	return class
+}
+
+// ingressClass returns the class of the ingresses, that of the ingress
+// controller of the platform, or Traefik
+func ingressClass(options kubernetes.InstallationOptions) string {
+	class, err := options.GetString("ingress_class", "")
+	if err != nil || class == "" {
+		return DefaultIngressClass
+	}
+
+	return class
+}
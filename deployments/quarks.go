@@ -2,15 +2,13 @@ package deployments
 
 import (
 	"context"
-	"fmt"
-	"os"
-	"strings"
 
 	"github.com/fuseml/fuseml/cli/helpers"
 	"github.com/fuseml/fuseml/cli/kubernetes"
 	"github.com/fuseml/fuseml/cli/paas/ui"
 	"github.com/kyokomi/emoji"
 	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/cli/values"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -29,6 +27,16 @@ func (k *Quarks) ID() string {
 	return QuarksDeploymentID
 }
 
+// Needs returns no deployments
+func (k Quarks) Needs() []string {
+	return nil
+}
+
+// Options returns no options, Quarks only uses the shared ones
+func (k Quarks) Options() kubernetes.InstallationOptions {
+	return kubernetes.InstallationOptions{}
+}
+
 func (k *Quarks) Backup(c *kubernetes.Cluster, ui *ui.UI, d string) error {
 	return nil
 }
@@ -41,8 +49,22 @@ func (k Quarks) Describe() string {
 	return emoji.Sprintf(":cloud:Quarks version: %s\n:clipboard:Quarks chart: %s", quarksVersion, quarksChartURL)
 }
 
+// Health checks the Quarks release and its quarks-secret operator
+func (k Quarks) Health(c *kubernetes.Cluster) []kubernetes.HealthCheck {
+	namespace := c.NamespaceHealth(QuarksDeploymentID)
+	if !namespace.Healthy {
+		return []kubernetes.HealthCheck{namespace}
+	}
+
+	return []kubernetes.HealthCheck{
+		namespace,
+		releaseHealth(c, "quarks", QuarksDeploymentID),
+		c.PodsHealth(QuarksDeploymentID, "name=quarks-secret"),
+	}
+}
+
 // Delete removes Quarks from kubernetes cluster
-func (k Quarks) Delete(c *kubernetes.Cluster, ui *ui.UI) error {
+func (k Quarks) Delete(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.DeleteOptions) error {
 	ui.Note().KeeplineUnder(1).Msg("Removing Quarks...")
 
 	existsAndOwned, err := c.NamespaceExistsAndOwned(QuarksDeploymentID)
@@ -54,32 +76,39 @@ func (k Quarks) Delete(c *kubernetes.Cluster, ui *ui.UI) error {
 		return nil
 	}
 
-	currentdir, err := os.Getwd()
-	if err != nil {
-		return errors.New("Failed uninstalling Quarks: " + err.Error())
+	proceed, err := removal{
+		name: "Quarks",
+		resources: []string{
+			"helm release " + QuarksDeploymentID + "/quarks",
+			"custom resource definition quarkssecrets.quarks.cloudfoundry.org",
+			"namespace " + QuarksDeploymentID,
+		},
+	}.prepare(c, ui, options)
+	if !proceed || err != nil {
+		return err
 	}
 
 	message := "Removing helm release " + QuarksDeploymentID
-	out, err := helpers.WaitForCommandCompletion(ui, message,
+	found := true
+	_, err = helpers.WaitForCommandCompletion(ui, message,
 		func() (string, error) {
-			helmCmd := fmt.Sprintf("helm uninstall quarks --namespace %s", QuarksDeploymentID)
-			return helpers.RunProc(helmCmd, currentdir, k.Debug)
+			var err error
+			found, err = uninstallRelease(c, "quarks", QuarksDeploymentID, k.Debug)
+			return "", err
 		},
 	)
 	if err != nil {
-		if strings.Contains(out, "release: not found") {
-			ui.Exclamation().Msgf("%s helm release not found, skipping.\n", QuarksDeploymentID)
-		} else {
-			return errors.New("Failed uninstalling Quarks: " + out)
-		}
+		return errors.Wrap(err, "Failed uninstalling Quarks")
+	}
+	if !found {
+		ui.Exclamation().Msgf("%s helm release not found, skipping.\n", QuarksDeploymentID)
 	}
 
 	for _, crd := range []string{
 		"quarkssecrets.quarks.cloudfoundry.org",
 	} {
-		out, err := helpers.Kubectl("delete crds --ignore-not-found=true " + crd)
-		if err != nil {
-			return errors.Wrap(err, fmt.Sprintf("Deleting quarks CRD failed:\n%s", out))
+		if err := c.DeleteCRD(crd); err != nil {
+			return errors.Wrap(err, "Deleting quarks CRD failed")
 		}
 	}
 
@@ -98,22 +127,27 @@ func (k Quarks) Delete(c *kubernetes.Cluster, ui *ui.UI) error {
 	return nil
 }
 
-func (k Quarks) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions, upgrade bool) error {
-	action := "install"
-	if upgrade {
-		action = "upgrade"
-	}
+// release returns the helm release of Quarks
+func (k Quarks) release(options kubernetes.InstallationOptions) (helmRelease, error) {
+	// Setup Quarks helm values
+	vals := values.Options{Values: []string{"global.monitoredID=quarks-secret"}}
 
-	currentdir, _ := os.Getwd()
+	chart, err := chartSource(options, quarksChartURL)
+	if err != nil {
+		return helmRelease{}, err
+	}
 
-	// Setup Quarks helm values
-	var helmArgs []string
+	return helmRelease{name: "quarks", namespace: QuarksDeploymentID, chart: chart, values: vals, imageRegistry: imageRegistry(options)}, nil
+}
 
-	helmArgs = append(helmArgs, "--set global.monitoredID=quarks-secret")
+func (k Quarks) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions, upgrade bool) error {
+	release, err := k.release(options)
+	if err != nil {
+		return err
+	}
 
-	helmCmd := fmt.Sprintf("helm %s quarks --create-namespace --namespace %s %s %s", action, QuarksDeploymentID, quarksChartURL, strings.Join(helmArgs, " "))
-	if _, err := helpers.RunProc(helmCmd, currentdir, k.Debug); err != nil {
-		return errors.New("Failed installing Quarks")
+	if err := release.install(c, upgrade, k.Debug); err != nil {
+		return errors.Wrap(err, "Failed installing Quarks")
 	}
 
 	if err := c.WaitUntilPodBySelectorExist(ui, QuarksDeploymentID, "name=quarks-secret", k.Timeout); err != nil {
@@ -123,7 +157,7 @@ func (k Quarks) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.Insta
 		return errors.Wrap(err, "failed waiting Quarks quarks-secret deployment to come up")
 	}
 
-	err := c.LabelNamespace(QuarksDeploymentID, kubernetes.FusemlDeploymentLabelKey, kubernetes.FusemlDeploymentLabelValue)
+	err = c.LabelNamespace(QuarksDeploymentID, kubernetes.FusemlDeploymentLabelKey, kubernetes.FusemlDeploymentLabelValue)
 	if err != nil {
 		return err
 	}
@@ -133,6 +167,31 @@ func (k Quarks) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.Insta
 	return nil
 }
 
+// Render writes the Quarks namespace and chart into dir
+func (k Quarks) Render(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions, dir string) error {
+	r, err := newRenderer(dir, QuarksDeploymentID)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Objects("namespace", fusemlNamespace(QuarksDeploymentID, nil)); err != nil {
+		return err
+	}
+
+	release, err := k.release(options)
+	if err != nil {
+		return err
+	}
+
+	if err := release.render(r); err != nil {
+		return err
+	}
+
+	ui.Success().WithStringValue("Directory", r.Dir).Msg("Quarks rendered")
+
+	return nil
+}
+
 func (k Quarks) GetVersion() string {
 	return quarksVersion
 }
@@ -4,13 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/fuseml/fuseml/cli/helpers"
 	"github.com/fuseml/fuseml/cli/kubernetes"
 	"github.com/fuseml/fuseml/cli/paas/ui"
 	"github.com/kyokomi/emoji"
 	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/cli/values"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -22,14 +22,54 @@ type Registry struct {
 
 const (
 	RegistryDeploymentID = "fuseml-registry"
-	registryVersion      = "0.1.0"
-	registryChartFile    = "container-registry-0.1.0.tgz"
+	registryVersion      = "0.1.2"
+	registryChartFile    = "container-registry-0.1.2.tgz"
+
+	// RegistryUsername and RegistryPassword match the htpasswd entry of the
+	// registry chart
+	RegistryUsername = "admin"
+	RegistryPassword = "password"
+	// RegistryPodSelector selects the pod running the registry and its proxy
+	RegistryPodSelector = "app.kubernetes.io/name=container-registry"
 )
 
+// registryTLSSecrets hold the CA and the certificate of the registry,
+// generated by quarks
+var registryTLSSecrets = []string{"registry-tls-self-ca", "registry-tls-self"}
+
 func (k *Registry) ID() string {
 	return RegistryDeploymentID
 }
 
+// Needs returns Quarks, which generates the registry certificates, and the
+// workloads deployment, where they are copied to
+func (k Registry) Needs() []string {
+	return []string{QuarksDeploymentID, WorkloadsDeploymentID}
+}
+
+// Options returns the persistence settings of the registry
+func (k Registry) Options() kubernetes.InstallationOptions {
+	return kubernetes.InstallationOptions{
+		{
+			Name:         "persistence",
+			Description:  "Keep the images in a persistent volume, instead of losing them when the registry pod restarts",
+			Type:         kubernetes.BooleanType,
+			Default:      false,
+			Value:        false,
+			DeploymentID: RegistryDeploymentID,
+		},
+		{
+			Name:         "storage_size",
+			Description:  "The size of the persistent volume holding the images",
+			Type:         kubernetes.StringType,
+			Default:      "10Gi",
+			Value:        "",
+			DeploymentID: RegistryDeploymentID,
+			Validators:   []kubernetes.InstallationOptionValidator{storageSizeValidator},
+		},
+	}
+}
+
 func (k *Registry) Backup(c *kubernetes.Cluster, ui *ui.UI, d string) error {
 	return nil
 }
@@ -42,8 +82,25 @@ func (k Registry) Describe() string {
 	return emoji.Sprintf(":cloud:Registry version: %s\n:clipboard:Registry chart: %s", registryVersion, registryChartFile)
 }
 
+// Health checks the registry release and pods, and its TLS secrets, which
+// are generated in its namespace and copied to the workloads namespace
+func (k Registry) Health(c *kubernetes.Cluster) []kubernetes.HealthCheck {
+	namespace := c.NamespaceHealth(RegistryDeploymentID)
+	if !namespace.Healthy {
+		return []kubernetes.HealthCheck{namespace}
+	}
+
+	return []kubernetes.HealthCheck{
+		namespace,
+		releaseHealth(c, RegistryDeploymentID, RegistryDeploymentID),
+		c.PodsHealth(RegistryDeploymentID, RegistryPodSelector),
+		c.SecretsHealth(RegistryDeploymentID, registryTLSSecrets...),
+		c.SecretsHealth(WorkloadsDeploymentID, registryTLSSecrets...),
+	}
+}
+
 // Delete removes Registry from kubernetes cluster
-func (k Registry) Delete(c *kubernetes.Cluster, ui *ui.UI) error {
+func (k Registry) Delete(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.DeleteOptions) error {
 	ui.Note().KeeplineUnder(1).Msg("Removing Registry...")
 
 	existsAndOwned, err := c.NamespaceExistsAndOwned(RegistryDeploymentID)
@@ -55,24 +112,32 @@ func (k Registry) Delete(c *kubernetes.Cluster, ui *ui.UI) error {
 		return nil
 	}
 
-	currentdir, err := os.Getwd()
-	if err != nil {
-		return errors.New("Failed uninstalling Registry: " + err.Error())
+	proceed, err := removal{
+		name: "Registry",
+		resources: []string{
+			"helm release " + RegistryDeploymentID + "/" + RegistryDeploymentID,
+			"namespace " + RegistryDeploymentID,
+		},
+		dataNamespace: RegistryDeploymentID,
+	}.prepare(c, ui, options)
+	if !proceed || err != nil {
+		return err
 	}
 
 	message := "Removing helm release " + RegistryDeploymentID
-	out, err := helpers.WaitForCommandCompletion(ui, message,
+	found := true
+	_, err = helpers.WaitForCommandCompletion(ui, message,
 		func() (string, error) {
-			helmCmd := fmt.Sprintf("helm uninstall '%s' --namespace '%s'", RegistryDeploymentID, RegistryDeploymentID)
-			return helpers.RunProc(helmCmd, currentdir, k.Debug)
+			var err error
+			found, err = uninstallRelease(c, RegistryDeploymentID, RegistryDeploymentID, k.Debug)
+			return "", err
 		},
 	)
 	if err != nil {
-		if strings.Contains(out, "release: not found") {
-			ui.Exclamation().Msgf("%s helm release not found, skipping.\n", RegistryDeploymentID)
-		} else {
-			return errors.Wrapf(err, "Failed uninstalling helm release %s: %s", RegistryDeploymentID, out)
-		}
+		return errors.Wrap(err, "Failed uninstalling Registry")
+	}
+	if !found {
+		ui.Exclamation().Msgf("%s helm release not found, skipping.\n", RegistryDeploymentID)
 	}
 
 	message = "Deleting Registry namespace " + RegistryDeploymentID
@@ -90,49 +155,77 @@ func (k Registry) Delete(c *kubernetes.Cluster, ui *ui.UI) error {
 	return nil
 }
 
-func (k Registry) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions, upgrade bool) error {
-	action := "install"
-	if upgrade {
-		action = "upgrade"
+// release returns the helm release of the registry, from the embedded chart.
+// The returned function removes its temporary files.
+func (k Registry) release(options kubernetes.InstallationOptions) (helmRelease, func(), error) {
+	tarPath, err := helpers.ExtractFile(registryChartFile)
+	if err != nil {
+		return helmRelease{}, nil, errors.New("Failed to extract embedded file: " + registryChartFile + " - " + err.Error())
 	}
 
-	currentdir, err := os.Getwd()
+	persistence, err := options.GetBool("persistence", RegistryDeploymentID)
 	if err != nil {
-		return err
+		os.Remove(tarPath)
+		return helmRelease{}, nil, err
+	}
+	storageSize, err := options.GetString("storage_size", RegistryDeploymentID)
+	if err != nil {
+		os.Remove(tarPath)
+		return helmRelease{}, nil, err
+	}
+
+	vals := values.Options{
+		Values: []string{fmt.Sprintf("persistence.enabled=%t", persistence)},
+		StringValues: []string{
+			"persistence.size=" + storageSize,
+			"persistence.storageClass=" + storageClass(options),
+		},
 	}
 
-	if action == "install" {
-		helmCmd := fmt.Sprintf("helm list --namespace %s --deployed -q | grep %s", RegistryDeploymentID, RegistryDeploymentID)
-		out, _ := helpers.RunProc(helmCmd, currentdir, k.Debug)
-		if strings.TrimSpace(out) == RegistryDeploymentID {
+	release := helmRelease{name: RegistryDeploymentID, namespace: RegistryDeploymentID, chart: tarPath, values: vals, imageRegistry: imageRegistry(options)}
+
+	return release, func() { os.Remove(tarPath) }, nil
+}
+
+func (k Registry) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions, upgrade bool) error {
+	if !upgrade {
+		deployed, err := releaseDeployed(c, RegistryDeploymentID, RegistryDeploymentID)
+		if err != nil {
+			return err
+		}
+		if deployed {
 			ui.Exclamation().Msg(RegistryDeploymentID + " already present under " + RegistryDeploymentID + " namespace, skipping installation")
 			return nil
 		}
 	}
 
-	if err = createQuarksMonitoredNamespace(c, RegistryDeploymentID); err != nil {
+	// Volumes kept by an uninstall with --keep-data hold the images
+	if err := c.ReleaseRetainedVolumes(RegistryDeploymentID); err != nil {
 		return err
 	}
 
-	tarPath, err := helpers.ExtractFile(registryChartFile)
+	if err := createQuarksMonitoredNamespace(c, RegistryDeploymentID); err != nil {
+		return err
+	}
+
+	release, cleanup, err := k.release(options)
 	if err != nil {
-		return errors.New("Failed to extract embedded file: " + tarPath + " - " + err.Error())
+		return err
 	}
-	defer os.Remove(tarPath)
+	defer cleanup()
 
-	helmCmd := fmt.Sprintf("helm %s %s --create-namespace --namespace %s %s", action, RegistryDeploymentID, RegistryDeploymentID, tarPath)
-	if out, err := helpers.RunProc(helmCmd, currentdir, k.Debug); err != nil {
-		return errors.New("Failed installing Registry: " + out)
+	if err := release.install(c, upgrade, k.Debug); err != nil {
+		return errors.Wrap(err, "Failed installing Registry")
 	}
 
 	err = c.LabelNamespace(RegistryDeploymentID, kubernetes.FusemlDeploymentLabelKey, kubernetes.FusemlDeploymentLabelValue)
 	if err != nil {
 		return err
 	}
-	if err := c.WaitUntilPodBySelectorExist(ui, RegistryDeploymentID, "app.kubernetes.io/name=container-registry", 180); err != nil {
+	if err := c.WaitUntilPodBySelectorExist(ui, RegistryDeploymentID, RegistryPodSelector, 180); err != nil {
 		return errors.Wrap(err, "failed waiting Registry deployment to come up")
 	}
-	if err := c.WaitForPodBySelectorRunning(ui, RegistryDeploymentID, "app.kubernetes.io/name=container-registry", 180); err != nil {
+	if err := c.WaitForPodBySelectorRunning(ui, RegistryDeploymentID, RegistryPodSelector, 180); err != nil {
 		return errors.Wrap(err, "failed waiting Registry deployment to come up")
 	}
 
@@ -141,6 +234,35 @@ func (k Registry) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.Ins
 	return nil
 }
 
+// Render writes the registry namespace and chart into dir
+func (k Registry) Render(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions, dir string) error {
+	r, err := newRenderer(dir, RegistryDeploymentID)
+	if err != nil {
+		return err
+	}
+
+	namespace := fusemlNamespace(RegistryDeploymentID, map[string]string{
+		"quarks.cloudfoundry.org/monitored": "quarks-secret",
+	})
+	if err := r.Objects("namespace", namespace); err != nil {
+		return err
+	}
+
+	release, cleanup, err := k.release(options)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if err := release.render(r); err != nil {
+		return err
+	}
+
+	ui.Success().WithStringValue("Directory", r.Dir).Msg("Registry rendered")
+
+	return nil
+}
+
 func (k Registry) GetVersion() string {
 	return registryVersion
 }
@@ -0,0 +1,82 @@
+package deployments
+
+import (
+	"path/filepath"
+
+	"github.com/fuseml/fuseml/cli/helpers"
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	"github.com/fuseml/fuseml/cli/paas/bundle"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newRenderer returns the renderer for a deployment, writing into its own
+// directory under dir
+func newRenderer(dir, deploymentID string) (*kubernetes.Renderer, error) {
+	return kubernetes.NewRenderer(filepath.Join(dir, deploymentID))
+}
+
+// fusemlNamespace returns a namespace labeled as owned by fuseml, as the
+// deployments leave their namespaces once installed
+func fusemlNamespace(name string, labels map[string]string) *corev1.Namespace {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				kubernetes.FusemlDeploymentLabelKey: kubernetes.FusemlDeploymentLabelValue,
+			},
+		},
+	}
+	for key, value := range labels {
+		namespace.Labels[key] = value
+	}
+
+	return namespace
+}
+
+// renderEmbeddedYaml writes an embedded manifest, with its images pointed
+// to the image registry mirror, if one is configured
+func renderEmbeddedYaml(r *kubernetes.Renderer, options kubernetes.InstallationOptions, name, yamlPath string) error {
+	manifest, err := helpers.ReadEmbeddedFile(yamlPath)
+	if err != nil {
+		return errors.New("Failed to extract embedded file: " + yamlPath + " - " + err.Error())
+	}
+
+	return renderManifest(r, options, name, manifest)
+}
+
+// renderManifest writes the manifest, with its images pointed to the image
+// registry mirror, if one is configured
+func renderManifest(r *kubernetes.Renderer, options kubernetes.InstallationOptions, name string, manifest []byte) error {
+	if mirror := imageRegistry(options); mirror != "" {
+		var err error
+		manifest, err = bundle.RewriteImages(manifest, mirror)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.Manifest(name, manifest)
+}
+
+// secretPlaceholder returns the placeholder standing in for a secret value
+// in the rendered manifests, which are meant to be reviewed and committed.
+// It is substituted when applying them, e.g. by envsubst.
+func secretPlaceholder(name string) string {
+	return "${" + name + "}"
+}
+
+// withPlaceholders returns a copy of the options, with the secret options
+// of the deployment set to their placeholders, named by placeholders
+func withPlaceholders(options kubernetes.InstallationOptions, deploymentID string, placeholders map[string]string) kubernetes.InstallationOptions {
+	result := make(kubernetes.InstallationOptions, len(options))
+	copy(result, options)
+	for i, opt := range result {
+		if name, ok := placeholders[opt.Name]; ok && opt.DeploymentID == deploymentID {
+			result[i].Value = secretPlaceholder(name)
+		}
+	}
+
+	return result
+}
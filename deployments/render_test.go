@@ -0,0 +1,63 @@
+package deployments_test
+
+import (
+	. "github.com/fuseml/fuseml/cli/deployments"
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WithPlaceholders", func() {
+	var options kubernetes.InstallationOptions
+
+	BeforeEach(func() {
+		options = (&Gitea{}).Options()
+		for i := range options {
+			switch options[i].Name {
+			case "admin_username":
+				options[i].Value = "dev"
+			case "admin_password":
+				options[i].Value = "s3cr3t"
+			}
+		}
+	})
+
+	It("replaces the secret options of the deployment by their placeholders", func() {
+		rendered := WithPlaceholders(options, GiteaDeploymentID, map[string]string{"admin_password": "GITEA_ADMIN_PASSWORD"})
+
+		password, err := rendered.GetString("admin_password", GiteaDeploymentID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(password).To(Equal("${GITEA_ADMIN_PASSWORD}"))
+
+		username, err := rendered.GetString("admin_username", GiteaDeploymentID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(username).To(Equal("dev"))
+	})
+
+	It("leaves the given options alone", func() {
+		WithPlaceholders(options, GiteaDeploymentID, map[string]string{"admin_password": "GITEA_ADMIN_PASSWORD"})
+
+		password, err := options.GetString("admin_password", GiteaDeploymentID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(password).To(Equal("s3cr3t"))
+	})
+
+	It("ignores the options of other deployments", func() {
+		rendered := WithPlaceholders(options, MLflowDeploymentID, map[string]string{"admin_password": "GITEA_ADMIN_PASSWORD"})
+
+		password, err := rendered.GetString("admin_password", GiteaDeploymentID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(password).To(Equal("s3cr3t"))
+	})
+
+	It("keeps the password out of the rendered Gitea credentials", func() {
+		rendered := WithPlaceholders(options, GiteaDeploymentID, map[string]string{"admin_password": "GITEA_ADMIN_PASSWORD"})
+
+		secret, err := Gitea{}.CredsSecret(rendered)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secret.StringData).To(Equal(map[string]string{
+			"username": "dev",
+			"password": "${GITEA_ADMIN_PASSWORD}",
+		}))
+	})
+})
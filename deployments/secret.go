@@ -0,0 +1,31 @@
+package deployments
+
+import (
+	"context"
+
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// applySecret creates the secret, or updates it if it exists already
+func applySecret(c *kubernetes.Cluster, secret *corev1.Secret) error {
+	secrets := c.Kubectl.CoreV1().Secrets(secret.Namespace)
+	_, err := secrets.Create(context.Background(), secret, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		_, err = secrets.Update(context.Background(), secret, metav1.UpdateOptions{})
+	}
+
+	return err
+}
+
+// deleteSecret removes the secret, if it exists
+func deleteSecret(c *kubernetes.Cluster, namespace, name string) error {
+	err := c.Kubectl.CoreV1().Secrets(namespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
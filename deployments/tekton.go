@@ -3,10 +3,8 @@ package deployments
 import (
 	"context"
 	"fmt"
-	"io/ioutil"
-	"os"
 	"regexp"
-	"strconv"
+	"strings"
 	"time"
 
 	"github.com/fuseml/fuseml/cli/helpers"
@@ -14,9 +12,8 @@ import (
 	"github.com/fuseml/fuseml/cli/paas/ui"
 	"github.com/kyokomi/emoji"
 	"github.com/pkg/errors"
-	"k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 type Tekton struct {
@@ -41,6 +38,37 @@ func (k *Tekton) ID() string {
 	return TektonDeploymentID
 }
 
+// Needs returns the workloads deployment, where the FuseML pipelines run,
+// and the registry, whose certificates kaniko trusts
+func (k Tekton) Needs() []string {
+	return []string{WorkloadsDeploymentID, RegistryDeploymentID}
+}
+
+// Options returns the timeout and workspace size of the pipeline runs
+// building and training applications
+func (k Tekton) Options() kubernetes.InstallationOptions {
+	return kubernetes.InstallationOptions{
+		{
+			Name:         "pipeline_timeout",
+			Description:  "How long an application pipeline may run before it is cancelled, e.g. 1h30m",
+			Type:         kubernetes.StringType,
+			Default:      "1h",
+			Value:        "",
+			DeploymentID: TektonDeploymentID,
+			Validators:   []kubernetes.InstallationOptionValidator{durationValidator},
+		},
+		{
+			Name:         "workspace_size",
+			Description:  "The size of the volume holding the sources of each application pipeline run",
+			Type:         kubernetes.StringType,
+			Default:      "2Gi",
+			Value:        "",
+			DeploymentID: TektonDeploymentID,
+			Validators:   []kubernetes.InstallationOptionValidator{storageSizeValidator},
+		},
+	}
+}
+
 func (k *Tekton) Backup(c *kubernetes.Cluster, ui *ui.UI, d string) error {
 	return nil
 }
@@ -54,8 +82,27 @@ func (k Tekton) Describe() string {
 		tektonPipelineYamlPath, tektonDashboardYamlPath, tektonTriggersYamlPath)
 }
 
+// Health checks the Tekton pods, the FuseML event listener and the endpoint
+// of the dashboard
+func (k Tekton) Health(c *kubernetes.Cluster) []kubernetes.HealthCheck {
+	namespace := c.NamespaceHealth(tektonNamespace)
+	if !namespace.Healthy {
+		return []kubernetes.HealthCheck{namespace}
+	}
+
+	checks := []kubernetes.HealthCheck{namespace}
+	for _, component := range []string{"pipelines", "triggers", "dashboard"} {
+		checks = append(checks, c.PodsHealth(tektonNamespace, "app.kubernetes.io/part-of=tekton-"+component))
+	}
+
+	return append(checks,
+		c.PodsHealth(WorkloadsDeploymentID, "eventlistener=mlflow-listener,app.kubernetes.io/part-of=Triggers"),
+		c.EndpointHealth(tektonNamespace, ""),
+	)
+}
+
 // Delete removes Tekton from kubernetes cluster
-func (k Tekton) Delete(c *kubernetes.Cluster, ui *ui.UI) error {
+func (k Tekton) Delete(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.DeleteOptions) error {
 	ui.Note().KeeplineUnder(1).Msg("Removing Tekton...")
 
 	existsAndOwned, err := c.NamespaceExistsAndOwned(tektonNamespace)
@@ -67,17 +114,36 @@ func (k Tekton) Delete(c *kubernetes.Cluster, ui *ui.UI) error {
 		return nil
 	}
 
-	if out, err := helpers.KubectlDeleteEmbeddedYaml(tektonDashboardYamlPath, true); err != nil {
-		return errors.Wrap(err, fmt.Sprintf("Deleting %s failed:\n%s", tektonDashboardYamlPath, out))
+	proceed, err := removal{
+		name: "Tekton",
+		resources: []string{
+			"manifest " + tektonDashboardYamlPath,
+			"manifest " + tektonAdminRoleYamlPath,
+			"manifest " + tektonTriggersYamlPath,
+			"manifest " + tektonPipelineYamlPath,
+			tektonGateway("").String(),
+			"namespace " + tektonNamespace,
+		},
+	}.prepare(c, ui, options)
+	if !proceed || err != nil {
+		return err
 	}
-	if out, err := helpers.KubectlDeleteEmbeddedYaml(tektonAdminRoleYamlPath, true); err != nil {
-		return errors.Wrap(err, fmt.Sprintf("Deleting %s failed:\n%s", tektonAdminRoleYamlPath, out))
+
+	if err := tektonGateway("").delete(c); err != nil {
+		return errors.Wrap(err, "Deleting the Tekton istio gateway failed")
 	}
-	if out, err := helpers.KubectlDeleteEmbeddedYaml(tektonTriggersYamlPath, true); err != nil {
-		return errors.Wrap(err, fmt.Sprintf("Deleting %s failed:\n%s", tektonTriggersYamlPath, out))
+
+	if err := helpers.DeleteEmbeddedYaml(c, tektonDashboardYamlPath, true); err != nil {
+		return errors.Wrapf(err, "Deleting %s failed", tektonDashboardYamlPath)
 	}
-	if out, err := helpers.KubectlDeleteEmbeddedYaml(tektonPipelineYamlPath, true); err != nil {
-		return errors.Wrap(err, fmt.Sprintf("Deleting %s failed:\n%s", tektonPipelineYamlPath, out))
+	if err := helpers.DeleteEmbeddedYaml(c, tektonAdminRoleYamlPath, true); err != nil {
+		return errors.Wrapf(err, "Deleting %s failed", tektonAdminRoleYamlPath)
+	}
+	if err := helpers.DeleteEmbeddedYaml(c, tektonTriggersYamlPath, true); err != nil {
+		return errors.Wrapf(err, "Deleting %s failed", tektonTriggersYamlPath)
+	}
+	if err := helpers.DeleteEmbeddedYaml(c, tektonPipelineYamlPath, true); err != nil {
+		return errors.Wrapf(err, "Deleting %s failed", tektonPipelineYamlPath)
 	}
 
 	message := "Deleting Tekton namespace " + tektonNamespace
@@ -96,17 +162,17 @@ func (k Tekton) Delete(c *kubernetes.Cluster, ui *ui.UI) error {
 }
 
 func (k Tekton) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions, upgrade bool) error {
-	if out, err := helpers.KubectlApplyEmbeddedYaml(tektonAdminRoleYamlPath); err != nil {
-		return errors.Wrap(err, fmt.Sprintf("Installing %s failed:\n%s", tektonAdminRoleYamlPath, out))
+	if err := applyEmbeddedYaml(c, options, tektonAdminRoleYamlPath, tektonNamespace); err != nil {
+		return errors.Wrapf(err, "Installing %s failed", tektonAdminRoleYamlPath)
 	}
-	if out, err := helpers.KubectlApplyEmbeddedYaml(tektonPipelineYamlPath); err != nil {
-		return errors.Wrap(err, fmt.Sprintf("Installing %s failed:\n%s", tektonPipelineYamlPath, out))
+	if err := applyEmbeddedYaml(c, options, tektonPipelineYamlPath, tektonNamespace); err != nil {
+		return errors.Wrapf(err, "Installing %s failed", tektonPipelineYamlPath)
 	}
-	if out, err := helpers.KubectlApplyEmbeddedYaml(tektonTriggersYamlPath); err != nil {
-		return errors.Wrap(err, fmt.Sprintf("Installing %s failed:\n%s", tektonTriggersYamlPath, out))
+	if err := applyEmbeddedYaml(c, options, tektonTriggersYamlPath, tektonNamespace); err != nil {
+		return errors.Wrapf(err, "Installing %s failed", tektonTriggersYamlPath)
 	}
-	if out, err := helpers.KubectlApplyEmbeddedYaml(tektonDashboardYamlPath); err != nil {
-		return errors.Wrap(err, fmt.Sprintf("Installing %s failed:\n%s", tektonDashboardYamlPath, out))
+	if err := applyEmbeddedYaml(c, options, tektonDashboardYamlPath, tektonNamespace); err != nil {
+		return errors.Wrapf(err, "Installing %s failed", tektonDashboardYamlPath)
 	}
 
 	err := c.LabelNamespace(tektonNamespace, kubernetes.FusemlDeploymentLabelKey, kubernetes.FusemlDeploymentLabelValue)
@@ -130,70 +196,71 @@ func (k Tekton) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.Insta
 		"triggertemplates.triggers.tekton.dev",
 	} {
 		message := fmt.Sprintf("Establish CRD %s", crd)
-		out, err := helpers.WaitForCommandCompletion(ui, message,
+		_, err := helpers.WaitForCommandCompletion(ui, message,
 			func() (string, error) {
-				return helpers.Kubectl("wait --for=condition=established --timeout=" + strconv.Itoa(k.Timeout) + "s crd/" + crd)
+				return "", c.WaitForCRDEstablished(crd, time.Duration(k.Timeout)*time.Second)
 			},
 		)
 		if err != nil {
-			return errors.Wrap(err, fmt.Sprintf("%s failed:\n%s", message, out))
+			return errors.Wrapf(err, "%s failed", message)
 		}
 	}
 
-	for _, c := range []string{"pipelines", "triggers", "dashboard"} {
-		message := fmt.Sprintf("Starting tekton %s pods", c)
-		out, err := helpers.WaitForCommandCompletion(ui, message,
-			func() (string, error) {
-				return helpers.Kubectl(fmt.Sprintf("wait --for=condition=Ready --timeout=%ds -n %s --selector=app.kubernetes.io/part-of=tekton-%s pod",
-					k.Timeout, tektonNamespace, c))
-			},
-		)
-		if err != nil {
-			return errors.Wrap(err, fmt.Sprintf("%s failed:\n%s", message, out))
+	for _, component := range []string{"pipelines", "triggers", "dashboard"} {
+		selector := fmt.Sprintf("app.kubernetes.io/part-of=tekton-%s", component)
+		if err := c.WaitUntilPodBySelectorExist(ui, tektonNamespace, selector, k.Timeout); err != nil {
+			return errors.Wrapf(err, "failed waiting tekton %s pods to exist", component)
+		}
+		if err := c.WaitForPodBySelectorRunning(ui, tektonNamespace, selector, k.Timeout); err != nil {
+			return errors.Wrapf(err, "failed waiting tekton %s pods to come up", component)
 		}
 	}
 
 	message := "Creating registry certificates in fuseml-workloads"
-	out, err := helpers.WaitForCommandCompletion(ui, message,
+	_, err = helpers.WaitForCommandCompletion(ui, message,
 		func() (string, error) {
-			out1, err := helpers.ExecToSuccessWithTimeout(
-				func() (string, error) {
-					return helpers.Kubectl("get secret -n fuseml-workloads registry-tls-self-ca")
-				}, time.Duration(k.Timeout)*time.Second, 3*time.Second)
-			if err != nil {
-				return out1, err
+			for _, secret := range registryTLSSecrets {
+				err := helpers.RunToSuccessWithTimeout(
+					func() error {
+						_, err := c.GetSecret(WorkloadsDeploymentID, secret)
+						return err
+					}, time.Duration(k.Timeout)*time.Second, 3*time.Second)
+				if err != nil {
+					return "", errors.Wrapf(err, "secret %s not found", secret)
+				}
 			}
-
-			out2, err := helpers.ExecToSuccessWithTimeout(
-				func() (string, error) {
-					return helpers.Kubectl("get secret -n fuseml-workloads registry-tls-self")
-				}, time.Duration(k.Timeout)*time.Second, 3*time.Second)
-
-			return fmt.Sprintf("%s\n%s", out1, out2), err
+			return "", nil
 		},
 	)
 	if err != nil {
-		return errors.Wrap(err, fmt.Sprintf("%s failed:\n%s", message, out))
+		return errors.Wrapf(err, "%s failed", message)
 	}
 
 	message = "Installing FuseML pipelines and triggers"
-	out, err = helpers.WaitForCommandCompletion(ui, message,
+	_, err = helpers.WaitForCommandCompletion(ui, message,
 		func() (string, error) {
-			return helpers.KubectlApplyEmbeddedYaml(tektonFusemlYamlPath)
+			if _, err := EnsureWebhookSecret(c); err != nil {
+				return "", err
+			}
+			manifest, err := tektonFusemlManifest(options)
+			if err != nil {
+				return "", err
+			}
+			return "", applyManifest(c, options, manifest, WorkloadsDeploymentID)
 		},
 	)
 	if err != nil {
-		return errors.Wrap(err, fmt.Sprintf("%s failed:\n%s", message, out))
+		return errors.Wrapf(err, "%s failed", message)
 	}
 
 	message = "Applying tekton Kaniko resources"
-	out, err = helpers.WaitForCommandCompletion(ui, message,
+	_, err = helpers.WaitForCommandCompletion(ui, message,
 		func() (string, error) {
-			return applyTektonKaniko(c, ui)
+			return "", applyTektonKaniko(c, ui, options)
 		},
 	)
 	if err != nil {
-		return errors.Wrap(err, fmt.Sprintf("%s failed:\n%s", message, out))
+		return errors.Wrapf(err, "%s failed", message)
 	}
 
 	domain, err := options.GetString("system_domain", TektonDeploymentID)
@@ -205,14 +272,14 @@ func (k Tekton) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.Insta
 		message := "Creating Tekton dashboard istio ingress gateway"
 		_, err = helpers.WaitForCommandCompletion(ui, message,
 			func() (string, error) {
-				return helpers.CreateIstioIngressGateway("tekton", tektonNamespace, TektonDeploymentID+"."+domain, "tekton-dashboard", 9097)
+				return "", tektonGateway(domain).create(c)
 			},
 		)
 	} else {
 		message = "Creating Tekton dashboard ingress"
 		_, err = helpers.WaitForCommandCompletion(ui, message,
 			func() (string, error) {
-				return "", createTektonIngress(c, TektonDeploymentID+"."+domain)
+				return "", createTektonIngress(c, TektonDeploymentID+"."+domain, ingressClass(options))
 			},
 		)
 	}
@@ -232,6 +299,81 @@ func (k Tekton) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.Insta
 	return nil
 }
 
+// Render writes the Tekton manifests, the FuseML pipelines and the dashboard
+// ingress into dir, with the webhook secret as a placeholder. The kaniko
+// resources need the registry CA, which is only generated during the
+// installation: unless it exists already, its subject hash is left as a
+// placeholder.
+func (k Tekton) Render(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions, dir string) error {
+	domain, err := options.GetString("system_domain", TektonDeploymentID)
+	if err != nil {
+		return errors.Wrap(err, "Couldn't get system_domain option")
+	}
+
+	r, err := newRenderer(dir, TektonDeploymentID)
+	if err != nil {
+		return err
+	}
+
+	for _, manifest := range []struct{ name, path string }{
+		{"admin-role", tektonAdminRoleYamlPath},
+		{"pipeline", tektonPipelineYamlPath},
+		{"triggers", tektonTriggersYamlPath},
+		{"dashboard", tektonDashboardYamlPath},
+	} {
+		if err := renderEmbeddedYaml(r, options, manifest.name, manifest.path); err != nil {
+			return err
+		}
+	}
+
+	fuseml, err := tektonFusemlManifest(options)
+	if err != nil {
+		return err
+	}
+	if err := renderManifest(r, options, "fuseml", fuseml); err != nil {
+		return err
+	}
+	webhookToken := secretPlaceholder(webhookSecretPlaceholder)
+	if err := r.Objects(WebhookSecretName, webhookSecret(webhookToken)); err != nil {
+		return err
+	}
+
+	var kaniko []byte
+	caHash, err := getRegistryCAHash(c, ui)
+	if err == nil {
+		kaniko, err = tektonKanikoManifest(caHash)
+	} else {
+		ui.Exclamation().Msg("Registry CA not generated yet, the kaniko resources keep its hash as placeholder")
+		kaniko, err = helpers.ReadEmbeddedFile(tektonKanikoYamlPath)
+	}
+	if err != nil {
+		return err
+	}
+	if err := renderManifest(r, options, "kaniko", kaniko); err != nil {
+		return err
+	}
+
+	if c.HasIstio() {
+		manifest, err := tektonGateway(domain).manifest()
+		if err != nil {
+			return err
+		}
+		err = r.Manifest("dashboard-gateway", manifest)
+	} else {
+		err = r.Objects("dashboard-ingress", tektonIngress(TektonDeploymentID+"."+domain, ingressClass(options)))
+	}
+	if err != nil {
+		return err
+	}
+
+	ui.Success().
+		WithStringValue("Directory", r.Dir).
+		WithStringValue("Placeholders", webhookToken).
+		Msg("Tekton rendered, without the webhook secret")
+
+	return nil
+}
+
 func (k Tekton) GetVersion() string {
 	return fmt.Sprintf("pipelines: %s, triggers %s, dashboard: %s",
 		tektonPipelineYamlPath, tektonTriggersYamlPath, tektonDashboardYamlPath)
@@ -287,69 +429,101 @@ func getRegistryCAHash(c *kubernetes.Cluster, ui *ui.UI) (string, error) {
 	return helpers.OpenSSLSubjectHash(string(secret.Data["ca"]))
 }
 
-func applyTektonKaniko(c *kubernetes.Cluster, ui *ui.UI) (string, error) {
+func applyTektonKaniko(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions) error {
 	caHash, err := getRegistryCAHash(c, ui)
 	if err != nil {
-		return "", errors.Wrap(err, "Failed to get registry CA from fuseml-workloads namespace")
+		return errors.Wrap(err, "Failed to get registry CA from fuseml-workloads namespace")
 	}
 
-	yamlPathOnDisk, err := helpers.ExtractFile(tektonKanikoYamlPath)
+	manifest, err := tektonKanikoManifest(caHash)
 	if err != nil {
-		return "", errors.New("Failed to extract embedded file: " + tektonKanikoYamlPath + " - " + err.Error())
+		return err
 	}
-	defer os.Remove(yamlPathOnDisk)
 
-	fileContents, err := ioutil.ReadFile(yamlPathOnDisk)
+	return applyManifest(c, options, manifest, WorkloadsDeploymentID)
+}
+
+// tektonFusemlManifest returns the FuseML pipelines and triggers, with the
+// pipeline timeout and workspace size from the options
+func tektonFusemlManifest(options kubernetes.InstallationOptions) ([]byte, error) {
+	fileContents, err := helpers.ReadEmbeddedFile(tektonFusemlYamlPath)
 	if err != nil {
-		return "", err
+		return nil, errors.New("Failed to extract embedded file: " + tektonFusemlYamlPath + " - " + err.Error())
 	}
 
-	// Constructing the name of the cert file as required by openssl.
-	// Lookup "subject_hash" in the docs: https://www.openssl.org/docs/man1.0.2/man1/x509.html
-	re := regexp.MustCompile(`{{CA_SELF_HASHED_NAME}}`)
-	renderedFileContents := re.ReplaceAll(fileContents, []byte(caHash+".0"))
+	timeout, err := options.GetString("pipeline_timeout", TektonDeploymentID)
+	if err != nil {
+		return nil, err
+	}
+	workspaceSize, err := options.GetString("workspace_size", TektonDeploymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := strings.NewReplacer(
+		"{{PIPELINE_TIMEOUT}}", timeout,
+		"{{WORKSPACE_SIZE}}", workspaceSize,
+	).Replace(string(fileContents))
 
-	tmpFilePath, err := helpers.CreateTmpFile(string(renderedFileContents))
+	return []byte(manifest), nil
+}
+
+// tektonKanikoManifest returns the kaniko resources, trusting the registry
+// CA with the given subject hash
+func tektonKanikoManifest(caHash string) ([]byte, error) {
+	fileContents, err := helpers.ReadEmbeddedFile(tektonKanikoYamlPath)
 	if err != nil {
-		return "", err
+		return nil, errors.New("Failed to extract embedded file: " + tektonKanikoYamlPath + " - " + err.Error())
 	}
-	defer os.Remove(tmpFilePath)
 
-	return helpers.Kubectl(fmt.Sprintf("apply -n fuseml-workloads --filename %s", tmpFilePath))
+	// Constructing the name of the cert file as required by openssl.
+	// Lookup "subject_hash" in the docs: https://www.openssl.org/docs/man1.0.2/man1/x509.html
+	re := regexp.MustCompile(`{{CA_SELF_HASHED_NAME}}`)
+	return re.ReplaceAll(fileContents, []byte(caHash+".0")), nil
 }
 
-func createTektonIngress(c *kubernetes.Cluster, subdomain string) error {
-	_, err := c.Kubectl.ExtensionsV1beta1().Ingresses("tekton-pipelines").Create(
+// tektonGateway returns the istio gateway exposing the Tekton dashboard
+func tektonGateway(domain string) istioGateway {
+	return istioGateway{name: "tekton", namespace: tektonNamespace, host: TektonDeploymentID + "." + domain, service: "tekton-dashboard", port: 9097}
+}
+
+func createTektonIngress(c *kubernetes.Cluster, subdomain, class string) error {
+	_, err := c.Kubectl.NetworkingV1().Ingresses("tekton-pipelines").Create(
 		context.Background(),
-		// TODO: Switch to networking v1 when we don't care about <1.18 clusters
-		// Like this (which has been reverted):
-		// https://github.com/SUSE/carrier/commit/7721d610fdf27a79be980af522783671d3ffc198
-		&v1beta1.Ingress{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "tekton-dashboard",
-				Namespace: "tekton-pipelines",
-				Annotations: map[string]string{
-					"kubernetes.io/ingress.class": "traefik",
-				},
-			},
-			Spec: v1beta1.IngressSpec{
-				Rules: []v1beta1.IngressRule{
-					{
-						Host: subdomain,
-						IngressRuleValue: v1beta1.IngressRuleValue{
-							HTTP: &v1beta1.HTTPIngressRuleValue{
-								Paths: []v1beta1.HTTPIngressPath{
-									{
-										Path: "/",
-										Backend: v1beta1.IngressBackend{
-											ServiceName: "tekton-dashboard",
-											ServicePort: intstr.IntOrString{
-												Type:   intstr.Int,
-												IntVal: 9097,
-											},
-										}}}}}}}}},
+		tektonIngress(subdomain, class),
 		metav1.CreateOptions{},
 	)
 
 	return err
 }
+
+func tektonIngress(subdomain, class string) *networkingv1.Ingress {
+	pathType := networkingv1.PathTypePrefix
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tekton-dashboard",
+			Namespace: "tekton-pipelines",
+			Annotations: map[string]string{
+				"kubernetes.io/ingress.class": class,
+			},
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: subdomain,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "tekton-dashboard",
+											Port: networkingv1.ServiceBackendPort{
+												Number: 9097,
+											},
+										},
+									}}}}}}}},
+	}
+}
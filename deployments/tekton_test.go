@@ -0,0 +1,28 @@
+package deployments_test
+
+import (
+	. "github.com/fuseml/fuseml/cli/deployments"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+var _ = Describe("TektonIngress", func() {
+	It("routes the host to the dashboard, with a networking.k8s.io/v1 ingress", func() {
+		ingress := TektonIngress("tekton.10.0.0.1.nip.io", "traefik")
+		Expect(ingress.Annotations).To(HaveKeyWithValue("kubernetes.io/ingress.class", "traefik"))
+		Expect(ingress.Spec.Rules).To(HaveLen(1))
+
+		rule := ingress.Spec.Rules[0]
+		Expect(rule.Host).To(Equal("tekton.10.0.0.1.nip.io"))
+		Expect(rule.HTTP.Paths).To(HaveLen(1))
+
+		path := rule.HTTP.Paths[0]
+		Expect(path.Path).To(Equal("/"))
+		Expect(*path.PathType).To(Equal(networkingv1.PathTypePrefix))
+		Expect(path.Backend.Service).To(Equal(&networkingv1.IngressServiceBackend{
+			Name: "tekton-dashboard",
+			Port: networkingv1.ServiceBackendPort{Number: 9097},
+		}))
+	})
+})
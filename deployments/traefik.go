@@ -2,15 +2,13 @@ package deployments
 
 import (
 	"context"
-	"fmt"
-	"os"
-	"strings"
 
 	"github.com/fuseml/fuseml/cli/helpers"
 	"github.com/fuseml/fuseml/cli/kubernetes"
 	"github.com/fuseml/fuseml/cli/paas/ui"
 	"github.com/kyokomi/emoji"
 	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/cli/values"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,10 +23,43 @@ const (
 	traefikChartURL     = "https://helm.traefik.io/traefik/traefik-9.11.0.tgz"
 )
 
+// traefikCRDs are the CRDs installed by the traefik chart
+var traefikCRDs = []string{
+	"ingressroutes.traefik.containo.us",
+	"ingressroutetcps.traefik.containo.us",
+	"ingressrouteudps.traefik.containo.us",
+	"middlewares.traefik.containo.us",
+	"tlsoptions.traefik.containo.us",
+	"tlsstores.traefik.containo.us",
+	"traefikservices.traefik.containo.us",
+}
+
 func (k *Traefik) ID() string {
 	return TraefikDeploymentID
 }
 
+// Needs returns no deployments. Traefik is installed first, as the system
+// domain defaults to its IP.
+func (k Traefik) Needs() []string {
+	return nil
+}
+
+// Options returns the type of the Traefik service, which platforms without
+// load balancers set to NodePort
+func (k Traefik) Options() kubernetes.InstallationOptions {
+	return kubernetes.InstallationOptions{
+		{
+			Name:         "service_type",
+			Description:  "The type of the Traefik service, LoadBalancer or NodePort",
+			Type:         kubernetes.StringType,
+			Default:      "LoadBalancer",
+			Value:        "",
+			DeploymentID: TraefikDeploymentID,
+			Validators:   []kubernetes.InstallationOptionValidator{kubernetes.EnumValidator("LoadBalancer", "NodePort")},
+		},
+	}
+}
+
 func (k *Traefik) Backup(c *kubernetes.Cluster, ui *ui.UI, d string) error {
 	return nil
 }
@@ -41,8 +72,30 @@ func (k Traefik) Describe() string {
 	return emoji.Sprintf(":cloud:Traefik version: %s\n:clipboard:Traefik Ingress chart: %s", traefikVersion, traefikChartURL)
 }
 
+// Health checks the Traefik release and pods. Nothing else is checked when
+// istio, or the Traefik of k3s, serves the ingresses instead.
+func (k Traefik) Health(c *kubernetes.Cluster) []kubernetes.HealthCheck {
+	if c.HasIstio() {
+		return []kubernetes.HealthCheck{kubernetes.Healthy("ingress controller", "istio")}
+	}
+	if _, err := c.Kubectl.CoreV1().Services("kube-system").Get(context.Background(), "traefik", metav1.GetOptions{}); err == nil {
+		return []kubernetes.HealthCheck{kubernetes.Healthy("ingress controller", "traefik in kube-system")}
+	}
+
+	namespace := c.NamespaceHealth(TraefikDeploymentID)
+	if !namespace.Healthy {
+		return []kubernetes.HealthCheck{namespace}
+	}
+
+	return []kubernetes.HealthCheck{
+		namespace,
+		releaseHealth(c, "traefik", TraefikDeploymentID),
+		c.PodsHealth(TraefikDeploymentID, "app.kubernetes.io/name=traefik"),
+	}
+}
+
 // Delete removes traefik from kubernetes cluster
-func (k Traefik) Delete(c *kubernetes.Cluster, ui *ui.UI) error {
+func (k Traefik) Delete(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.DeleteOptions) error {
 	ui.Note().KeeplineUnder(1).Msg("Removing Traefik...")
 
 	existsAndOwned, err := c.NamespaceExistsAndOwned(TraefikDeploymentID)
@@ -54,23 +107,38 @@ func (k Traefik) Delete(c *kubernetes.Cluster, ui *ui.UI) error {
 		return nil
 	}
 
-	currentdir, err := os.Getwd()
-	if err != nil {
-		return errors.New("Failed uninstalling Traefik: " + err.Error())
+	resources := []string{"helm release " + TraefikDeploymentID + "/traefik"}
+	for _, crd := range traefikCRDs {
+		resources = append(resources, "custom resource definition "+crd)
+	}
+	proceed, err := removal{
+		name:      "Traefik",
+		resources: append(resources, "namespace "+TraefikDeploymentID),
+	}.prepare(c, ui, options)
+	if !proceed || err != nil {
+		return err
 	}
 
 	message := "Removing helm release " + TraefikDeploymentID
-	out, err := helpers.WaitForCommandCompletion(ui, message,
+	found := true
+	_, err = helpers.WaitForCommandCompletion(ui, message,
 		func() (string, error) {
-			helmCmd := fmt.Sprintf("helm uninstall traefik --namespace '%s'", TraefikDeploymentID)
-			return helpers.RunProc(helmCmd, currentdir, k.Debug)
+			var err error
+			found, err = uninstallRelease(c, "traefik", TraefikDeploymentID, k.Debug)
+			return "", err
 		},
 	)
 	if err != nil {
-		if strings.Contains(out, "release: not found") {
-			ui.Exclamation().Msgf("%s helm release not found, skipping.\n", TraefikDeploymentID)
-		} else {
-			return errors.Wrapf(err, "Failed uninstalling helm release %s: %s", TraefikDeploymentID, out)
+		return errors.Wrap(err, "Failed uninstalling Traefik")
+	}
+	if !found {
+		ui.Exclamation().Msgf("%s helm release not found, skipping.\n", TraefikDeploymentID)
+	}
+
+	// helm leaves the CRDs of a chart behind
+	for _, crd := range traefikCRDs {
+		if err := c.DeleteCRD(crd); err != nil {
+			return errors.Wrap(err, "Deleting traefik CRD failed")
 		}
 	}
 
@@ -89,28 +157,38 @@ func (k Traefik) Delete(c *kubernetes.Cluster, ui *ui.UI) error {
 	return nil
 }
 
-func (k Traefik) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions, upgrade bool) error {
-	action := "install"
-	if upgrade {
-		action = "upgrade"
+// release returns the helm release of Traefik
+func (k Traefik) release(options kubernetes.InstallationOptions) (helmRelease, error) {
+	// Setup Traefik helm values
+	var vals values.Options
+
+	// Disable sending anonymous usage statistics
+	// https://github.com/traefik/traefik-helm-chart/blob/v9.11.0/traefik/values.yaml#L170
+	// Overwrite globalArguments until https://github.com/traefik/traefik-helm-chart/issues/357 is fixed
+	vals.Values = append(vals.Values, "globalArguments=")
+
+	serviceType, err := options.GetString("service_type", TraefikDeploymentID)
+	if err != nil {
+		return helmRelease{}, err
 	}
+	vals.Values = append(vals.Values, "service.type="+serviceType)
 
-	currentdir, err := os.Getwd()
+	chart, err := chartSource(options, traefikChartURL)
 	if err != nil {
-		return err
+		return helmRelease{}, err
 	}
 
-	// Setup Traefik helm values
-	var helmArgs []string
+	return helmRelease{name: "traefik", namespace: TraefikDeploymentID, chart: chart, values: vals, imageRegistry: imageRegistry(options)}, nil
+}
 
-	// Disable sending anonymous usage statistics
-	// https://github.com/traefik/traefik-helm-chart/blob/v9.11.0/traefik/values.yaml#L170
-	// Overwrite globalArguments until https://github.com/traefik/traefik-helm-chart/issues/357 is fixed
-	helmArgs = append(helmArgs, `--set "globalArguments="`)
+func (k Traefik) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions, upgrade bool) error {
+	release, err := k.release(options)
+	if err != nil {
+		return err
+	}
 
-	helmCmd := fmt.Sprintf("helm %s traefik --create-namespace --namespace %s %s %s", action, TraefikDeploymentID, traefikChartURL, strings.Join(helmArgs, " "))
-	if out, err := helpers.RunProc(helmCmd, currentdir, k.Debug); err != nil {
-		return errors.Wrap(err, fmt.Sprintf("Failed installing Traefik: %s\n", out))
+	if err := release.install(c, upgrade, k.Debug); err != nil {
+		return errors.Wrap(err, "Failed installing Traefik")
 	}
 
 	err = c.LabelNamespace(TraefikDeploymentID, kubernetes.FusemlDeploymentLabelKey, kubernetes.FusemlDeploymentLabelValue)
@@ -130,6 +208,46 @@ func (k Traefik) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.Inst
 	return nil
 }
 
+// Render writes the Traefik namespace and chart into dir
+func (k Traefik) Render(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions, dir string) error {
+	_, err := c.Kubectl.CoreV1().Services("kube-system").Get(
+		context.Background(),
+		"traefik",
+		metav1.GetOptions{},
+	)
+	if err == nil {
+		ui.Exclamation().Msg("Traefik Ingress already installed, skipping")
+		return nil
+	}
+
+	if c.HasIstio() {
+		ui.Exclamation().Msg("Istio already installed, skipping traefik installation...")
+		return nil
+	}
+
+	r, err := newRenderer(dir, TraefikDeploymentID)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Objects("namespace", fusemlNamespace(TraefikDeploymentID, nil)); err != nil {
+		return err
+	}
+
+	release, err := k.release(options)
+	if err != nil {
+		return err
+	}
+
+	if err := release.render(r); err != nil {
+		return err
+	}
+
+	ui.Success().WithStringValue("Directory", r.Dir).Msg("Traefik Ingress rendered")
+
+	return nil
+}
+
 func (k Traefik) GetVersion() string {
 	return traefikVersion
 }
@@ -0,0 +1,73 @@
+package deployments
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	// WebhookSecretName is the secret holding the token the webhooks of the
+	// applications sign their requests with, checked by the pipeline
+	// triggers
+	WebhookSecretName = "fuseml-webhook"
+	// WebhookSecretKey is the key of the token in the webhook secret
+	WebhookSecretKey = "secret"
+
+	webhookSecretPlaceholder = "FUSEML_WEBHOOK_SECRET"
+	webhookSecretBytes       = 18
+)
+
+// EnsureWebhookSecret returns the token the webhooks of the applications
+// sign their requests with, generating it if it does not exist yet
+func EnsureWebhookSecret(c *kubernetes.Cluster) (string, error) {
+	return ensureWebhookSecret(c.Kubectl.CoreV1().Secrets(WorkloadsDeploymentID))
+}
+
+func ensureWebhookSecret(secrets typedcorev1.SecretInterface) (string, error) {
+	secret, err := secrets.Get(context.Background(), WebhookSecretName, metav1.GetOptions{})
+	if err == nil {
+		return string(secret.Data[WebhookSecretKey]), nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return "", errors.Wrap(err, "failed to get the webhook secret")
+	}
+
+	b := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to generate the webhook secret")
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	_, err = secrets.Create(context.Background(), webhookSecret(token), metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		// Created concurrently, use that one
+		return ensureWebhookSecret(secrets)
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create the webhook secret")
+	}
+
+	return token, nil
+}
+
+// webhookSecret returns the secret holding the webhook token
+func webhookSecret(token string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      WebhookSecretName,
+			Namespace: WorkloadsDeploymentID,
+		},
+		StringData: map[string]string{
+			WebhookSecretKey: token,
+		},
+		Type: corev1.SecretTypeOpaque,
+	}
+}
@@ -0,0 +1,100 @@
+package deployments_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	. "github.com/fuseml/fuseml/cli/deployments"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/fake"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+var _ = Describe("EnsureWebhookSecret", func() {
+	var secrets typedcorev1.SecretInterface
+
+	BeforeEach(func() {
+		secrets = fake.NewSimpleClientset().CoreV1().Secrets(WorkloadsDeploymentID)
+	})
+
+	It("generates the secret if it does not exist", func() {
+		token, err := EnsureWebhookSecretIn(secrets)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).ToNot(BeEmpty())
+
+		secret, err := secrets.Get(context.Background(), WebhookSecretName, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secret.StringData).To(Equal(map[string]string{WebhookSecretKey: token}))
+	})
+
+	It("returns the existing secret", func() {
+		_, err := secrets.Create(context.Background(), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: WebhookSecretName, Namespace: WorkloadsDeploymentID},
+			Data:       map[string][]byte{WebhookSecretKey: []byte("s3cr3t")},
+		}, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		token, err := EnsureWebhookSecretIn(secrets)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).To(Equal("s3cr3t"))
+	})
+})
+
+var _ = Describe("FuseML triggers", func() {
+	type trigger struct {
+		Name         string `json:"name"`
+		Interceptors []struct {
+			GitLab *struct {
+				SecretRef *struct {
+					SecretName string `json:"secretName"`
+					SecretKey  string `json:"secretKey"`
+				} `json:"secretRef"`
+				EventTypes []string `json:"eventTypes"`
+			} `json:"gitlab"`
+		} `json:"interceptors"`
+	}
+
+	It("checks the webhook secret of the GitLab push events", func() {
+		manifest, err := TektonFusemlManifest((&Tekton{}).Options())
+		Expect(err).ToNot(HaveOccurred())
+
+		var found bool
+		decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifest), 4096)
+		for {
+			var object struct {
+				Kind string `json:"kind"`
+				Spec struct {
+					Triggers []trigger `json:"triggers"`
+				} `json:"spec"`
+			}
+			err := decoder.Decode(&object)
+			if err == io.EOF {
+				break
+			}
+			Expect(err).ToNot(HaveOccurred())
+			if object.Kind != "EventListener" {
+				continue
+			}
+
+			for _, t := range object.Spec.Triggers {
+				if t.Name != "gitlab-push" {
+					continue
+				}
+				found = true
+				Expect(t.Interceptors).ToNot(BeEmpty())
+				gitlab := t.Interceptors[0].GitLab
+				Expect(gitlab).ToNot(BeNil())
+				Expect(gitlab.SecretRef).ToNot(BeNil())
+				Expect(gitlab.SecretRef.SecretName).To(Equal(WebhookSecretName))
+				Expect(gitlab.SecretRef.SecretKey).To(Equal(WebhookSecretKey))
+				Expect(gitlab.EventTypes).To(ConsistOf("Push Hook"))
+			}
+		}
+		Expect(found).To(BeTrue())
+	})
+})
@@ -1,8 +1,9 @@
 package deployments
 
 import (
+	"bytes"
 	"context"
-	"fmt"
+	"time"
 
 	"github.com/fuseml/fuseml/cli/helpers"
 	"github.com/fuseml/fuseml/cli/kubernetes"
@@ -10,7 +11,6 @@ import (
 	"github.com/kyokomi/emoji"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -29,6 +29,16 @@ func (k *Workloads) ID() string {
 	return WorkloadsDeploymentID
 }
 
+// Needs returns no deployments
+func (k Workloads) Needs() []string {
+	return nil
+}
+
+// Options returns no options, the workloads namespace only uses the shared ones
+func (k Workloads) Options() kubernetes.InstallationOptions {
+	return kubernetes.InstallationOptions{}
+}
+
 func (k *Workloads) Backup(c *kubernetes.Cluster, ui *ui.UI, d string) error {
 	return nil
 }
@@ -41,20 +51,50 @@ func (k Workloads) Describe() string {
 	return emoji.Sprintf(":cloud:Workloads Eirinix Ingress Version: %s\n", WorkloadsIngressVersion)
 }
 
+// Health checks the workloads namespace, the registry credentials of the
+// applications, and the app ingress without istio
+func (w Workloads) Health(c *kubernetes.Cluster) []kubernetes.HealthCheck {
+	namespace := c.NamespaceHealth(WorkloadsDeploymentID)
+	if !namespace.Healthy {
+		return []kubernetes.HealthCheck{namespace}
+	}
+
+	checks := []kubernetes.HealthCheck{
+		namespace,
+		c.SecretsHealth(WorkloadsDeploymentID, w.registryCredsSecret().Name),
+	}
+	if !c.HasIstio() {
+		checks = append(checks, c.PodsHealth("app-ingress", "name=app-ingress"))
+	}
+
+	return checks
+}
+
 // Delete removes Workloads from kubernetes cluster
-func (w Workloads) Delete(c *kubernetes.Cluster, ui *ui.UI) error {
+func (w Workloads) Delete(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.DeleteOptions) error {
 	ui.Note().KeeplineUnder(1).Msg("Removing Workloads...")
 
+	proceed, err := removal{
+		name: "Workloads",
+		resources: []string{
+			"namespace " + WorkloadsDeploymentID + ", with all applications",
+			"manifest " + appIngressYamlPath,
+		},
+		// MLflow keeps its data in the workloads namespace
+		dataNamespace: WorkloadsDeploymentID,
+		dataSelectors: mlflowDataSelectors,
+	}.prepare(c, ui, options)
+	if !proceed || err != nil {
+		return err
+	}
+
 	existsAndOwned, err := c.NamespaceExistsAndOwned(WorkloadsDeploymentID)
 	if err != nil {
 		return errors.Wrapf(err, "failed to check if namespace '%s' is owned or not", WorkloadsDeploymentID)
 	}
 	if !existsAndOwned {
 		ui.Exclamation().Msg("Skipping Workspace because namespace either doesn't exist or not owned by Fuseml")
-		return nil
-	}
-
-	if err := w.deleteWorkloadsNamespace(c, ui); err != nil {
+	} else if err := w.deleteWorkloadsNamespace(c, ui); err != nil {
 		return errors.Wrapf(err, "Failed deleting namespace %s", WorkloadsDeploymentID)
 	}
 
@@ -67,8 +107,8 @@ func (w Workloads) Delete(c *kubernetes.Cluster, ui *ui.UI) error {
 		return nil
 	}
 
-	if out, err := helpers.KubectlDeleteEmbeddedYaml(appIngressYamlPath, true); err != nil {
-		return errors.Wrap(err, fmt.Sprintf("Deleting %s failed:\n%s", appIngressYamlPath, out))
+	if err := helpers.DeleteEmbeddedYaml(c, appIngressYamlPath, true); err != nil {
+		return errors.Wrapf(err, "Deleting %s failed", appIngressYamlPath)
 	}
 
 	ui.Success().Msg("Workloads removed")
@@ -82,8 +122,12 @@ func (w Workloads) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.In
 	}
 
 	if !c.HasIstio() {
-		if out, err := helpers.KubectlApplyEmbeddedYaml(appIngressYamlPath); err != nil {
-			return errors.Wrap(err, fmt.Sprintf("Installing %s failed:\n%s", appIngressYamlPath, out))
+		manifest, err := w.appIngress(options)
+		if err != nil {
+			return err
+		}
+		if err := applyManifest(c, options, manifest, "app-ingress"); err != nil {
+			return errors.Wrapf(err, "Installing %s failed", appIngressYamlPath)
 		}
 
 		if err := c.LabelNamespace("app-ingress", kubernetes.FusemlDeploymentLabelKey, kubernetes.FusemlDeploymentLabelValue); err != nil {
@@ -100,6 +144,47 @@ func (w Workloads) apply(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.In
 	return nil
 }
 
+// appIngress returns the manifest of the app ingress, which creates the
+// ingresses of the applications with the configured ingress class
+func (w Workloads) appIngress(options kubernetes.InstallationOptions) ([]byte, error) {
+	manifest, err := helpers.ReadEmbeddedFile(appIngressYamlPath)
+	if err != nil {
+		return nil, errors.New("Failed to extract embedded file: " + appIngressYamlPath + " - " + err.Error())
+	}
+
+	return bytes.ReplaceAll(manifest,
+		[]byte(`"kubernetes.io/ingress.class": "`+DefaultIngressClass+`"`),
+		[]byte(`"kubernetes.io/ingress.class": "`+ingressClass(options)+`"`)), nil
+}
+
+// Render writes the workloads namespace, with its credentials, and the app
+// ingress into dir
+func (w Workloads) Render(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.InstallationOptions, dir string) error {
+	r, err := newRenderer(dir, WorkloadsDeploymentID)
+	if err != nil {
+		return err
+	}
+
+	err = r.Objects("namespace", w.namespace(), w.registryCredsSecret(), w.serviceAccount())
+	if err != nil {
+		return err
+	}
+
+	if !c.HasIstio() {
+		manifest, err := w.appIngress(options)
+		if err != nil {
+			return err
+		}
+		if err := renderManifest(r, options, "app-ingress", manifest); err != nil {
+			return err
+		}
+	}
+
+	ui.Success().WithStringValue("Directory", r.Dir).Msg("Workloads rendered")
+
+	return nil
+}
+
 func (k Workloads) GetVersion() string {
 	// TODO: Maybe this should be the Fuseml version itself?
 	return WorkloadsIngressVersion
@@ -134,15 +219,7 @@ func (k Workloads) Upgrade(c *kubernetes.Cluster, ui *ui.UI, options kubernetes.
 func (w Workloads) createWorkloadsNamespace(c *kubernetes.Cluster, ui *ui.UI) error {
 	if _, err := c.Kubectl.CoreV1().Namespaces().Create(
 		context.Background(),
-		&corev1.Namespace{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: WorkloadsDeploymentID,
-				Labels: map[string]string{
-					"quarks.cloudfoundry.org/monitored": "quarks-secret",
-					kubernetes.FusemlDeploymentLabelKey: kubernetes.FusemlDeploymentLabelValue,
-				},
-			},
-		},
+		w.namespace(),
 		metav1.CreateOptions{},
 	); err != nil {
 		return nil
@@ -151,9 +228,6 @@ func (w Workloads) createWorkloadsNamespace(c *kubernetes.Cluster, ui *ui.UI) er
 	if err := c.LabelNamespace(WorkloadsDeploymentID, kubernetes.FusemlDeploymentLabelKey, kubernetes.FusemlDeploymentLabelValue); err != nil {
 		return err
 	}
-	if err := w.createGiteaCredsSecret(c); err != nil {
-		return err
-	}
 	if err := w.createClusterRegistryCredsSecret(c); err != nil {
 		return err
 	}
@@ -176,36 +250,32 @@ func (w Workloads) deleteWorkloadsNamespace(c *kubernetes.Cluster, ui *ui.UI) er
 	}
 
 	message = "Waiting for workloads namespace to be gone"
-	warning, err := helpers.WaitForCommandCompletion(ui, message,
+	_, err = helpers.WaitForCommandCompletion(ui, message,
 		func() (string, error) {
-			var err error
-			for err == nil {
-				_, err = c.Kubectl.CoreV1().Namespaces().Get(
-					context.Background(),
-					WorkloadsDeploymentID,
-					metav1.GetOptions{},
-				)
-			}
-			if serr, ok := err.(*apierrors.StatusError); ok {
-				if serr.ErrStatus.Reason == metav1.StatusReasonNotFound {
-					return "", nil
-				}
-			}
-
-			return "", err
+			return "", c.WaitForNamespaceGone(WorkloadsDeploymentID, time.Duration(w.Timeout)*time.Second)
 		},
 	)
 	if err != nil {
 		return err
 	}
-	if warning != "" {
-		ui.Exclamation().Msg(warning)
-	}
 
 	return nil
 }
 
 func (w Workloads) createClusterRegistryCredsSecret(c *kubernetes.Cluster) error {
+	_, err := c.Kubectl.CoreV1().Secrets(WorkloadsDeploymentID).Create(context.Background(),
+		w.registryCredsSecret(), metav1.CreateOptions{})
+
+	return err
+}
+
+func (w Workloads) namespace() *corev1.Namespace {
+	return fusemlNamespace(WorkloadsDeploymentID, map[string]string{
+		"quarks.cloudfoundry.org/monitored": "quarks-secret",
+	})
+}
+
+func (w Workloads) registryCredsSecret() *corev1.Secret {
 	// TODO: Are all of these really used? We need tekton to be able to access
 	// the registry and also kubernetes (when we deploy our app deployments)
 	auths := `{ "auths": {
@@ -214,63 +284,40 @@ func (w Workloads) createClusterRegistryCredsSecret(c *kubernetes.Cluster) error
 		 "registry.fuseml-registry":{"username":"admin","password":"password"},
 		 "registry.fuseml-registry:444":{"username":"admin","password":"password"} } }`
 
-	_, err := c.Kubectl.CoreV1().Secrets(WorkloadsDeploymentID).Create(context.Background(),
-		&corev1.Secret{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "registry-creds",
-			},
-			StringData: map[string]string{
-				".dockerconfigjson": auths,
-			},
-			Type: "kubernetes.io/dockerconfigjson",
-		}, metav1.CreateOptions{})
-
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-func (w Workloads) createGiteaCredsSecret(c *kubernetes.Cluster) error {
-	_, err := c.Kubectl.CoreV1().Secrets(WorkloadsDeploymentID).Create(context.Background(),
-		&corev1.Secret{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "gitea-creds",
-				Annotations: map[string]string{
-					//"kpack.io/git": fmt.Sprintf("http://%s.%s", GiteaDeploymentID, domain),
-					"tekton.dev/git-0": "http://gitea-http.gitea:10080", // TODO: Don't hardcode
-				},
-			},
-			StringData: map[string]string{
-				"username": "dev",
-				"password": "changeme",
-			},
-			Type: "kubernetes.io/basic-auth",
-		}, metav1.CreateOptions{})
-
-	if err != nil {
-		return err
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "registry-creds",
+			Namespace: WorkloadsDeploymentID,
+		},
+		StringData: map[string]string{
+			".dockerconfigjson": auths,
+		},
+		Type: "kubernetes.io/dockerconfigjson",
 	}
-	return nil
 }
 
 // Adding the imagePullSecrets to the service account attached to the application
 // pods, will automatically assign the same imagePullSecrets to the pods themselves:
 // https://kubernetes.io/docs/tasks/configure-pod-container/configure-service-account/#verify-imagepullsecrets-was-added-to-pod-spec
 func (w Workloads) createWorkloadsServiceAccountWithSecretAccess(c *kubernetes.Cluster) error {
-	automountServiceAccountToken := false
 	_, err := c.Kubectl.CoreV1().ServiceAccounts(WorkloadsDeploymentID).Create(
-		context.Background(),
-		&corev1.ServiceAccount{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: WorkloadsDeploymentID,
-			},
-			ImagePullSecrets: []corev1.LocalObjectReference{
-				{Name: "registry-creds"},
-				{Name: "gitea-creds"},
-			},
-			AutomountServiceAccountToken: &automountServiceAccountToken,
-		}, metav1.CreateOptions{})
+		context.Background(), w.serviceAccount(), metav1.CreateOptions{})
 
 	return err
 }
+
+func (w Workloads) serviceAccount() *corev1.ServiceAccount {
+	automountServiceAccountToken := false
+
+	return &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      WorkloadsDeploymentID,
+			Namespace: WorkloadsDeploymentID,
+		},
+		ImagePullSecrets: []corev1.LocalObjectReference{
+			{Name: "registry-creds"},
+			{Name: giteaCredsSecret},
+		},
+		AutomountServiceAccountToken: &automountServiceAccountToken,
+	}
+}
@@ -7,11 +7,14 @@ require (
 	github.com/briandowns/spinner v1.12.0
 	github.com/codeskyblue/kexec v0.0.0-20180119015717-5a4bed90d99a
 	github.com/fatih/color v1.9.0
+	github.com/go-git/go-git/v5 v5.1.0
 	github.com/go-logr/logr v0.4.0
 	github.com/go-logr/stdr v0.4.0
+	github.com/google/go-containerregistry v0.4.1-0.20210128200529-19c2b639fab1
 	github.com/google/wire v0.4.0
 	github.com/imdario/mergo v0.3.11 // indirect
 	github.com/kyokomi/emoji v2.2.4+incompatible
+	github.com/mattn/go-isatty v0.0.16
 	github.com/maxbrunsfeld/counterfeiter/v6 v6.3.0
 	github.com/olekukonko/tablewriter v0.0.4
 	github.com/onsi/ginkgo v1.14.2
@@ -24,10 +27,22 @@ require (
 	github.com/spf13/pflag v1.0.5
 	github.com/spf13/viper v1.7.1
 	github.com/tektoncd/pipeline v0.22.0
+	golang.org/x/oauth2 v0.13.0
+	golang.org/x/term v0.13.0
+	helm.sh/helm/v3 v3.5.4
 	istio.io/client-go v1.8.2
-	k8s.io/api v0.20.2
-	k8s.io/apimachinery v0.20.2
-	k8s.io/client-go v0.20.2
+	k8s.io/api v0.20.4
+	k8s.io/apimachinery v0.20.4
+	k8s.io/client-go v0.20.4
 	knative.dev/pkg v0.0.0-20210215165523-84c98f3c3e7a
 	knative.dev/serving v0.21.0
+	sigs.k8s.io/yaml v1.2.0
+)
+
+// The helm SDK needs the docker modules it was built with, and the
+// kustomize of cli-runtime does not build with go-openapi/spec v0.20
+replace (
+	github.com/docker/distribution => github.com/docker/distribution v0.0.0-20191216044856-a8371794149d
+	github.com/docker/docker => github.com/moby/moby v17.12.0-ce-rc1.0.20200618181300-9dc6525e6118+incompatible
+	github.com/go-openapi/spec => github.com/go-openapi/spec v0.19.6
 )
@@ -2,12 +2,11 @@ package helpers
 
 import (
 	"errors"
-	"fmt"
 	"io/ioutil"
 	"log"
-	"os"
 	"path"
 
+	"github.com/fuseml/fuseml/cli/kubernetes"
 	_ "github.com/fuseml/fuseml/cli/statik"
 	"github.com/rakyll/statik/fs"
 )
@@ -39,30 +38,29 @@ func ExtractFile(filePath string) (string, error) {
 	return tmpFilePath, nil
 }
 
-// KubectlApplyEmbeddedYaml un-embeds the given yaml file and calls `kubectl apply`
-// on it. It returns the command output and an error (if there is one)
-func KubectlApplyEmbeddedYaml(yamlPath string) (string, error) {
-	yamlPathOnDisk, err := ExtractFile(yamlPath)
+// ReadEmbeddedFile returns the contents of a file embedded with statik.
+func ReadEmbeddedFile(filePath string) ([]byte, error) {
+	statikFS, err := fs.New()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := statikFS.Open(path.Join("/", filePath))
 	if err != nil {
-		return "", errors.New("Failed to extract embedded file: " + yamlPath + " - " + err.Error())
+		return nil, err
 	}
-	defer os.Remove(yamlPathOnDisk)
+	defer file.Close()
 
-	return Kubectl(fmt.Sprintf("apply --filename %s", yamlPathOnDisk))
+	return ioutil.ReadAll(file)
 }
 
-// KubectlDeleteEmbeddedYaml un-embeds the given yaml file and calls `kubectl delete`
-// on it. It returns the command output and an error (if there is one)
-func KubectlDeleteEmbeddedYaml(yamlPath string, ignoreMissing bool) (string, error) {
-	yamlPathOnDisk, err := ExtractFile(yamlPath)
+// DeleteEmbeddedYaml un-embeds the given yaml file and deletes its objects
+// from the cluster
+func DeleteEmbeddedYaml(c *kubernetes.Cluster, yamlPath string, ignoreMissing bool) error {
+	manifest, err := ReadEmbeddedFile(yamlPath)
 	if err != nil {
-		return "", errors.New("Failed to extract embedded file: " + yamlPath + " - " + err.Error())
+		return errors.New("Failed to extract embedded file: " + yamlPath + " - " + err.Error())
 	}
-	defer os.Remove(yamlPathOnDisk)
 
-	if ignoreMissing {
-		return Kubectl(fmt.Sprintf("delete --ignore-not-found=true --wait=false --filename %s", yamlPathOnDisk))
-	} else {
-		return Kubectl(fmt.Sprintf("delete --filename %s", yamlPathOnDisk))
-	}
+	return c.DeleteManifest(manifest, "", ignoreMissing)
 }
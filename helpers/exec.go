@@ -6,8 +6,6 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
-	"os/exec"
-	"strings"
 	"time"
 
 	"github.com/codeskyblue/kexec"
@@ -18,10 +16,16 @@ import (
 
 type ExternalFuncWithString func() (output string, err error)
 
+// Debug returns whether debug output is enabled, with DEBUG=true. It is
+// written directly to stdout, bypassing any progress display.
+func Debug() bool {
+	return os.Getenv("DEBUG") == "true"
+}
+
 type ExternalFunc func() (err error)
 
 func RunProc(cmd, dir string, toStdout bool) (string, error) {
-	if os.Getenv("DEBUG") == "true" {
+	if Debug() {
 		fmt.Println("Executing ", cmd)
 	}
 	p := kexec.CommandString(cmd)
@@ -46,7 +50,7 @@ func RunProc(cmd, dir string, toStdout bool) (string, error) {
 }
 
 func RunProcNoErr(cmd, dir string, toStdout bool) (string, error) {
-	if os.Getenv("DEBUG") == "true" {
+	if Debug() {
 		fmt.Println("Executing ", cmd)
 	}
 	p := kexec.CommandString(cmd)
@@ -87,24 +91,6 @@ func CreateTmpFile(contents string) (string, error) {
 	return tmpfile.Name(), nil
 }
 
-// Kubectl invoces the `kubectl` command in PATH, running the specified command.
-// It returns the command output and/or error.
-func Kubectl(command string) (string, error) {
-	_, err := exec.LookPath("kubectl")
-	if err != nil {
-		return "", errors.Wrap(err, "kubectl not in path")
-	}
-
-	currentdir, err := os.Getwd()
-	if err != nil {
-		return "", err
-	}
-
-	cmd := fmt.Sprintf("kubectl " + command)
-
-	return RunProc(cmd, currentdir, false)
-}
-
 func WaitForCommandCompletion(ui *ui.UI, message string, funk ExternalFuncWithString) (string, error) {
 	s := ui.Progressf(" %s", message)
 	defer s.Stop()
@@ -147,32 +133,3 @@ func RunToSuccessWithTimeout(funk ExternalFunc, timeout, interval time.Duration)
 		}
 	}
 }
-
-// OpenSSLSubjectHash return the subject_hash of the given CA certificate as
-// returned by this command:
-// openssl x509 -hash -noout
-// https://www.openssl.org/docs/man1.0.2/man1/x509.html
-// TODO: The way this function is implemented, it makes a system call to openssl
-// thus making openssl a dependency. There must be a way to calculate the hash
-// in Go so we don't need openssl.
-func OpenSSLSubjectHash(cert string) (string, error) {
-	_, err := exec.LookPath("openssl")
-	if err != nil {
-		return "", errors.Wrap(err, "openssl not in path")
-	}
-
-	cmd := exec.Command("openssl", "x509", "-hash", "-noout")
-	stdin, err := cmd.StdinPipe()
-	if err != nil {
-		return "", err
-	}
-
-	go func() {
-		defer stdin.Close()
-		io.WriteString(stdin, cert)
-	}()
-
-	out, err := cmd.CombinedOutput()
-
-	return strings.TrimSpace(string(out)), err
-}
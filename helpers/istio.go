@@ -1,21 +1,25 @@
 package helpers
 
 import (
-	"fmt"
-	"io/ioutil"
-	"os"
+	"bytes"
 	"text/template"
-)
 
-// CreateIstioIngressGateway creates a temporary ingress gateway definition for teh specified service
-func CreateIstioIngressGateway(name string, namespace string, host string, serviceHost string, servicePort int) (string, error) {
+	"github.com/fuseml/fuseml/cli/kubernetes"
+)
 
-	tmpFile, err := ioutil.TempFile("", "fuseml")
+// CreateIstioIngressGateway creates an ingress gateway and virtual service for the specified service
+func CreateIstioIngressGateway(c *kubernetes.Cluster, name string, namespace string, host string, serviceHost string, servicePort int) error {
+	manifest, err := IstioIngressGateway(name, namespace, host, serviceHost, servicePort)
 	if err != nil {
-		return tmpFile.Name(), err
+		return err
 	}
-	defer os.Remove(tmpFile.Name())
 
+	return c.ApplyManifest(manifest, namespace)
+}
+
+// IstioIngressGateway returns the manifest of an ingress gateway and virtual
+// service for the specified service
+func IstioIngressGateway(name string, namespace string, host string, serviceHost string, servicePort int) ([]byte, error) {
 	istioGatewayTmpl, err := template.New("istiogw").Parse(`
 ---
 apiVersion: networking.istio.io/v1alpha3
@@ -58,10 +62,11 @@ spec:
         host: {{ .ServiceHost }}
 `)
 	if err != nil {
-		return tmpFile.Name(), err
+		return nil, err
 	}
 
-	err = istioGatewayTmpl.Execute(tmpFile, struct {
+	var manifest bytes.Buffer
+	err = istioGatewayTmpl.Execute(&manifest, struct {
 		Name        string
 		Namespace   string
 		Host        string
@@ -75,8 +80,8 @@ spec:
 		ServicePort: servicePort,
 	})
 	if err != nil {
-		return tmpFile.Name(), err
+		return nil, err
 	}
 
-	return Kubectl(fmt.Sprintf("apply --filename %s", tmpFile.Name()))
+	return manifest.Bytes(), nil
 }
@@ -0,0 +1,217 @@
+package helpers
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// ASN.1 universal tags of the string types OpenSSL canonicalizes
+const (
+	tagUTF8String      = 12
+	tagPrintableString = 19
+	tagT61String       = 20
+	tagIA5String       = 22
+	tagVisibleString   = 26
+	tagUniversalString = 28
+	tagBMPString       = 30
+)
+
+// OpenSSLSubjectHash return the subject_hash of the given PEM encoded CA
+// certificate, as returned by this command:
+// openssl x509 -hash -noout
+// https://www.openssl.org/docs/man1.0.2/man1/x509.html
+// It is the little-endian value of the first four bytes of the SHA1 of the
+// canonical encoding of the subject (see x509_name_canon in OpenSSL).
+func OpenSSLSubjectHash(cert string) (string, error) {
+	block, _ := pem.Decode([]byte(cert))
+	if block == nil {
+		return "", errors.New("no PEM encoded certificate found")
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse certificate")
+	}
+
+	canonical, err := canonicalName(certificate.RawSubject)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to canonicalize certificate subject")
+	}
+
+	sum := sha1.Sum(canonical)
+
+	return fmt.Sprintf("%08x", binary.LittleEndian.Uint32(sum[:4])), nil
+}
+
+// canonicalName returns the concatenated DER encoding of the RDNs of the
+// name, with their string values converted to lowercase UTF8Strings and
+// whitespace normalized. The outer SEQUENCE is left out.
+func canonicalName(rawName []byte) ([]byte, error) {
+	var name asn1.RawValue
+	if _, err := asn1.Unmarshal(rawName, &name); err != nil {
+		return nil, err
+	}
+
+	result := []byte{}
+	rdns := name.Bytes
+	for len(rdns) > 0 {
+		var rdn asn1.RawValue
+		var err error
+		rdns, err = asn1.Unmarshal(rdns, &rdn)
+		if err != nil {
+			return nil, err
+		}
+
+		attributes := [][]byte{}
+		values := rdn.Bytes
+		for len(values) > 0 {
+			var attribute asn1.RawValue
+			values, err = asn1.Unmarshal(values, &attribute)
+			if err != nil {
+				return nil, err
+			}
+
+			encoded, err := canonicalAttribute(attribute.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			attributes = append(attributes, encoded)
+		}
+
+		// DER orders the members of a SET OF by their encoding
+		sort.Slice(attributes, func(i, j int) bool {
+			return bytes.Compare(attributes[i], attributes[j]) < 0
+		})
+
+		set, err := asn1.Marshal(asn1.RawValue{
+			Tag:        asn1.TagSet,
+			IsCompound: true,
+			Bytes:      bytes.Join(attributes, nil),
+		})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, set...)
+	}
+
+	return result, nil
+}
+
+// canonicalAttribute encodes an AttributeTypeAndValue, given the contents of
+// its SEQUENCE, with its value canonicalized
+func canonicalAttribute(contents []byte) ([]byte, error) {
+	var oid, value asn1.RawValue
+	rest, err := asn1.Unmarshal(contents, &oid)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = asn1.Unmarshal(rest, &value); err != nil {
+		return nil, err
+	}
+
+	encodedValue := value.FullBytes
+	if value.Class == asn1.ClassUniversal {
+		text, ok, err := decodeString(value.Tag, value.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			encodedValue, err = asn1.Marshal(asn1.RawValue{
+				Tag:   tagUTF8String,
+				Bytes: canonicalString(text),
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return asn1.Marshal(asn1.RawValue{
+		Tag:        asn1.TagSequence,
+		IsCompound: true,
+		Bytes:      append(append([]byte{}, oid.FullBytes...), encodedValue...),
+	})
+}
+
+// decodeString returns the UTF-8 contents of the ASN.1 string types OpenSSL
+// canonicalizes. ok is false for other types, which are kept as they are.
+func decodeString(tag int, contents []byte) (text []byte, ok bool, err error) {
+	switch tag {
+	case tagUTF8String, tagPrintableString, tagIA5String, tagVisibleString:
+		return contents, true, nil
+	case tagT61String:
+		// Handled as Latin-1, like OpenSSL does
+		runes := make([]rune, len(contents))
+		for i, b := range contents {
+			runes[i] = rune(b)
+		}
+		return []byte(string(runes)), true, nil
+	case tagBMPString:
+		if len(contents)%2 != 0 {
+			return nil, false, errors.New("invalid BMPString length")
+		}
+		units := make([]uint16, len(contents)/2)
+		for i := range units {
+			units[i] = binary.BigEndian.Uint16(contents[2*i:])
+		}
+		return []byte(string(utf16.Decode(units))), true, nil
+	case tagUniversalString:
+		if len(contents)%4 != 0 {
+			return nil, false, errors.New("invalid UniversalString length")
+		}
+		text := []byte{}
+		for i := 0; i < len(contents); i += 4 {
+			text = append(text, []byte(string(rune(binary.BigEndian.Uint32(contents[i:]))))...)
+		}
+		return text, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// canonicalString trims leading and trailing whitespace, collapses inner
+// whitespace runs into a single space and lowercases ASCII letters. Bytes
+// outside of ASCII are copied unchanged.
+func canonicalString(text []byte) []byte {
+	text = bytes.TrimFunc(text, func(r rune) bool {
+		return r < utf8.RuneSelf && isSpace(byte(r))
+	})
+
+	result := make([]byte, 0, len(text))
+	for i := 0; i < len(text); i++ {
+		b := text[i]
+		switch {
+		case b >= utf8.RuneSelf:
+			result = append(result, b)
+		case isSpace(b):
+			result = append(result, ' ')
+			for i+1 < len(text) && isSpace(text[i+1]) {
+				i++
+			}
+		case 'A' <= b && b <= 'Z':
+			result = append(result, b+'a'-'A')
+		default:
+			result = append(result, b)
+		}
+	}
+
+	return result
+}
+
+func isSpace(b byte) bool {
+	switch b {
+	case ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+	return false
+}
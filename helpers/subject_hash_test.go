@@ -0,0 +1,35 @@
+package helpers_test
+
+import (
+	"io/ioutil"
+
+	. "github.com/fuseml/fuseml/cli/helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OpenSSLSubjectHash", func() {
+	// Expected values as printed by `openssl x509 -hash -noout -in FILE`
+	DescribeTable("matches openssl",
+		func(file, expected string) {
+			cert, err := ioutil.ReadFile(FixturePath("certs/" + file))
+			Expect(err).ToNot(HaveOccurred())
+
+			hash, err := OpenSSLSubjectHash(string(cert))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hash).To(Equal(expected))
+		},
+		Entry("single PrintableString CN", "simple.pem", "cb35e44e"),
+		Entry("certificate generated by openssl", "openssl.pem", "cc23108a"),
+		Entry("mixed case and extra whitespace", "whitespace.pem", "d99f0629"),
+		Entry("multi-valued RDN", "multivalued.pem", "36f91d1d"),
+		Entry("non-ASCII UTF8String", "utf8.pem", "da3b6442"),
+		Entry("T61, BMP, IA5 and NumericString values", "encodings.pem", "cdfa50fa"),
+	)
+
+	It("fails on input which is not a PEM certificate", func() {
+		_, err := OpenSSLSubjectHash("not a certificate")
+		Expect(err).To(HaveOccurred())
+	})
+})
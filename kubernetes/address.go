@@ -0,0 +1,54 @@
+package kubernetes
+
+import (
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+)
+
+// HTTPPort is the port the ingress serves plain HTTP at, behind a load
+// balancer
+const HTTPPort = 80
+
+// WildcardDNSProviders are the known services resolving <IP>.<suffix> to IP.
+// Any other suffix of such a service can be used too.
+var WildcardDNSProviders = []string{"omg.howdoi.website", "nip.io", "sslip.io"}
+
+// LoadBalancerIP returns the IP of the load balancer of the service, if it
+// has one
+func LoadBalancerIP(service *v1.Service) string {
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			return ingress.IP
+		}
+	}
+	return ""
+}
+
+// NodePortAddress returns the first of the node IPs and the node port of
+// the HTTP port of the service, which LoadBalancer services get too. The
+// HTTP port is the one named web, as in the traefik chart, or http2, as in
+// the istio ingress gateway, or else port 80.
+func NodePortAddress(service *v1.Service, nodeIPs []string) (string, int32, error) {
+	if service.Spec.Type != v1.ServiceTypeNodePort && service.Spec.Type != v1.ServiceTypeLoadBalancer {
+		return "", 0, errors.Errorf("service %s is of type %s, without node ports", service.Name, service.Spec.Type)
+	}
+	if len(nodeIPs) == 0 {
+		return "", 0, errors.New("no node IPs found")
+	}
+
+	var nodePort int32
+	for _, port := range service.Spec.Ports {
+		if port.Name == "web" || port.Name == "http2" {
+			nodePort = port.NodePort
+			break
+		}
+		if port.Port == HTTPPort && nodePort == 0 {
+			nodePort = port.NodePort
+		}
+	}
+	if nodePort == 0 {
+		return "", 0, errors.Errorf("service %s has no node port for HTTP", service.Name)
+	}
+
+	return nodeIPs[0], nodePort, nil
+}
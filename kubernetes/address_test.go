@@ -0,0 +1,67 @@
+package kubernetes_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/fuseml/fuseml/cli/kubernetes"
+)
+
+var _ = Describe("Ingress addresses", func() {
+	var service *v1.Service
+
+	BeforeEach(func() {
+		service = &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "traefik"},
+			Spec: v1.ServiceSpec{
+				Type: v1.ServiceTypeLoadBalancer,
+				Ports: []v1.ServicePort{
+					{Name: "websecure", Port: 443, NodePort: 31443},
+					{Name: "web", Port: 80, NodePort: 31080},
+				},
+			},
+		}
+	})
+
+	Describe("LoadBalancerIP", func() {
+		It("is empty while the load balancer is pending", func() {
+			Expect(LoadBalancerIP(service)).To(BeEmpty())
+		})
+
+		It("returns the IP of the load balancer", func() {
+			service.Status.LoadBalancer.Ingress = []v1.LoadBalancerIngress{{Hostname: "lb.example.com"}, {IP: "10.0.0.1"}}
+			Expect(LoadBalancerIP(service)).To(Equal("10.0.0.1"))
+		})
+	})
+
+	Describe("NodePortAddress", func() {
+		It("returns a node IP and the node port of the web port", func() {
+			ip, port, err := NodePortAddress(service, []string{"172.18.0.2", "172.18.0.3"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ip).To(Equal("172.18.0.2"))
+			Expect(port).To(BeEquivalentTo(31080))
+		})
+
+		It("falls back to the node port of port 80", func() {
+			service.Spec.Type = v1.ServiceTypeNodePort
+			service.Spec.Ports = []v1.ServicePort{{Name: "http", Port: 80, NodePort: 30080}}
+			_, port, err := NodePortAddress(service, []string{"172.18.0.2"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(port).To(BeEquivalentTo(30080))
+		})
+
+		It("fails without node IPs", func() {
+			_, _, err := NodePortAddress(service, nil)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("fails for cluster IP services", func() {
+			service.Spec.Type = v1.ServiceTypeClusterIP
+			_, _, err := NodePortAddress(service, []string{"172.18.0.2"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -1,8 +1,6 @@
 package kubernetes
 
 import (
-	"strings"
-
 	"github.com/spf13/cobra"
 )
 
@@ -21,8 +19,7 @@ func NewCLIOptionsReader(cmd *cobra.Command) CLIOptionsReader {
 // appropriate (Go) type as defined by the Type field of the
 // InstallationOption. Does nothing if no cobra flag is found.
 func (reader CLIOptionsReader) Read(option *InstallationOption) error {
-	// Translate option name
-	flagName := strings.ReplaceAll(option.Name, "_", "-")
+	flagName := option.FlagName()
 
 	// Get option value. The default is considered as `not set`,
 	// forcing use of the interactive reader.  This is a hack I m
@@ -64,6 +61,7 @@ func (reader CLIOptionsReader) Read(option *InstallationOption) error {
 	if cliValid {
 		option.Value = cliValue
 		option.UserSpecified = true
+		option.Source = SourceCLI
 	}
 
 	return nil
@@ -117,6 +117,26 @@ var _ = Describe("CLIOptionsReader", func() {
 			})
 		})
 
+		When("handling a private option", func() {
+			It("reads the flag prefixed with the deployment", func() {
+				cmd := &cobra.Command{Use: "private"}
+				options := InstallationOptions{
+					{Name: "a_text", Default: "", Type: StringType},
+					{Name: "a_text", Default: "", Type: StringType, DeploymentID: "mydeployment"},
+				}
+				options.AsCobraFlagsFor(cmd)
+				Expect(cmd.Flags().Set("mydeployment-a-text", "private text")).To(Succeed())
+
+				reader := NewCLIOptionsReader(cmd)
+				Expect(reader.Read(&options[0])).To(Succeed())
+				Expect(reader.Read(&options[1])).To(Succeed())
+
+				Expect(options[0].UserSpecified).To(BeFalse())
+				Expect(options[1].Value).To(Equal("private text"))
+				Expect(options[1].UserSpecified).To(BeTrue())
+			})
+		})
+
 		When("handling a boolean flag", func() {
 			It("returns a boolean", func() {
 				err := reader.Read(&optionFlag)
@@ -5,33 +5,39 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net/http"
 	"strings"
-	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/fuseml/fuseml/cli/kubernetes/platform"
 	generic "github.com/fuseml/fuseml/cli/kubernetes/platform/generic"
 	ibm "github.com/fuseml/fuseml/cli/kubernetes/platform/ibm"
 	k3s "github.com/fuseml/fuseml/cli/kubernetes/platform/k3s"
 	kind "github.com/fuseml/fuseml/cli/kubernetes/platform/kind"
+	microk8s "github.com/fuseml/fuseml/cli/kubernetes/platform/microk8s"
 	minikube "github.com/fuseml/fuseml/cli/kubernetes/platform/minikube"
-	"github.com/fuseml/fuseml/cli/paas/ui"
+	openshift "github.com/fuseml/fuseml/cli/kubernetes/platform/openshift"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/portforward"
 	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
 
 	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
-	tektonv1beta "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/typed/pipeline/v1beta1"
-	"knative.dev/pkg/apis"
 
 	// https://github.com/kubernetes/client-go/issues/345
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
@@ -53,13 +59,20 @@ type Platform interface {
 	String() string
 	Load(*kubernetes.Clientset) error
 	ExternalIPs() []string
+	// Profile returns the option values and component tweaks of the
+	// platform, as found by Load
+	Profile() platform.Profile
 }
 
+// SupportedPlatforms are detected in order. OpenShift comes before IBM, as
+// it runs on IBM Cloud too.
 var SupportedPlatforms []Platform = []Platform{
 	kind.NewPlatform(),
 	k3s.NewPlatform(),
+	openshift.NewPlatform(),
 	ibm.NewPlatform(),
 	minikube.NewPlatform(),
+	microk8s.NewPlatform(),
 }
 
 type Cluster struct {
@@ -69,6 +82,8 @@ type Cluster struct {
 	RestConfig *restclient.Config
 	platform   Platform
 	TektonCS   *versioned.Clientset
+	Dynamic    dynamic.Interface
+	Mapper     meta.RESTMapper
 }
 
 // NewClusterFromClient creates a new Cluster from a Kubernetes rest client config
@@ -86,6 +101,11 @@ func NewClusterFromClient(restConfig *restclient.Config) (*Cluster, error) {
 		c.platform = generic.NewPlatform()
 	}
 
+	err = c.connectDynamic()
+	if err != nil {
+		return nil, err
+	}
+
 	cs, err := versioned.NewForConfig(restConfig)
 	if err != nil {
 		fmt.Printf("failed to create tekton pipeline client %s", err)
@@ -121,97 +141,35 @@ func (c *Cluster) Connect(config string) error {
 		c.platform = generic.NewPlatform()
 	}
 
-	err = c.platform.Load(clientset)
-	if err == nil {
-		fmt.Println(c.platform.Describe())
+	err = c.connectDynamic()
+	if err != nil {
+		return err
 	}
-	return err
-}
 
-func (c *Cluster) detectPlatform() {
-	for _, p := range SupportedPlatforms {
-		if p.Detect(c.Kubectl) {
-			c.platform = p
-			return
-		}
-	}
+	return c.platform.Load(clientset)
 }
 
-// IsPodRunningAndReady returns a condition function that indicates whether the given pod is
-// currently running and ready
-func (c *Cluster) IsPodRunningAndReady(podName, namespace string) wait.ConditionFunc {
-	return func() (bool, error) {
-		pod, err := c.Kubectl.CoreV1().Pods(namespace).Get(context.Background(), podName, metav1.GetOptions{})
-		if err != nil {
-			return false, err
-		}
-
-		for _, cont := range pod.Status.ContainerStatuses {
-			if cont.State.Waiting != nil {
-				//fmt.Println("containers still in waiting")
-				return false, nil
-			}
-		}
-
-		for _, cont := range pod.Status.InitContainerStatuses {
-			if cont.State.Waiting != nil || cont.State.Running != nil {
-				return false, nil
-			}
-		}
-
-		for _, cont := range pod.Status.ContainerStatuses {
-			if cont.Ready != true {
-				return false, nil
-			}
-		}
-
-		switch pod.Status.Phase {
-		case v1.PodRunning, v1.PodSucceeded:
-			return true, nil
-		case v1.PodFailed:
-			return false, nil
-		}
-		return false, nil
+// connectDynamic creates the clients used for objects of arbitrary kinds
+func (c *Cluster) connectDynamic() error {
+	dynamicClient, err := dynamic.NewForConfig(c.RestConfig)
+	if err != nil {
+		return err
 	}
-}
+	c.Dynamic = dynamicClient
+	c.Mapper = restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(c.Kubectl.Discovery()))
 
-func (c *Cluster) PipelineRunSucceeded(pipelineRunClient tektonv1beta.PipelineRunInterface, pipelineRunName string) wait.ConditionFunc {
-	return func() (bool, error) {
-		pr, err := pipelineRunClient.Get(context.Background(), pipelineRunName, metav1.GetOptions{})
-		if err != nil {
-			return false, err
-		}
-		c := pr.Status.GetCondition(apis.ConditionSucceeded)
-		if c != nil {
-			if c.Status == v1.ConditionTrue || c.Status == v1.ConditionFalse {
-				return true, nil
-			} else if c.Status == v1.ConditionUnknown && (c.Reason == "Running" || c.Reason == "Pending") {
-				return false, nil
-			}
-		}
-		return false, nil
-	}
+	return nil
 }
 
-func (c *Cluster) PodExists(namespace, selector string) wait.ConditionFunc {
-	return func() (bool, error) {
-		podList, err := c.ListPods(namespace, selector)
-		if err != nil {
-			return false, err
-		}
-		if len(podList.Items) == 0 {
-			return false, nil
+func (c *Cluster) detectPlatform() {
+	for _, p := range SupportedPlatforms {
+		if p.Detect(c.Kubectl) {
+			c.platform = p
+			return
 		}
-		return true, nil
 	}
 }
 
-// Poll up to timeout seconds for pod to enter running and ready state.
-// Returns an error if the pod never enters such state.
-func (c *Cluster) WaitForPodRunning(namespace, podName string, timeout time.Duration) error {
-	return wait.PollImmediate(time.Second, timeout, c.IsPodRunningAndReady(podName, namespace))
-}
-
 // ListPods returns the list of currently scheduled or running pods in `namespace` with the given selector
 func (c *Cluster) ListPods(namespace, selector string) (*v1.PodList, error) {
 	listOptions := metav1.ListOptions{}
@@ -225,107 +183,6 @@ func (c *Cluster) ListPods(namespace, selector string) (*v1.PodList, error) {
 	return podList, nil
 }
 
-// Wait up to timeout seconds for all pods in 'namespace' with given 'selector' to enter running state.
-// Returns an error if no pods are found or not all discovered pods enter running state.
-func (c *Cluster) WaitUntilPodBySelectorExist(ui *ui.UI, namespace, selector string, timeout int) error {
-	s := ui.Progressf("Creating %s in %s", selector, namespace)
-	defer s.Stop()
-
-	return wait.PollImmediate(time.Second, time.Duration(timeout)*time.Second, c.PodExists(namespace, selector))
-}
-
-// Wait up to timeout seconds for pipelinerun in 'namespace' with given 'selector' to enter running state.
-func (c *Cluster) PipelineRunExists(namespace, selector string) wait.ConditionFunc {
-
-	pipelineRunClient := c.TektonCS.TektonV1beta1().PipelineRuns(namespace)
-
-	return func() (bool, error) {
-
-		listOptions := metav1.ListOptions{}
-		if len(selector) > 0 {
-			listOptions.LabelSelector = selector
-		}
-		pipelineRunList, err := pipelineRunClient.List(context.Background(), listOptions)
-
-		if err != nil {
-			return false, err
-		}
-		if len(pipelineRunList.Items) == 0 {
-			return false, nil
-		}
-
-		return true, nil
-	}
-}
-
-func (c *Cluster) WaitUntilPipelineRunExists(ui *ui.UI, namespace, selector string, timeout int) error {
-	s := ui.Progressf("Checking existence of PipelineRun identified by %s in %s", selector, namespace)
-	defer s.Stop()
-
-	return wait.PollImmediate(time.Second, time.Duration(timeout)*time.Second, c.PipelineRunExists(namespace, selector))
-}
-
-// WaitForPodBySelectorRunning waits timeout seconds for all pods in 'namespace'
-// with given 'selector' to enter running state. Returns an error if no pods are
-// found or not all discovered pods enter running state.
-func (c *Cluster) WaitForPodBySelectorRunning(ui *ui.UI, namespace, selector string, timeout int) error {
-	s := ui.Progressf("Starting %s in %s", selector, namespace)
-	defer s.Stop()
-
-	podList, err := c.ListPods(namespace, selector)
-	if err != nil {
-		return errors.Wrapf(err, "failed listingpods with selector %s", selector)
-	}
-
-	if len(podList.Items) == 0 {
-		return fmt.Errorf("no pods in %s with selector %s", namespace, selector)
-	}
-
-	for _, pod := range podList.Items {
-		s.ChangeMessagef("  Starting pod %s in %s", pod.Name, namespace)
-		if err := c.WaitForPodRunning(namespace, pod.Name, time.Duration(timeout)*time.Second); err != nil {
-			events, err2 := c.GetPodEvents(namespace, pod.Name)
-			if err2 != nil {
-				return errors.Wrap(err, err2.Error())
-			} else {
-				return errors.New(fmt.Sprintf("Failed waiting for %s: %s\nPod Events: \n%s", pod.Name, err.Error(), events))
-			}
-		}
-	}
-	return nil
-}
-
-// Wait timeout seconds for given pipelinerun to enter status 'Succeeded'
-func (c *Cluster) WaitForPipelineRunSuccess(ui *ui.UI, namespace, selector string, timeout int) error {
-	s := ui.Progressf("Starting %s in %s", selector, namespace)
-	defer s.Stop()
-
-	pipelineRunClient := c.TektonCS.TektonV1beta1().PipelineRuns(namespace)
-	listOptions := metav1.ListOptions{}
-	if len(selector) > 0 {
-		listOptions.LabelSelector = selector
-	}
-	pipelineRunList, err := pipelineRunClient.List(context.Background(), listOptions)
-
-	if err != nil {
-		return errors.Wrapf(err, "Failed listing PipelineRuns with selector %s", selector)
-	}
-
-	if len(pipelineRunList.Items) == 0 {
-		return fmt.Errorf("No pods in %s with selector %s", namespace, selector)
-	}
-
-	name := pipelineRunList.Items[0].Name
-
-	// wait until it finishes or times out
-	err = wait.PollImmediate(time.Second, time.Duration(timeout)*time.Second, c.PipelineRunSucceeded(pipelineRunClient, name))
-
-	if err != nil {
-		return errors.New(fmt.Sprintf("Failed waiting for PipelineRun (%s) to succeed %s: ", selector, err.Error()))
-	}
-	return nil
-}
-
 // GetPodEventsWithSelector tries to find a pod using the provided selector and
 // namespace. If found it returns the events on that Pod. If not found it returns
 // an error.
@@ -489,6 +346,46 @@ func (c *Cluster) execPod(namespace, podName, containerName string,
 	return nil
 }
 
+// PortForward forwards a random local port to the given port of the pod. It
+// returns the local port and a function which stops the forwarding.
+func (c *Cluster) PortForward(namespace, podName string, port int) (int, func(), error) {
+	transport, upgrader, err := spdy.RoundTripperFor(c.RestConfig)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	req := c.Kubectl.CoreV1().RESTClient().Post().Resource("pods").Name(podName).
+		Namespace(namespace).SubResource("portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
+
+	stopChan := make(chan struct{}, 1)
+	readyChan := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"},
+		[]string{fmt.Sprintf("0:%d", port)}, stopChan, readyChan, ioutil.Discard, ioutil.Discard)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- forwarder.ForwardPorts()
+	}()
+
+	select {
+	case err := <-errChan:
+		return 0, nil, errors.Wrapf(err, "failed to forward port %d of pod %s", port, podName)
+	case <-readyChan:
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil {
+		close(stopChan)
+		return 0, nil, err
+	}
+
+	return int(ports[0].Local), func() { close(stopChan) }, nil
+}
+
 // LabelNamespace adds a label to the namespace
 func (c *Cluster) LabelNamespace(namespace, labelKey, labelValue string) error {
 	patchContents := fmt.Sprintf(`{ "metadata": { "labels": { "%s": "%s" } } }`, labelKey, labelValue)
@@ -15,9 +15,23 @@ func KubeConfigFlags(pf *pflag.FlagSet, argToEnv map[string]string) {
 	argToEnv["kubeconfig"] = "KUBECONFIG"
 }
 
+// Location is where the kube config is read from: the kubeconfig Path, or
+// the default locations if empty, and the Context in it, or its current
+// context if empty
+type Location struct {
+	Path    string
+	Context string
+}
+
+// DefaultLocation returns the location given by the kubeconfig flag or the
+// KUBECONFIG env
+func DefaultLocation() Location {
+	return Location{Path: viper.GetString("kubeconfig")}
+}
+
 // KubeConfig uses kubeconfig pkg to return a valid kube config
-func KubeConfig() (*rest.Config, error) {
-	restConfig, err := NewGetter().Get(viper.GetString("kubeconfig"))
+func KubeConfig(location Location) (*rest.Config, error) {
+	restConfig, err := NewGetter().Get(location.Path, location.Context)
 	if err != nil {
 		return nil, errors.Wrap(err, "couldn't fetch kubeconfig; ensure kubeconfig is present to continue")
 	}
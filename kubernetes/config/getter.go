@@ -13,7 +13,7 @@ import (
 // Getter is the interface that wraps the Get method that returns the Kubernetes configuration used
 // to communicate with it using its API.
 type Getter interface {
-	Get(configPath string) (*rest.Config, error)
+	Get(configPath, context string) (*rest.Config, error)
 }
 
 // NewGetter constructs a default getter that satisfies the Getter interface.
@@ -37,10 +37,11 @@ type getter struct {
 	defaultRESTConfig        func() (*rest.Config, error)
 }
 
-func (g *getter) Get(configPath string) (*rest.Config, error) {
+func (g *getter) Get(configPath, context string) (*rest.Config, error) {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: context}
 	if configPath == "" {
-		c, err := g.restConfigFromKubeConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+		c, err := g.restConfigFromKubeConfig(loadingRules, overrides).ClientConfig()
 		if err != nil {
 			return nil, &getConfigError{err}
 		}
@@ -57,7 +58,7 @@ func (g *getter) Get(configPath string) (*rest.Config, error) {
 			loadingRules.Precedence = paths
 		}
 	}
-	c, err := g.restConfigFromKubeConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	c, err := g.restConfigFromKubeConfig(loadingRules, overrides).ClientConfig()
 	if err != nil {
 		return nil, &getConfigError{err}
 	}
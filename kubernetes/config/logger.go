@@ -32,6 +32,11 @@ func NewInstallClientLogger() logr.Logger {
 	return NewLogger().WithName("InstallClient")
 }
 
+// New creates a new logger with our setup
+func NewLoginClientLogger() logr.Logger {
+	return NewLogger().WithName("LoginClient")
+}
+
 // New creates a new logger with our setup
 func NewLogger() logr.Logger {
 	stdr.SetVerbosity(TraceLevel())
@@ -19,5 +19,6 @@ func (reader DefaultOptionsReader) Read(option *InstallationOption) error {
 		return nil
 	}
 
+	option.Source = SourceDefault
 	return option.SetDefault()
 }
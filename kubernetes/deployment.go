@@ -8,10 +8,33 @@ import (
 type Deployment interface {
 	Deploy(*Cluster, *ui.UI, InstallationOptions) error
 	Upgrade(*Cluster, *ui.UI, InstallationOptions) error
-	Delete(*Cluster, *ui.UI) error
+	// Render writes everything Deploy would apply into the given directory,
+	// without modifying the cluster
+	Render(*Cluster, *ui.UI, InstallationOptions, string) error
+	// Delete removes the deployment, or with DryRun only lists what it
+	// would remove
+	Delete(*Cluster, *ui.UI, DeleteOptions) error
+	// Options returns the installation options of the deployment, private
+	// to it, i.e. with their DeploymentID set to its ID
+	Options() InstallationOptions
 	Describe() string
+	// Health checks the installed deployment, e.g. that its pods are
+	// ready and its endpoints reachable
+	Health(*Cluster) []HealthCheck
 	GetVersion() string
 	Restore(*Cluster, *ui.UI, string) error
 	Backup(*Cluster, *ui.UI, string) error
 	ID() string
+	// Needs returns the IDs of the deployments which have to be installed
+	// before this one
+	Needs() []string
+}
+
+// DeleteOptions tune the removal of a deployment
+type DeleteOptions struct {
+	// DryRun lists what would be removed, without removing anything
+	DryRun bool
+	// KeepData retains the persistent volumes of the deployment, i.e. its
+	// databases, repositories and buckets, for a later reinstall
+	KeepData bool
 }
@@ -0,0 +1,263 @@
+package kubernetes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Deployments is a list of deployments, which may need each other, see
+// Deployment.Needs
+type Deployments []Deployment
+
+// DeploymentError is the failure of a single deployment of a list
+type DeploymentError struct {
+	ID  string
+	Err error
+}
+
+func (e *DeploymentError) Error() string {
+	return e.ID + ": " + e.Err.Error()
+}
+
+// DeploymentErrors collects the failures of the deployments acted on by
+// Deployments.Concurrently, so that they can be reported at once
+type DeploymentErrors []*DeploymentError
+
+func (errs DeploymentErrors) Error() string {
+	problems := []string{}
+	for _, err := range errs {
+		problems = append(problems, "  - "+err.Error())
+	}
+
+	return "failed components:\n" + strings.Join(problems, "\n")
+}
+
+// IDs returns the IDs of the deployments, in order
+func (ds Deployments) IDs() []string {
+	ids := []string{}
+	for _, d := range ds {
+		ids = append(ids, d.ID())
+	}
+
+	return ids
+}
+
+// Get returns the deployment with the given ID
+func (ds Deployments) Get(id string) (Deployment, bool) {
+	for _, d := range ds {
+		if d.ID() == id {
+			return d, true
+		}
+	}
+
+	return nil, false
+}
+
+// Select returns the deployments with an ID in only, or all of them if only
+// is empty, except for those with an ID in skip. It fails for unknown IDs.
+func (ds Deployments) Select(only, skip []string) (Deployments, error) {
+	for _, id := range append(append([]string{}, only...), skip...) {
+		if _, ok := ds.Get(id); !ok {
+			return nil, errors.Errorf("unknown component '%s', expected one of %s", id, strings.Join(ds.IDs(), ", "))
+		}
+	}
+
+	result := Deployments{}
+	for _, d := range ds {
+		if (len(only) == 0 || contains(only, d.ID())) && !contains(skip, d.ID()) {
+			result = append(result, d)
+		}
+	}
+
+	return result, nil
+}
+
+// Sorted returns the deployments ordered so that each one comes after the
+// deployments it needs. Deployments which don't need each other keep their
+// order. Needed deployments missing from the list are ignored, see Missing.
+func (ds Deployments) Sorted() (Deployments, error) {
+	result := Deployments{}
+	placed := map[string]bool{}
+
+	for len(result) < len(ds) {
+		progress := false
+		for _, d := range ds {
+			if placed[d.ID()] || !ds.neededPlaced(d, placed) {
+				continue
+			}
+			result = append(result, d)
+			placed[d.ID()] = true
+			progress = true
+			break
+		}
+
+		if !progress {
+			cycle := []string{}
+			for _, d := range ds {
+				if !placed[d.ID()] {
+					cycle = append(cycle, d.ID())
+				}
+			}
+			return nil, errors.Errorf("components %s need each other", strings.Join(cycle, ", "))
+		}
+	}
+
+	return result, nil
+}
+
+// Missing returns the deployments needed by those of the list, which are not
+// part of it, as messages like `tekton needs fuseml-registry`
+func (ds Deployments) Missing() []string {
+	missing := []string{}
+	for _, d := range ds {
+		for _, needed := range d.Needs() {
+			if _, ok := ds.Get(needed); !ok {
+				missing = append(missing, fmt.Sprintf("%s needs %s", d.ID(), needed))
+			}
+		}
+	}
+
+	return missing
+}
+
+// Reversed returns the deployments in reverse order, e.g. to remove sorted
+// deployments
+func (ds Deployments) Reversed() Deployments {
+	result := Deployments{}
+	for i := len(ds) - 1; i >= 0; i-- {
+		result = append(result, ds[i])
+	}
+
+	return result
+}
+
+// Concurrently calls action for each deployment of the list, as soon as the
+// deployments it needs are done, with at most limit actions running at the
+// same time. A limit below 1 means no limit. Deployments needing one which
+// failed are not acted on, skipped is called for them instead. The failures
+// are returned as DeploymentErrors, in the order of the list.
+func (ds Deployments) Concurrently(limit int, action func(Deployment) error, skipped func(Deployment, error)) error {
+	if limit < 1 {
+		limit = len(ds)
+	}
+
+	type result struct {
+		id  string
+		err error
+	}
+	results := make(chan result)
+
+	started := map[string]bool{}
+	done := map[string]error{}
+	running := 0
+	errs := DeploymentErrors{}
+
+	for len(done) < len(ds) {
+		for changed := true; changed; {
+			changed = false
+			for _, d := range ds {
+				if started[d.ID()] {
+					continue
+				}
+
+				ready, failed := ds.neededDone(d, done)
+				switch {
+				case failed != "":
+					err := errors.Errorf("skipped, as it needs %s, which failed", failed)
+					started[d.ID()] = true
+					done[d.ID()] = err
+					errs = append(errs, &DeploymentError{ID: d.ID(), Err: err})
+					if skipped != nil {
+						skipped(d, err)
+					}
+					changed = true
+				case ready && running < limit:
+					started[d.ID()] = true
+					running++
+					go func(d Deployment) {
+						results <- result{id: d.ID(), err: action(d)}
+					}(d)
+				}
+			}
+		}
+
+		if len(done) == len(ds) {
+			break
+		}
+		if running == 0 {
+			cycle := []string{}
+			for _, d := range ds {
+				if !started[d.ID()] {
+					cycle = append(cycle, d.ID())
+				}
+			}
+			return errors.Errorf("components %s need each other", strings.Join(cycle, ", "))
+		}
+
+		r := <-results
+		running--
+		done[r.id] = r.err
+		if r.err != nil {
+			errs = append(errs, &DeploymentError{ID: r.id, Err: r.err})
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	order := map[string]int{}
+	for i, d := range ds {
+		order[d.ID()] = i
+	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		return order[errs[i].ID] < order[errs[j].ID]
+	})
+
+	return errs
+}
+
+// neededDone tells whether all deployments needed by d, which are part of the
+// list, are done. failed names one of them which failed, if any.
+func (ds Deployments) neededDone(d Deployment, done map[string]error) (ready bool, failed string) {
+	ready = true
+	for _, needed := range d.Needs() {
+		if _, ok := ds.Get(needed); !ok {
+			continue
+		}
+		err, finished := done[needed]
+		if finished && err != nil {
+			return false, needed
+		}
+		if !finished {
+			ready = false
+		}
+	}
+
+	return ready, ""
+}
+
+// neededPlaced tells whether all deployments needed by d, which are part of
+// the list, are placed already
+func (ds Deployments) neededPlaced(d Deployment, placed map[string]bool) bool {
+	for _, needed := range d.Needs() {
+		if _, ok := ds.Get(needed); ok && !placed[needed] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
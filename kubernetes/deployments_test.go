@@ -0,0 +1,170 @@
+package kubernetes_test
+
+import (
+	"errors"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fuseml/fuseml/cli/kubernetes"
+	"github.com/fuseml/fuseml/cli/kubernetes/kubernetesfakes"
+)
+
+var _ = Describe("Deployments", func() {
+	deployment := func(id string, needs ...string) Deployment {
+		d := &kubernetesfakes.FakeDeployment{}
+		d.IDReturns(id)
+		d.NeedsReturns(needs)
+		return d
+	}
+
+	var all Deployments
+
+	BeforeEach(func() {
+		all = Deployments{
+			deployment("tekton", "workloads", "registry"),
+			deployment("traefik"),
+			deployment("registry", "quarks", "workloads"),
+			deployment("workloads"),
+			deployment("quarks"),
+		}
+	})
+
+	Describe("Sorted", func() {
+		It("places deployments after those they need, keeping the order otherwise", func() {
+			sorted, err := all.Sorted()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sorted.IDs()).To(Equal([]string{"traefik", "workloads", "quarks", "registry", "tekton"}))
+		})
+
+		It("ignores needed deployments which are not part of the list", func() {
+			selected, err := all.Select([]string{"tekton", "registry"}, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			sorted, err := selected.Sorted()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sorted.IDs()).To(Equal([]string{"registry", "tekton"}))
+		})
+
+		It("fails for deployments needing each other", func() {
+			_, err := Deployments{
+				deployment("a", "b"),
+				deployment("b", "a"),
+				deployment("c"),
+			}.Sorted()
+			Expect(err).To(MatchError("components a, b need each other"))
+		})
+	})
+
+	Describe("Select", func() {
+		It("returns all deployments by default", func() {
+			selected, err := all.Select(nil, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selected.IDs()).To(Equal(all.IDs()))
+		})
+
+		It("returns only the given deployments, without the skipped ones", func() {
+			selected, err := all.Select([]string{"quarks", "traefik", "registry"}, []string{"registry"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selected.IDs()).To(Equal([]string{"traefik", "quarks"}))
+		})
+
+		It("fails for unknown deployments", func() {
+			_, err := all.Select(nil, []string{"gitlab"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unknown component 'gitlab'"))
+		})
+	})
+
+	Describe("Missing", func() {
+		It("lists the needed deployments which are not part of the list", func() {
+			selected, err := all.Select(nil, []string{"workloads", "traefik"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selected.Missing()).To(Equal([]string{
+				"tekton needs workloads",
+				"registry needs workloads",
+			}))
+		})
+	})
+
+	Describe("Concurrently", func() {
+		var (
+			mutex    sync.Mutex
+			finished []string
+			skipped  []string
+		)
+
+		BeforeEach(func() {
+			finished = []string{}
+			skipped = []string{}
+		})
+
+		record := func(fail ...string) func(Deployment) error {
+			return func(d Deployment) error {
+				mutex.Lock()
+				defer mutex.Unlock()
+				for _, id := range d.Needs() {
+					if _, ok := all.Get(id); ok {
+						Expect(finished).To(ContainElement(id))
+					}
+				}
+				finished = append(finished, d.ID())
+				for _, id := range fail {
+					if id == d.ID() {
+						return errors.New("broken")
+					}
+				}
+				return nil
+			}
+		}
+
+		skip := func(d Deployment, err error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			skipped = append(skipped, d.ID())
+		}
+
+		It("acts on every deployment after those it needs", func() {
+			Expect(all.Concurrently(0, record(), skip)).To(Succeed())
+			Expect(finished).To(ConsistOf(all.IDs()))
+			Expect(skipped).To(BeEmpty())
+		})
+
+		It("acts on one deployment at a time with a limit of 1", func() {
+			Expect(all.Concurrently(1, record(), skip)).To(Succeed())
+			Expect(finished).To(Equal([]string{"traefik", "workloads", "quarks", "registry", "tekton"}))
+		})
+
+		It("skips the deployments needing a failed one and reports all failures", func() {
+			err := all.Concurrently(2, record("quarks"), skip)
+			Expect(err).To(HaveOccurred())
+			Expect(finished).To(ConsistOf("traefik", "workloads", "quarks"))
+			Expect(skipped).To(Equal([]string{"registry", "tekton"}))
+
+			errs, ok := err.(DeploymentErrors)
+			Expect(ok).To(BeTrue())
+			Expect(errs).To(HaveLen(3))
+			Expect(errs[0].ID).To(Equal("tekton"))
+			Expect(err.Error()).To(Equal("failed components:\n" +
+				"  - tekton: skipped, as it needs registry, which failed\n" +
+				"  - registry: skipped, as it needs quarks, which failed\n" +
+				"  - quarks: broken"))
+		})
+
+		It("fails for deployments needing each other", func() {
+			err := Deployments{
+				deployment("a", "b"),
+				deployment("b", "a"),
+			}.Concurrently(0, record(), skip)
+			Expect(err).To(MatchError("components a, b need each other"))
+			Expect(finished).To(BeEmpty())
+		})
+	})
+
+	Describe("Reversed", func() {
+		It("reverses the order", func() {
+			Expect(all.Reversed().IDs()).To(Equal([]string{"quarks", "workloads", "registry", "traefik", "tekton"}))
+		})
+	})
+})
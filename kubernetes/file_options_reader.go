@@ -0,0 +1,119 @@
+package kubernetes
+
+import (
+	"io/ioutil"
+	"sort"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// FileOptionsReader fills options from a YAML or JSON values file.
+// Shared options are top-level keys, while the options private to a
+// deployment are nested under the deployment's ID, e.g.
+//
+//	system_domain: example.com
+//	gitea:
+//	  some_option: value
+//
+// Options already specified by the user, i.e. on the command line, are
+// left alone.
+type FileOptionsReader struct {
+	path   string
+	values map[string]interface{}
+	used   map[string]bool
+}
+
+// NewFileOptionsReader is a reader used by the Installer to fill
+// configuration variables from the values file at path.
+func NewFileOptionsReader(path string) (*FileOptionsReader, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read values file %s", path)
+	}
+
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal(contents, &values); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse values file %s", path)
+	}
+
+	return &FileOptionsReader{path: path, values: values, used: map[string]bool{}}, nil
+}
+
+// Read sets the option from the values file, converted to the type
+// defined by the Type field of the option. Does nothing if the file
+// has no value for the option.
+func (reader *FileOptionsReader) Read(option *InstallationOption) error {
+	if option.UserSpecified {
+		return nil
+	}
+
+	key := option.QualifiedName()
+	values := reader.values
+	if option.DeploymentID != "" {
+		section, ok := reader.values[option.DeploymentID]
+		if !ok {
+			return nil
+		}
+		values, ok = section.(map[string]interface{})
+		if !ok {
+			return errors.Errorf("%s: '%s' must hold the options of the %s deployment", reader.path, option.DeploymentID, option.DeploymentID)
+		}
+	}
+
+	fileValue, ok := values[option.Name]
+	if !ok {
+		return nil
+	}
+	reader.used[key] = true
+
+	var value interface{}
+	switch option.Type {
+	case BooleanType:
+		value, ok = fileValue.(bool)
+	case StringType:
+		value, ok = fileValue.(string)
+	case IntType:
+		// Numbers are decoded as float64, accept only whole ones
+		var number float64
+		number, ok = fileValue.(float64)
+		ok = ok && number == float64(int(number))
+		value = int(number)
+	default:
+		return errors.New("Internal error: option Type not supported")
+	}
+
+	if !ok {
+		return errors.Errorf("%s: invalid value '%v' for %s, expected %s", reader.path, fileValue, key, typeName(option.Type))
+	}
+
+	option.Value = value
+	option.UserSpecified = true
+	option.Source = SourceFile
+
+	return nil
+}
+
+// Unused returns the keys of the values file which were not read by any
+// option, sorted, e.g. misspelled option names
+func (reader *FileOptionsReader) Unused() []string {
+	unused := []string{}
+
+	for key, value := range reader.values {
+		section, ok := value.(map[string]interface{})
+		if !ok {
+			if !reader.used[key] {
+				unused = append(unused, key)
+			}
+			continue
+		}
+		for name := range section {
+			if !reader.used[key+"."+name] {
+				unused = append(unused, key+"."+name)
+			}
+		}
+	}
+
+	sort.Strings(unused)
+	return unused
+}
@@ -0,0 +1,156 @@
+package kubernetes_test
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fuseml/fuseml/cli/kubernetes"
+)
+
+var _ = Describe("FileOptionsReader", func() {
+	var valuesFile string
+
+	writeValues := func(contents string) {
+		file, err := ioutil.TempFile("", "fuseml-values")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = file.WriteString(contents)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(file.Close()).To(Succeed())
+		valuesFile = file.Name()
+	}
+
+	AfterEach(func() {
+		os.Remove(valuesFile)
+	})
+
+	newOptions := func() *InstallationOptions {
+		return &InstallationOptions{
+			{Name: "a_flag", Default: false, Type: BooleanType},
+			{Name: "a_text", Default: "", Type: StringType},
+			{Name: "a_count", Default: -1, Type: IntType},
+			{Name: "a_text", Default: "", Type: StringType, DeploymentID: "mydeployment"},
+		}
+	}
+
+	Describe("Read", func() {
+		It("reads shared and private options from YAML", func() {
+			writeValues(`
+a_flag: true
+a_text: shared
+a_count: 3
+mydeployment:
+  a_text: private
+`)
+			reader, err := NewFileOptionsReader(valuesFile)
+			Expect(err).ToNot(HaveOccurred())
+
+			options, err := newOptions().Populate(reader)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(options.GetBool("a_flag", "")).To(BeTrue())
+			Expect(options.GetInt("a_count", "")).To(Equal(3))
+			Expect(options.GetString("a_text", "")).To(Equal("shared"))
+			Expect(options.GetString("a_text", "mydeployment")).To(Equal("private"))
+
+			opt, err := options.GetOpt("a_text", "mydeployment")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(opt.UserSpecified).To(BeTrue())
+			Expect(opt.Source).To(Equal(SourceFile))
+
+			Expect(reader.Unused()).To(BeEmpty())
+		})
+
+		It("reads JSON", func() {
+			writeValues(`{"a_text": "from json"}`)
+			reader, err := NewFileOptionsReader(valuesFile)
+			Expect(err).ToNot(HaveOccurred())
+
+			options, err := newOptions().Populate(reader)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(options.GetString("a_text", "")).To(Equal("from json"))
+		})
+
+		It("leaves options without a value alone", func() {
+			writeValues(`a_flag: true`)
+			reader, err := NewFileOptionsReader(valuesFile)
+			Expect(err).ToNot(HaveOccurred())
+
+			options, err := newOptions().Populate(reader)
+			Expect(err).ToNot(HaveOccurred())
+
+			opt, err := options.GetOpt("a_text", "mydeployment")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(opt.Value).To(BeNil())
+			Expect(opt.UserSpecified).To(BeFalse())
+		})
+
+		It("rejects values of the wrong type", func() {
+			writeValues(`a_count: 1.5`)
+			reader, err := NewFileOptionsReader(valuesFile)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = newOptions().Populate(reader)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("expected an integer"))
+		})
+
+		It("reports unknown keys", func() {
+			writeValues(`
+a_txet: typo
+mydeployment:
+  a_text: private
+  other: value
+`)
+			reader, err := NewFileOptionsReader(valuesFile)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = newOptions().Populate(reader)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reader.Unused()).To(Equal([]string{"a_txet", "mydeployment.other"}))
+		})
+	})
+
+	It("is overridden by the command line and overrides defaults", func() {
+		writeValues(`
+a_text: from file
+a_count: 3
+`)
+		options := &InstallationOptions{
+			{Name: "a_flag", Default: false, Type: BooleanType},
+			{Name: "a_text", Default: "", Type: StringType},
+			{Name: "a_count", Default: -1, Type: IntType},
+		}
+		cmd := &cobra.Command{Use: "dummy"}
+		options.AsCobraFlagsFor(cmd)
+		Expect(cmd.Flags().Set("a-text", "from cli")).To(Succeed())
+
+		fileReader, err := NewFileOptionsReader(valuesFile)
+		Expect(err).ToNot(HaveOccurred())
+
+		result, err := options.Populate(NewCLIOptionsReader(cmd))
+		Expect(err).ToNot(HaveOccurred())
+		result, err = result.Populate(fileReader)
+		Expect(err).ToNot(HaveOccurred())
+		result, err = result.Populate(NewDefaultOptionsReader())
+		Expect(err).ToNot(HaveOccurred())
+
+		sources := map[string]InstallationOptionSource{}
+		for _, opt := range *result {
+			sources[opt.ToOptMapKey()] = opt.Source
+		}
+
+		Expect(result.GetString("a_text", "")).To(Equal("from cli"))
+		Expect(result.GetInt("a_count", "")).To(Equal(3))
+		Expect(result.GetBool("a_flag", "")).To(BeFalse())
+		Expect(sources).To(Equal(map[string]InstallationOptionSource{
+			"a_text-":  SourceCLI,
+			"a_count-": SourceFile,
+			"a_flag-":  SourceDefault,
+		}))
+	})
+})
@@ -0,0 +1,211 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// HealthCheck is the outcome of checking one part of an installed
+// deployment, e.g. its pods or its ingress
+type HealthCheck struct {
+	Deployment string `json:"deployment"`
+	Check      string `json:"check"`
+	Healthy    bool   `json:"healthy"`
+	Details    string `json:"details"`
+}
+
+// Healthy returns a passed check
+func Healthy(check, format string, a ...interface{}) HealthCheck {
+	return HealthCheck{Check: check, Healthy: true, Details: fmt.Sprintf(format, a...)}
+}
+
+// Unhealthy returns a failed check
+func Unhealthy(check, format string, a ...interface{}) HealthCheck {
+	return HealthCheck{Check: check, Details: fmt.Sprintf(format, a...)}
+}
+
+// Degraded returns the number of failed checks
+func Degraded(checks []HealthCheck) int {
+	count := 0
+	for _, check := range checks {
+		if !check.Healthy {
+			count++
+		}
+	}
+	return count
+}
+
+// ReachableTimeout is how long EndpointHealth waits for a host to answer
+const ReachableTimeout = 5 * time.Second
+
+var gatewayResource = schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1alpha3", Resource: "gateways"}
+
+// NamespaceHealth checks that the namespace exists and belongs to FuseML
+func (c *Cluster) NamespaceHealth(namespace string) HealthCheck {
+	check := "namespace " + namespace
+
+	owned, err := c.NamespaceExistsAndOwned(namespace)
+	if err != nil {
+		return Unhealthy(check, "%s", err.Error())
+	}
+	if !owned {
+		return Unhealthy(check, "missing, or not owned by FuseML")
+	}
+
+	return Healthy(check, "present")
+}
+
+// PodsHealth checks that there are pods in the namespace matching the
+// selector, and that all of them are running and ready
+func (c *Cluster) PodsHealth(namespace, selector string) HealthCheck {
+	check := "pods " + namespace + "/" + selector
+
+	pods, err := c.ListPods(namespace, selector)
+	if err != nil {
+		return Unhealthy(check, "%s", err.Error())
+	}
+	if len(pods.Items) == 0 {
+		return Unhealthy(check, "no pods found")
+	}
+
+	ready := 0
+	reasons := []string{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if PodRunningAndReady(pod) {
+			ready++
+			continue
+		}
+		reasons = append(reasons, pod.Name+" "+PodWaitReason(pod))
+	}
+
+	details := fmt.Sprintf("%d/%d ready", ready, len(pods.Items))
+	if len(reasons) > 0 {
+		return Unhealthy(check, "%s, %s", details, strings.Join(reasons, ", "))
+	}
+
+	return Healthy(check, "%s", details)
+}
+
+// SecretsHealth checks that the named secrets exist in the namespace
+func (c *Cluster) SecretsHealth(namespace string, names ...string) HealthCheck {
+	check := "secrets " + namespace + "/" + strings.Join(names, ",")
+
+	missing := []string{}
+	for _, name := range names {
+		_, err := c.Kubectl.CoreV1().Secrets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			missing = append(missing, name)
+			continue
+		}
+		if err != nil {
+			return Unhealthy(check, "%s", err.Error())
+		}
+	}
+
+	if len(missing) > 0 {
+		return Unhealthy(check, "missing %s", strings.Join(missing, ", "))
+	}
+
+	return Healthy(check, "present")
+}
+
+// EndpointHealth checks that the hosts of the istio gateways, or of the
+// ingresses without istio, in the namespace and matching the selector
+// answer HTTP requests
+func (c *Cluster) EndpointHealth(namespace, selector string) HealthCheck {
+	kind := "ingress"
+	if c.HasIstio() {
+		kind = "gateway"
+	}
+	check := kind + " " + namespace
+	if selector != "" {
+		check += "/" + selector
+	}
+
+	var hosts []string
+	var err error
+	if kind == "gateway" {
+		hosts, err = c.gatewayHosts(namespace, selector)
+	} else {
+		hosts, err = c.ingressHosts(namespace, selector)
+	}
+	if err != nil {
+		return Unhealthy(check, "%s", err.Error())
+	}
+	if len(hosts) == 0 {
+		return Unhealthy(check, "no %s found", kind)
+	}
+
+	client := &http.Client{Timeout: ReachableTimeout}
+	for _, host := range hosts {
+		if err := Reachable(client, "http://"+host); err != nil {
+			return Unhealthy(check, "%s", err.Error())
+		}
+	}
+
+	return Healthy(check, "%s reachable", strings.Join(hosts, ", "))
+}
+
+// Reachable checks that the URL answers a HTTP request, with any status
+// below 500
+func Reachable(client *http.Client, url string) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return errors.Wrapf(err, "%s unreachable", url)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return errors.Errorf("%s answers %s", url, resp.Status)
+	}
+
+	return nil
+}
+
+func (c *Cluster) ingressHosts(namespace, selector string) ([]string, error) {
+	ingresses, err := c.Kubectl.NetworkingV1().Ingresses(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list ingresses")
+	}
+
+	hosts := []string{}
+	for _, ingress := range ingresses.Items {
+		for _, rule := range ingress.Spec.Rules {
+			hosts = append(hosts, rule.Host)
+		}
+	}
+
+	return hosts, nil
+}
+
+func (c *Cluster) gatewayHosts(namespace, selector string) ([]string, error) {
+	gateways, err := c.Dynamic.Resource(gatewayResource).Namespace(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list istio gateways")
+	}
+
+	hosts := []string{}
+	for _, gateway := range gateways.Items {
+		servers, _, _ := unstructured.NestedSlice(gateway.Object, "spec", "servers")
+		for _, server := range servers {
+			server, ok := server.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			serverHosts, _, _ := unstructured.NestedStringSlice(server, "hosts")
+			hosts = append(hosts, serverHosts...)
+		}
+	}
+
+	return hosts, nil
+}
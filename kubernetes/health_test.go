@@ -0,0 +1,54 @@
+package kubernetes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fuseml/fuseml/cli/kubernetes"
+)
+
+var _ = Describe("Health checks", func() {
+	Describe("Degraded", func() {
+		It("counts the failed checks", func() {
+			Expect(Degraded([]HealthCheck{
+				Healthy("pods gitea/app=gitea", "1/1 ready"),
+				Unhealthy("secrets fuseml-workloads/gitea-creds", "missing gitea-creds"),
+				Unhealthy("helm release gitea/gitea", "not found"),
+			})).To(Equal(2))
+		})
+	})
+
+	Describe("Reachable", func() {
+		var status int
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(status)
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("accepts answers below 500, e.g. redirects to a login", func() {
+			status = http.StatusUnauthorized
+			Expect(Reachable(server.Client(), server.URL)).To(Succeed())
+		})
+
+		It("fails on server errors", func() {
+			status = http.StatusBadGateway
+			Expect(Reachable(server.Client(), server.URL)).To(MatchError(ContainSubstring("502 Bad Gateway")))
+		})
+
+		It("fails when nothing answers", func() {
+			url := server.URL
+			server.Close()
+			Expect(Reachable(server.Client(), url)).To(MatchError(ContainSubstring("unreachable")))
+		})
+	})
+})
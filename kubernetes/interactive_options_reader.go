@@ -71,17 +71,13 @@ func (reader InteractiveOptionsReader) Read(option *InstallationOption) error {
 		// Keep the default set by (**). And claim it as
 		// user-specified (actually more `affirmed`).
 		option.UserSpecified = true
+		option.Source = SourceInteractive
 		return nil
 	}
 
-	// TODO (post MVP): String and integer types should be
-	// extended to call an option-specific validation function, if
-	// present, which would perform additional checks on the
-	// user's value. For example range limits, proper syntax of
-	// the string, etc. They would then loop similar to the entry
-	// for booleans. The loop could then actually move outside of
-	// the switch, and boolean validation would use a standard
-	// validation function.
+	// String and integer values are checked with the option's
+	// validators, asking again until the user provides a valid
+	// value.
 
 	switch option.Type {
 	case BooleanType:
@@ -89,10 +85,12 @@ func (reader InteractiveOptionsReader) Read(option *InstallationOption) error {
 			if userValue == "y" {
 				option.Value = true
 				option.UserSpecified = true
+				option.Source = SourceInteractive
 				return nil
 			} else if userValue == "n" {
 				option.Value = false
 				option.UserSpecified = true
+				option.Source = SourceInteractive
 				return nil
 			}
 
@@ -104,19 +102,32 @@ func (reader InteractiveOptionsReader) Read(option *InstallationOption) error {
 			userValue = strings.TrimSpace(userValue)
 		}
 	case StringType:
-		option.Value = userValue
-		option.UserSpecified = true
-		return nil
+		for {
+			err := validValue(option, userValue)
+			if err == nil {
+				return nil
+			}
+
+			reader.out.Write([]byte(err.Error() + ", please try again"))
+			userValue, err = bufReader.ReadString('\n')
+			if err != nil {
+				return err
+			}
+			userValue = strings.TrimSpace(userValue)
+		}
 	case IntType:
 		for {
 			userInt, err := strconv.Atoi(userValue)
 			if err == nil {
-				option.Value = userInt
-				option.UserSpecified = true
-				return nil
+				err = validValue(option, userInt)
+				if err == nil {
+					return nil
+				}
+				reader.out.Write([]byte(err.Error() + ", please try again"))
+			} else {
+				reader.out.Write([]byte("Please provide an integer value"))
 			}
 
-			reader.out.Write([]byte("Please provide an integer value"))
 			userValue, err = bufReader.ReadString('\n')
 			if err != nil {
 				return err
@@ -127,3 +138,18 @@ func (reader InteractiveOptionsReader) Read(option *InstallationOption) error {
 		return errors.New("Internal error: option Type not supported")
 	}
 }
+
+// validValue sets the option to the value given by the user, if it passes
+// the option's validators
+func validValue(option *InstallationOption, value interface{}) error {
+	candidate := *option
+	candidate.Value = value
+	if err := candidate.Validate(); err != nil {
+		return err
+	}
+
+	option.Value = value
+	option.UserSpecified = true
+	option.Source = SourceInteractive
+	return nil
+}
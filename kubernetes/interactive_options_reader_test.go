@@ -191,6 +191,51 @@ var _ = Describe("InteractiveOptionsReader", func() {
 			})
 		})
 
+		When("the option has validators", func() {
+			It("asks again if a string is invalid", func() {
+				option := InstallationOption{
+					Name:        "Option",
+					Value:       "",
+					Description: "This is a domain option",
+					Type:        StringType,
+					Validators:  []InstallationOptionValidator{DNSNameValidator()},
+				}
+
+				stdin.Write([]byte("not_a_domain\nexample.com\n"))
+				err := reader.Read(&option)
+				Expect(err).ToNot(HaveOccurred())
+
+				prompt, err := ioutil.ReadAll(stdout)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(prompt)).To(
+					ContainSubstring("is not a valid DNS name"))
+
+				Expect(option.Value).To(Equal("example.com"))
+				Expect(option.UserSpecified).To(BeTrue())
+			})
+
+			It("asks again if an integer is out of range", func() {
+				option := InstallationOption{
+					Name:        "Option",
+					Value:       0,
+					Description: "This is a bounded integer option",
+					Type:        IntType,
+					Validators:  []InstallationOptionValidator{RangeValidator(1, 10)},
+				}
+
+				stdin.Write([]byte("11\n7\n"))
+				err := reader.Read(&option)
+				Expect(err).ToNot(HaveOccurred())
+
+				prompt, err := ioutil.ReadAll(stdout)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(prompt)).To(
+					ContainSubstring("out of range"))
+
+				Expect(option.Value).To(Equal(7))
+			})
+		})
+
 		When("the option is bogus", func() {
 			var option InstallationOption
 
@@ -22,11 +22,12 @@ type FakeDeployment struct {
 	backupReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteStub        func(*kubernetes.Cluster, *ui.UI) error
+	DeleteStub        func(*kubernetes.Cluster, *ui.UI, kubernetes.DeleteOptions) error
 	deleteMutex       sync.RWMutex
 	deleteArgsForCall []struct {
 		arg1 *kubernetes.Cluster
 		arg2 *ui.UI
+		arg3 kubernetes.DeleteOptions
 	}
 	deleteReturns struct {
 		result1 error
@@ -67,6 +68,17 @@ type FakeDeployment struct {
 	getVersionReturnsOnCall map[int]struct {
 		result1 string
 	}
+	HealthStub        func(*kubernetes.Cluster) []kubernetes.HealthCheck
+	healthMutex       sync.RWMutex
+	healthArgsForCall []struct {
+		arg1 *kubernetes.Cluster
+	}
+	healthReturns struct {
+		result1 []kubernetes.HealthCheck
+	}
+	healthReturnsOnCall map[int]struct {
+		result1 []kubernetes.HealthCheck
+	}
 	IDStub        func() string
 	iDMutex       sync.RWMutex
 	iDArgsForCall []struct {
@@ -77,6 +89,40 @@ type FakeDeployment struct {
 	iDReturnsOnCall map[int]struct {
 		result1 string
 	}
+	NeedsStub        func() []string
+	needsMutex       sync.RWMutex
+	needsArgsForCall []struct {
+	}
+	needsReturns struct {
+		result1 []string
+	}
+	needsReturnsOnCall map[int]struct {
+		result1 []string
+	}
+	OptionsStub        func() kubernetes.InstallationOptions
+	optionsMutex       sync.RWMutex
+	optionsArgsForCall []struct {
+	}
+	optionsReturns struct {
+		result1 kubernetes.InstallationOptions
+	}
+	optionsReturnsOnCall map[int]struct {
+		result1 kubernetes.InstallationOptions
+	}
+	RenderStub        func(*kubernetes.Cluster, *ui.UI, kubernetes.InstallationOptions, string) error
+	renderMutex       sync.RWMutex
+	renderArgsForCall []struct {
+		arg1 *kubernetes.Cluster
+		arg2 *ui.UI
+		arg3 kubernetes.InstallationOptions
+		arg4 string
+	}
+	renderReturns struct {
+		result1 error
+	}
+	renderReturnsOnCall map[int]struct {
+		result1 error
+	}
 	RestoreStub        func(*kubernetes.Cluster, *ui.UI, string) error
 	restoreMutex       sync.RWMutex
 	restoreArgsForCall []struct {
@@ -170,19 +216,20 @@ func (fake *FakeDeployment) BackupReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeDeployment) Delete(arg1 *kubernetes.Cluster, arg2 *ui.UI) error {
+func (fake *FakeDeployment) Delete(arg1 *kubernetes.Cluster, arg2 *ui.UI, arg3 kubernetes.DeleteOptions) error {
 	fake.deleteMutex.Lock()
 	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
 	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
 		arg1 *kubernetes.Cluster
 		arg2 *ui.UI
-	}{arg1, arg2})
+		arg3 kubernetes.DeleteOptions
+	}{arg1, arg2, arg3})
 	stub := fake.DeleteStub
 	fakeReturns := fake.deleteReturns
-	fake.recordInvocation("Delete", []interface{}{arg1, arg2})
+	fake.recordInvocation("Delete", []interface{}{arg1, arg2, arg3})
 	fake.deleteMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -196,17 +243,17 @@ func (fake *FakeDeployment) DeleteCallCount() int {
 	return len(fake.deleteArgsForCall)
 }
 
-func (fake *FakeDeployment) DeleteCalls(stub func(*kubernetes.Cluster, *ui.UI) error) {
+func (fake *FakeDeployment) DeleteCalls(stub func(*kubernetes.Cluster, *ui.UI, kubernetes.DeleteOptions) error) {
 	fake.deleteMutex.Lock()
 	defer fake.deleteMutex.Unlock()
 	fake.DeleteStub = stub
 }
 
-func (fake *FakeDeployment) DeleteArgsForCall(i int) (*kubernetes.Cluster, *ui.UI) {
+func (fake *FakeDeployment) DeleteArgsForCall(i int) (*kubernetes.Cluster, *ui.UI, kubernetes.DeleteOptions) {
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
 	argsForCall := fake.deleteArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeDeployment) DeleteReturns(result1 error) {
@@ -401,6 +448,67 @@ func (fake *FakeDeployment) GetVersionReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
+func (fake *FakeDeployment) Health(arg1 *kubernetes.Cluster) []kubernetes.HealthCheck {
+	fake.healthMutex.Lock()
+	ret, specificReturn := fake.healthReturnsOnCall[len(fake.healthArgsForCall)]
+	fake.healthArgsForCall = append(fake.healthArgsForCall, struct {
+		arg1 *kubernetes.Cluster
+	}{arg1})
+	stub := fake.HealthStub
+	fakeReturns := fake.healthReturns
+	fake.recordInvocation("Health", []interface{}{arg1})
+	fake.healthMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeDeployment) HealthCallCount() int {
+	fake.healthMutex.RLock()
+	defer fake.healthMutex.RUnlock()
+	return len(fake.healthArgsForCall)
+}
+
+func (fake *FakeDeployment) HealthCalls(stub func(*kubernetes.Cluster) []kubernetes.HealthCheck) {
+	fake.healthMutex.Lock()
+	defer fake.healthMutex.Unlock()
+	fake.HealthStub = stub
+}
+
+func (fake *FakeDeployment) HealthArgsForCall(i int) *kubernetes.Cluster {
+	fake.healthMutex.RLock()
+	defer fake.healthMutex.RUnlock()
+	argsForCall := fake.healthArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeDeployment) HealthReturns(result1 []kubernetes.HealthCheck) {
+	fake.healthMutex.Lock()
+	defer fake.healthMutex.Unlock()
+	fake.HealthStub = nil
+	fake.healthReturns = struct {
+		result1 []kubernetes.HealthCheck
+	}{result1}
+}
+
+func (fake *FakeDeployment) HealthReturnsOnCall(i int, result1 []kubernetes.HealthCheck) {
+	fake.healthMutex.Lock()
+	defer fake.healthMutex.Unlock()
+	fake.HealthStub = nil
+	if fake.healthReturnsOnCall == nil {
+		fake.healthReturnsOnCall = make(map[int]struct {
+			result1 []kubernetes.HealthCheck
+		})
+	}
+	fake.healthReturnsOnCall[i] = struct {
+		result1 []kubernetes.HealthCheck
+	}{result1}
+}
+
 func (fake *FakeDeployment) ID() string {
 	fake.iDMutex.Lock()
 	ret, specificReturn := fake.iDReturnsOnCall[len(fake.iDArgsForCall)]
@@ -454,6 +562,176 @@ func (fake *FakeDeployment) IDReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
+func (fake *FakeDeployment) Needs() []string {
+	fake.needsMutex.Lock()
+	ret, specificReturn := fake.needsReturnsOnCall[len(fake.needsArgsForCall)]
+	fake.needsArgsForCall = append(fake.needsArgsForCall, struct {
+	}{})
+	stub := fake.NeedsStub
+	fakeReturns := fake.needsReturns
+	fake.recordInvocation("Needs", []interface{}{})
+	fake.needsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeDeployment) NeedsCallCount() int {
+	fake.needsMutex.RLock()
+	defer fake.needsMutex.RUnlock()
+	return len(fake.needsArgsForCall)
+}
+
+func (fake *FakeDeployment) NeedsCalls(stub func() []string) {
+	fake.needsMutex.Lock()
+	defer fake.needsMutex.Unlock()
+	fake.NeedsStub = stub
+}
+
+func (fake *FakeDeployment) NeedsReturns(result1 []string) {
+	fake.needsMutex.Lock()
+	defer fake.needsMutex.Unlock()
+	fake.NeedsStub = nil
+	fake.needsReturns = struct {
+		result1 []string
+	}{result1}
+}
+
+func (fake *FakeDeployment) NeedsReturnsOnCall(i int, result1 []string) {
+	fake.needsMutex.Lock()
+	defer fake.needsMutex.Unlock()
+	fake.NeedsStub = nil
+	if fake.needsReturnsOnCall == nil {
+		fake.needsReturnsOnCall = make(map[int]struct {
+			result1 []string
+		})
+	}
+	fake.needsReturnsOnCall[i] = struct {
+		result1 []string
+	}{result1}
+}
+
+func (fake *FakeDeployment) Options() kubernetes.InstallationOptions {
+	fake.optionsMutex.Lock()
+	ret, specificReturn := fake.optionsReturnsOnCall[len(fake.optionsArgsForCall)]
+	fake.optionsArgsForCall = append(fake.optionsArgsForCall, struct {
+	}{})
+	stub := fake.OptionsStub
+	fakeReturns := fake.optionsReturns
+	fake.recordInvocation("Options", []interface{}{})
+	fake.optionsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeDeployment) OptionsCallCount() int {
+	fake.optionsMutex.RLock()
+	defer fake.optionsMutex.RUnlock()
+	return len(fake.optionsArgsForCall)
+}
+
+func (fake *FakeDeployment) OptionsCalls(stub func() kubernetes.InstallationOptions) {
+	fake.optionsMutex.Lock()
+	defer fake.optionsMutex.Unlock()
+	fake.OptionsStub = stub
+}
+
+func (fake *FakeDeployment) OptionsReturns(result1 kubernetes.InstallationOptions) {
+	fake.optionsMutex.Lock()
+	defer fake.optionsMutex.Unlock()
+	fake.OptionsStub = nil
+	fake.optionsReturns = struct {
+		result1 kubernetes.InstallationOptions
+	}{result1}
+}
+
+func (fake *FakeDeployment) OptionsReturnsOnCall(i int, result1 kubernetes.InstallationOptions) {
+	fake.optionsMutex.Lock()
+	defer fake.optionsMutex.Unlock()
+	fake.OptionsStub = nil
+	if fake.optionsReturnsOnCall == nil {
+		fake.optionsReturnsOnCall = make(map[int]struct {
+			result1 kubernetes.InstallationOptions
+		})
+	}
+	fake.optionsReturnsOnCall[i] = struct {
+		result1 kubernetes.InstallationOptions
+	}{result1}
+}
+
+func (fake *FakeDeployment) Render(arg1 *kubernetes.Cluster, arg2 *ui.UI, arg3 kubernetes.InstallationOptions, arg4 string) error {
+	fake.renderMutex.Lock()
+	ret, specificReturn := fake.renderReturnsOnCall[len(fake.renderArgsForCall)]
+	fake.renderArgsForCall = append(fake.renderArgsForCall, struct {
+		arg1 *kubernetes.Cluster
+		arg2 *ui.UI
+		arg3 kubernetes.InstallationOptions
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.RenderStub
+	fakeReturns := fake.renderReturns
+	fake.recordInvocation("Render", []interface{}{arg1, arg2, arg3, arg4})
+	fake.renderMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeDeployment) RenderCallCount() int {
+	fake.renderMutex.RLock()
+	defer fake.renderMutex.RUnlock()
+	return len(fake.renderArgsForCall)
+}
+
+func (fake *FakeDeployment) RenderCalls(stub func(*kubernetes.Cluster, *ui.UI, kubernetes.InstallationOptions, string) error) {
+	fake.renderMutex.Lock()
+	defer fake.renderMutex.Unlock()
+	fake.RenderStub = stub
+}
+
+func (fake *FakeDeployment) RenderArgsForCall(i int) (*kubernetes.Cluster, *ui.UI, kubernetes.InstallationOptions, string) {
+	fake.renderMutex.RLock()
+	defer fake.renderMutex.RUnlock()
+	argsForCall := fake.renderArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeDeployment) RenderReturns(result1 error) {
+	fake.renderMutex.Lock()
+	defer fake.renderMutex.Unlock()
+	fake.RenderStub = nil
+	fake.renderReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDeployment) RenderReturnsOnCall(i int, result1 error) {
+	fake.renderMutex.Lock()
+	defer fake.renderMutex.Unlock()
+	fake.RenderStub = nil
+	if fake.renderReturnsOnCall == nil {
+		fake.renderReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.renderReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeDeployment) Restore(arg1 *kubernetes.Cluster, arg2 *ui.UI, arg3 string) error {
 	fake.restoreMutex.Lock()
 	ret, specificReturn := fake.restoreReturnsOnCall[len(fake.restoreArgsForCall)]
@@ -593,8 +871,16 @@ func (fake *FakeDeployment) Invocations() map[string][][]interface{} {
 	defer fake.describeMutex.RUnlock()
 	fake.getVersionMutex.RLock()
 	defer fake.getVersionMutex.RUnlock()
+	fake.healthMutex.RLock()
+	defer fake.healthMutex.RUnlock()
 	fake.iDMutex.RLock()
 	defer fake.iDMutex.RUnlock()
+	fake.needsMutex.RLock()
+	defer fake.needsMutex.RUnlock()
+	fake.optionsMutex.RLock()
+	defer fake.optionsMutex.RUnlock()
+	fake.renderMutex.RLock()
+	defer fake.renderMutex.RUnlock()
 	fake.restoreMutex.RLock()
 	defer fake.restoreMutex.RUnlock()
 	fake.upgradeMutex.RLock()
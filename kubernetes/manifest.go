@@ -0,0 +1,215 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+)
+
+// FieldManager is the manager recorded for the fields fuseml applies
+const FieldManager = "fuseml"
+
+var crdResource = schema.GroupVersionResource{
+	Group:    "apiextensions.k8s.io",
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
+// DecodeManifest splits a multi-document YAML (or JSON) manifest into its
+// objects, skipping empty documents
+func DecodeManifest(manifest []byte) ([]*unstructured.Unstructured, error) {
+	result := []*unstructured.Unstructured{}
+
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifest), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		err := decoder.Decode(&obj.Object)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode manifest")
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if obj.GetKind() == "" || obj.GetName() == "" {
+			return nil, errors.Errorf("invalid object in manifest, kind and name are required: %v", obj.Object)
+		}
+		result = append(result, obj)
+	}
+
+	return result, nil
+}
+
+// ApplyManifest creates or updates all objects of the manifest, the same way
+// `kubectl apply --server-side` does. Namespaced objects without a namespace
+// are placed in the given namespace.
+func (c *Cluster) ApplyManifest(manifest []byte, namespace string) error {
+	objects, err := DecodeManifest(manifest)
+	if err != nil {
+		return err
+	}
+
+	force := true
+	for _, obj := range objects {
+		client, err := c.resourceClient(obj, namespace)
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encode %s %s", obj.GetKind(), obj.GetName())
+		}
+
+		_, err = client.Patch(context.Background(), obj.GetName(), types.ApplyPatchType, data,
+			metav1.PatchOptions{FieldManager: FieldManager, Force: &force})
+		if err != nil {
+			return errors.Wrapf(err, "failed to apply %s %s", obj.GetKind(), obj.GetName())
+		}
+	}
+
+	return nil
+}
+
+// DeleteManifest deletes all objects of the manifest, in reverse order,
+// without waiting for them to be gone. With ignoreMissing, objects (or
+// kinds) which don't exist are skipped.
+func (c *Cluster) DeleteManifest(manifest []byte, namespace string, ignoreMissing bool) error {
+	objects, err := DecodeManifest(manifest)
+	if err != nil {
+		return err
+	}
+
+	propagation := metav1.DeletePropagationBackground
+	for i := len(objects) - 1; i >= 0; i-- {
+		obj := objects[i]
+
+		client, err := c.resourceClient(obj, namespace)
+		if err != nil {
+			if ignoreMissing && meta.IsNoMatchError(errors.Cause(err)) {
+				continue
+			}
+			return err
+		}
+
+		err = client.Delete(context.Background(), obj.GetName(),
+			metav1.DeleteOptions{PropagationPolicy: &propagation})
+		if err != nil {
+			if ignoreMissing && apierrors.IsNotFound(err) {
+				continue
+			}
+			return errors.Wrapf(err, "failed to delete %s %s", obj.GetKind(), obj.GetName())
+		}
+	}
+
+	return nil
+}
+
+// WaitForCRDEstablished waits until the named CustomResourceDefinition is
+// established, i.e. its kind can be used
+func (c *Cluster) WaitForCRDEstablished(name string, timeout time.Duration) error {
+	crds := c.Dynamic.Resource(crdResource)
+	byName := fields.OneTermEqualSelector("metadata.name", name).String()
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = byName
+			return crds.List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = byName
+			return crds.Watch(context.Background(), options)
+		},
+	}
+
+	return watchUntil(lw, &unstructured.Unstructured{}, timeout, nil,
+		func(event watch.Event) (bool, error) {
+			crd, ok := event.Object.(*unstructured.Unstructured)
+			if !ok || event.Type == watch.Deleted {
+				return false, nil
+			}
+
+			conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+			for _, condition := range conditions {
+				values, ok := condition.(map[string]interface{})
+				if ok && values["type"] == "Established" && values["status"] == "True" {
+					return true, nil
+				}
+			}
+
+			return false, nil
+		})
+}
+
+// CRDNames returns the names of all CustomResourceDefinitions
+func (c *Cluster) CRDNames() ([]string, error) {
+	crds, err := c.Dynamic.Resource(crdResource).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list CRDs")
+	}
+
+	names := []string{}
+	for _, crd := range crds.Items {
+		names = append(names, crd.GetName())
+	}
+
+	return names, nil
+}
+
+// DeleteCRD removes the named CustomResourceDefinition, if it exists
+func (c *Cluster) DeleteCRD(name string) error {
+	err := c.Dynamic.Resource(crdResource).Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete CRD %s", name)
+	}
+
+	return nil
+}
+
+// resourceClient returns the dynamic client for the object's kind. The
+// mapping is refreshed once when the kind is unknown, as it may have been
+// introduced by a CRD applied since the last discovery.
+func (c *Cluster) resourceClient(obj *unstructured.Unstructured, namespace string) (dynamic.ResourceInterface, error) {
+	gvk := obj.GroupVersionKind()
+
+	mapping, err := c.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		if mapper, ok := c.Mapper.(interface{ Reset() }); ok {
+			mapper.Reset()
+			mapping, err = c.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unknown kind %s", gvk.String())
+	}
+
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return c.Dynamic.Resource(mapping.Resource), nil
+	}
+
+	if obj.GetNamespace() == "" {
+		if namespace == "" {
+			namespace = metav1.NamespaceDefault
+		}
+		obj.SetNamespace(namespace)
+	}
+
+	return c.Dynamic.Resource(mapping.Resource).Namespace(obj.GetNamespace()), nil
+}
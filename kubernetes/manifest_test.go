@@ -0,0 +1,121 @@
+package kubernetes_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	. "github.com/fuseml/fuseml/cli/kubernetes"
+)
+
+var _ = Describe("Manifest", func() {
+	manifest := []byte(`
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: app-ingress
+---
+# empty document
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  key: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other
+  namespace: elsewhere
+`)
+
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+	var cluster *Cluster
+	var client *dynamicfake.FakeDynamicClient
+
+	BeforeEach(func() {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+		mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+
+		existing := &unstructured.Unstructured{}
+		existing.SetAPIVersion("v1")
+		existing.SetKind("ConfigMap")
+		existing.SetName("settings")
+		existing.SetNamespace("fuseml")
+
+		client = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), existing)
+		cluster = &Cluster{Dynamic: client, Mapper: mapper}
+	})
+
+	Describe("DecodeManifest", func() {
+		It("returns the objects, skipping empty documents", func() {
+			objects, err := DecodeManifest(manifest)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(objects).To(HaveLen(3))
+			Expect(objects[1].GetName()).To(Equal("settings"))
+		})
+
+		It("rejects objects without a name", func() {
+			_, err := DecodeManifest([]byte("apiVersion: v1\nkind: ConfigMap\n"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ApplyManifest", func() {
+		It("applies every object server-side, in the default namespace if unset", func() {
+			applied := []string{}
+			client.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				patch := action.(k8stesting.PatchAction)
+				Expect(patch.GetPatchType()).To(Equal(types.ApplyPatchType))
+
+				obj := map[string]interface{}{}
+				Expect(json.Unmarshal(patch.GetPatch(), &obj)).To(Succeed())
+				Expect(obj["kind"]).ToNot(BeEmpty())
+
+				applied = append(applied, patch.GetResource().Resource+"/"+patch.GetNamespace()+"/"+patch.GetName())
+				return true, nil, nil
+			})
+
+			Expect(cluster.ApplyManifest(manifest, "fuseml")).To(Succeed())
+			Expect(applied).To(Equal([]string{
+				"namespaces//app-ingress",
+				"configmaps/fuseml/settings",
+				"configmaps/elsewhere/other",
+			}))
+		})
+
+		It("fails on unknown kinds", func() {
+			err := cluster.ApplyManifest([]byte("apiVersion: v1\nkind: Unknown\nmetadata:\n  name: x\n"), "fuseml")
+			Expect(err).To(MatchError(ContainSubstring("unknown kind")))
+		})
+	})
+
+	Describe("DeleteManifest", func() {
+		It("deletes the objects, ignoring missing ones if asked to", func() {
+			Expect(cluster.DeleteManifest(manifest, "fuseml", true)).To(Succeed())
+
+			_, err := client.Resource(configMaps).Namespace("fuseml").Get(context.Background(), "settings", metav1.GetOptions{})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("fails on missing objects otherwise", func() {
+			err := cluster.DeleteManifest(manifest, "fuseml", false)
+			Expect(err).To(MatchError(ContainSubstring("failed to delete ConfigMap other")))
+		})
+	})
+})
@@ -0,0 +1,98 @@
+package kubernetes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// InstallationOptionValidator checks the value of an option and returns an
+// error describing the problem, if any. It is only called once the value
+// is known to have the type defined by the Type field of the option.
+type InstallationOptionValidator func(o *InstallationOption) error
+
+// ValidationErrors collects the problems found by
+// InstallationOptions.Validate, so that they can be reported at once
+type ValidationErrors []error
+
+func (errs ValidationErrors) Error() string {
+	problems := []string{}
+	for _, err := range errs {
+		problems = append(problems, "  - "+err.Error())
+	}
+
+	return "invalid installation options:\n" + strings.Join(problems, "\n")
+}
+
+// RequiredValidator rejects empty strings.
+//
+// The other validators accept empty strings, as they usually stand for a
+// value to be determined automatically. Combine them with this one when
+// that is not the case.
+func RequiredValidator() InstallationOptionValidator {
+	return func(o *InstallationOption) error {
+		if value, ok := o.Value.(string); ok && value == "" {
+			return fmt.Errorf("%s is required", o.QualifiedName())
+		}
+		return nil
+	}
+}
+
+// RegexpValidator requires string values to match the regular expression
+// pattern. The pattern is compiled right away, and must be valid.
+func RegexpValidator(pattern string) InstallationOptionValidator {
+	re := regexp.MustCompile(pattern)
+
+	return func(o *InstallationOption) error {
+		value, ok := o.Value.(string)
+		if !ok || value == "" || re.MatchString(value) {
+			return nil
+		}
+		return fmt.Errorf("%s '%s' does not match %s", o.QualifiedName(), value, pattern)
+	}
+}
+
+// RangeValidator requires integer values to be within min and max,
+// inclusive
+func RangeValidator(min, max int) InstallationOptionValidator {
+	return func(o *InstallationOption) error {
+		value, ok := o.Value.(int)
+		if !ok || (value >= min && value <= max) {
+			return nil
+		}
+		return fmt.Errorf("%s %d is out of range, expected %d to %d", o.QualifiedName(), value, min, max)
+	}
+}
+
+// EnumValidator requires string values to be one of values
+func EnumValidator(values ...string) InstallationOptionValidator {
+	return func(o *InstallationOption) error {
+		value, ok := o.Value.(string)
+		if !ok || value == "" {
+			return nil
+		}
+		for _, allowed := range values {
+			if value == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s '%s' is not one of %s", o.QualifiedName(), value, strings.Join(values, ", "))
+	}
+}
+
+// DNSNameValidator requires string values to be DNS names, as used for
+// domains, e.g. `fuseml.example.com`
+func DNSNameValidator() InstallationOptionValidator {
+	return func(o *InstallationOption) error {
+		value, ok := o.Value.(string)
+		if !ok || value == "" {
+			return nil
+		}
+		if problems := validation.IsDNS1123Subdomain(value); len(problems) > 0 {
+			return fmt.Errorf("%s '%s' is not a valid DNS name: %s", o.QualifiedName(), value, strings.Join(problems, "; "))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,106 @@
+package kubernetes_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fuseml/fuseml/cli/kubernetes"
+)
+
+var _ = Describe("InstallationOption validators", func() {
+	validate := func(validator InstallationOptionValidator, optType InstallationOptionType, value interface{}) error {
+		option := InstallationOption{
+			Name:       "Option",
+			Type:       optType,
+			Value:      value,
+			Validators: []InstallationOptionValidator{validator},
+		}
+		return option.Validate()
+	}
+
+	Describe("RequiredValidator", func() {
+		It("rejects empty strings", func() {
+			err := validate(RequiredValidator(), StringType, "")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Option is required"))
+		})
+
+		It("accepts values", func() {
+			Expect(validate(RequiredValidator(), StringType, "value")).To(Succeed())
+		})
+	})
+
+	Describe("RegexpValidator", func() {
+		It("requires the value to match", func() {
+			Expect(validate(RegexpValidator(`^[a-z]+$`), StringType, "abc")).To(Succeed())
+			Expect(validate(RegexpValidator(`^[a-z]+$`), StringType, "ABC")).To(MatchError(ContainSubstring("does not match")))
+		})
+
+		It("accepts empty strings", func() {
+			Expect(validate(RegexpValidator(`^[a-z]+$`), StringType, "")).To(Succeed())
+		})
+	})
+
+	Describe("RangeValidator", func() {
+		It("requires the value to be within the bounds", func() {
+			Expect(validate(RangeValidator(1, 10), IntType, 1)).To(Succeed())
+			Expect(validate(RangeValidator(1, 10), IntType, 10)).To(Succeed())
+			Expect(validate(RangeValidator(1, 10), IntType, 11)).To(MatchError("Option 11 is out of range, expected 1 to 10"))
+		})
+	})
+
+	Describe("EnumValidator", func() {
+		It("requires one of the values", func() {
+			Expect(validate(EnumValidator("a", "b"), StringType, "b")).To(Succeed())
+			Expect(validate(EnumValidator("a", "b"), StringType, "c")).To(MatchError("Option 'c' is not one of a, b"))
+		})
+	})
+
+	Describe("DNSNameValidator", func() {
+		It("requires a DNS name", func() {
+			Expect(validate(DNSNameValidator(), StringType, "10.0.0.1.omg.howdoi.website")).To(Succeed())
+			Expect(validate(DNSNameValidator(), StringType, "not_a_domain")).To(MatchError(ContainSubstring("is not a valid DNS name")))
+			Expect(validate(DNSNameValidator(), StringType, "https://example.com")).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("InstallationOptions.Validate", func() {
+	It("checks the types of the values", func() {
+		options := InstallationOptions{
+			{Name: "Option", Type: IntType, Value: "3"},
+		}
+		Expect(options.Validate()).To(MatchError(ContainSubstring("Option '3' is not an integer")))
+	})
+
+	It("reports options without a value", func() {
+		options := InstallationOptions{
+			{Name: "Option", Type: StringType, DeploymentID: "SomeDeployment"},
+		}
+		Expect(options.Validate()).To(MatchError(ContainSubstring("SomeDeployment.Option has no value")))
+	})
+
+	It("reports all problems at once", func() {
+		options := InstallationOptions{
+			{Name: "Domain", Type: StringType, Value: "not_a_domain", Validators: []InstallationOptionValidator{DNSNameValidator()}},
+			{Name: "Valid", Type: StringType, Value: "example.com", Validators: []InstallationOptionValidator{DNSNameValidator()}},
+			{Name: "Count", Type: IntType, Value: 0, Validators: []InstallationOptionValidator{RangeValidator(1, 3)}},
+		}
+
+		err := options.Validate()
+		Expect(err).To(HaveOccurred())
+
+		problems, ok := err.(ValidationErrors)
+		Expect(ok).To(BeTrue())
+		Expect(problems).To(HaveLen(2))
+		Expect(err.Error()).To(ContainSubstring("Domain 'not_a_domain'"))
+		Expect(err.Error()).To(ContainSubstring("Count 0 is out of range"))
+	})
+
+	It("succeeds for valid options", func() {
+		options := InstallationOptions{
+			{Name: "Option", Type: BooleanType, Value: false},
+		}
+		Expect(options.Validate()).To(Succeed())
+	})
+})
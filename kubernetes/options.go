@@ -33,11 +33,32 @@ type OptionsReader interface {
 // Valid flag of the specified option. This is necessary for cases
 // where the dynamic default could not be determined, yet is not an
 // error.
-//
 type InstallationOptionDynamicDefault func(o *InstallationOption) error
 
 type InstallationOptionType int
 
+// InstallationOptionSource tells where the value of an option came from
+type InstallationOptionSource string
+
+const (
+	SourceCLI         InstallationOptionSource = "cli"
+	SourceFile        InstallationOptionSource = "values file"
+	SourceInteractive InstallationOptionSource = "interactive"
+	SourceDefault     InstallationOptionSource = "default"
+	SourcePlatform    InstallationOptionSource = "platform"
+)
+
+func typeName(t InstallationOptionType) string {
+	switch t {
+	case BooleanType:
+		return "a boolean"
+	case IntType:
+		return "an integer"
+	default:
+		return "a string"
+	}
+}
+
 type InstallationOption struct {
 	Name           string                           // Identifying name of the configuration variable
 	Value          interface{}                      // Value to use (may not be valid, see `Valid` field).
@@ -47,14 +68,16 @@ type InstallationOption struct {
 	Description    string                           // Short description of the variable
 	Type           InstallationOptionType           // Type information for `Value` and `Default`.
 	DeploymentID   string                           // If set, this option will be passed only to this deployment (private)
+	Source         InstallationOptionSource         // Where `Value` came from, set by the reader providing it.
+	Validators     []InstallationOptionValidator    // Checks of the final value, run by `Validate`.
+	Sensitive      bool                             // Flag, true if `Value` is a secret, masked when shown.
 }
 
 type InstallationOptions []InstallationOption
 
 func (opts InstallationOptions) AsCobraFlagsFor(cmd *cobra.Command) {
 	for _, opt := range opts {
-		// Translate option name
-		flagName := strings.ReplaceAll(opt.Name, "_", "-")
+		flagName := opt.FlagName()
 
 		// Declare option's flag, type-dependent
 		switch opt.Type {
@@ -94,6 +117,56 @@ func (opt InstallationOption) ToOptMapKey() string {
 	return fmt.Sprintf("%s-%s", opt.Name, opt.DeploymentID)
 }
 
+// FlagName returns the name of the cobra flag of the option. The flags of
+// private options are prefixed with their deployment, e.g.
+// `--gitea-admin-password`.
+func (opt InstallationOption) FlagName() string {
+	return strings.ReplaceAll(strings.ReplaceAll(opt.QualifiedName(), "_", "-"), ".", "-")
+}
+
+// QualifiedName returns the name of the option as it is written in a
+// values file and in error messages, prefixed by its deployment, if
+// private
+func (opt InstallationOption) QualifiedName() string {
+	if opt.DeploymentID == "" {
+		return opt.Name
+	}
+
+	return opt.DeploymentID + "." + opt.Name
+}
+
+// Validate checks that the value of the option has the type defined by
+// its Type field, and then runs its Validators. It returns the first
+// problem found.
+func (opt *InstallationOption) Validate() error {
+	var ok bool
+	switch opt.Type {
+	case BooleanType:
+		_, ok = opt.Value.(bool)
+	case StringType:
+		_, ok = opt.Value.(string)
+	case IntType:
+		_, ok = opt.Value.(int)
+	default:
+		return errors.New("Internal error: option Type not supported")
+	}
+
+	if opt.Value == nil {
+		return fmt.Errorf("%s has no value", opt.QualifiedName())
+	}
+	if !ok {
+		return fmt.Errorf("%s '%v' is not %s", opt.QualifiedName(), opt.Value, typeName(opt.Type))
+	}
+
+	for _, validator := range opt.Validators {
+		if err := validator(opt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (opt *InstallationOption) DynDefault() error {
 	return opt.DynDefaultFunc(opt)
 }
@@ -169,7 +242,7 @@ func (opts InstallationOptions) GetString(optionName string, deploymentID string
 
 	result, ok := option.Value.(string)
 	if !ok {
-		panic("wrong type assertion")
+		return "", fmt.Errorf("%s '%v' is not a string", option.QualifiedName(), option.Value)
 	}
 
 	return result, nil
@@ -183,7 +256,7 @@ func (opts InstallationOptions) GetBool(optionName string, deploymentID string)
 
 	result, ok := option.Value.(bool)
 	if !ok {
-		panic("wrong type assertion")
+		return false, fmt.Errorf("%s '%v' is not a boolean", option.QualifiedName(), option.Value)
 	}
 
 	return result, nil
@@ -197,12 +270,29 @@ func (opts InstallationOptions) GetInt(optionName string, deploymentID string) (
 
 	result, ok := option.Value.(int)
 	if !ok {
-		panic("wrong type assertion")
+		return 0, fmt.Errorf("%s '%v' is not an integer", option.QualifiedName(), option.Value)
 	}
 
 	return result, nil
 }
 
+// Validate checks all options, see InstallationOption.Validate. All
+// problems are returned at once, as ValidationErrors.
+func (opts InstallationOptions) Validate() error {
+	problems := ValidationErrors{}
+	for i := range opts {
+		if err := opts[i].Validate(); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+
+	return nil
+}
+
 func (opts InstallationOptions) ForDeployment(deploymentID string) InstallationOptions {
 	result := InstallationOptions{}
 	for _, opt := range opts {
@@ -29,6 +29,18 @@ var _ = Describe("InstallationOption", func() {
 		})
 	})
 
+	Describe("FlagName", func() {
+		It("translates the name of shared options", func() {
+			option := InstallationOption{Name: "system_domain"}
+			Expect(option.FlagName()).To(Equal("system-domain"))
+		})
+
+		It("prefixes private options with their deployment", func() {
+			option := InstallationOption{Name: "admin_password", DeploymentID: "gitea"}
+			Expect(option.FlagName()).To(Equal("gitea-admin-password"))
+		})
+	})
+
 	Describe("DynDefault", func() {
 		option := InstallationOption{
 			Name:         "TheName",
@@ -273,9 +285,10 @@ var _ = Describe("InstallationOptions", func() {
 					},
 				}
 			})
-			It("panics", func() {
-				Expect(func() { options.GetString("Option", "") }).
-					To(PanicWith(MatchRegexp("wrong type assertion")))
+			It("returns an error", func() {
+				_, err := options.GetString("Option", "")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("is not a string"))
 			})
 		})
 
@@ -320,9 +333,10 @@ var _ = Describe("InstallationOptions", func() {
 					},
 				}
 			})
-			It("panics", func() {
-				Expect(func() { options.GetInt("Option", "") }).
-					To(PanicWith(MatchRegexp("wrong type assertion")))
+			It("returns an error", func() {
+				_, err := options.GetInt("Option", "")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("is not an integer"))
 			})
 		})
 
@@ -367,9 +381,10 @@ var _ = Describe("InstallationOptions", func() {
 					},
 				}
 			})
-			It("panics", func() {
-				Expect(func() { options.GetBool("Option", "") }).
-					To(PanicWith(MatchRegexp("wrong type assertion")))
+			It("returns an error", func() {
+				_, err := options.GetBool("Option", "")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("is not a boolean"))
 			})
 		})
 
@@ -3,6 +3,7 @@ package generic
 import (
 	"context"
 
+	"github.com/fuseml/fuseml/cli/kubernetes/platform"
 	"github.com/kyokomi/emoji"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -46,10 +47,20 @@ func (k *Generic) Load(kube *kubernetes.Clientset) error {
 	return nil
 }
 
+// ExternalIPs returns the external IPs of the nodes, or their internal ones
+// on bare metal, where nodes have no external IPs
 func (k *Generic) ExternalIPs() []string {
+	if len(k.ExternalIP) == 0 {
+		return k.InternalIPs
+	}
 	return k.ExternalIP
 }
 
+// Profile returns nothing, the static defaults fit generic clusters
+func (k *Generic) Profile() platform.Profile {
+	return platform.Profile{}
+}
+
 func NewPlatform() *Generic {
 	return &Generic{}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/fuseml/fuseml/cli/kubernetes/platform"
 	"github.com/fuseml/fuseml/cli/kubernetes/platform/generic"
 	"github.com/kyokomi/emoji"
 
@@ -13,6 +14,8 @@ import (
 
 type k3s struct {
 	generic.Generic
+	// bundledTraefik is the Traefik k3s installs, unless disabled
+	bundledTraefik bool
 }
 
 func (k *k3s) Describe() string {
@@ -38,6 +41,34 @@ func (k *k3s) ExternalIPs() []string {
 	return k.InternalIPs
 }
 
+// Load finds the nodes, and the Traefik bundled with k3s
+func (k *k3s) Load(kube *kubernetes.Clientset) error {
+	if err := k.Generic.Load(kube); err != nil {
+		return err
+	}
+
+	_, err := kube.CoreV1().Services("kube-system").Get(context.Background(), "traefik", metav1.GetOptions{})
+	k.bundledTraefik = err == nil
+
+	return nil
+}
+
+// Profile reuses the Traefik bundled with k3s, and its local-path
+// provisioner
+func (k *k3s) Profile() platform.Profile {
+	profile := platform.Profile{
+		Options: map[string]interface{}{
+			"storage_class": "local-path",
+		},
+	}
+	if k.bundledTraefik {
+		profile.Ingress = &platform.Ingress{Class: "traefik", Namespace: "kube-system", Service: "traefik"}
+		profile.Options["ingress_class"] = "traefik"
+	}
+
+	return profile
+}
+
 func NewPlatform() *k3s {
 	return &k3s{}
 }
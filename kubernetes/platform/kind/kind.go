@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/fuseml/fuseml/cli/kubernetes/platform"
 	"github.com/fuseml/fuseml/cli/kubernetes/platform/generic"
 
 	"github.com/kyokomi/emoji"
@@ -38,6 +39,15 @@ func (k *kind) ExternalIPs() []string {
 	return k.Generic.InternalIPs
 }
 
+// Profile exposes Traefik through a node port, as kind has no load balancers
+func (k *kind) Profile() platform.Profile {
+	return platform.Profile{
+		Options: map[string]interface{}{
+			"traefik.service_type": "NodePort",
+		},
+	}
+}
+
 func NewPlatform() *kind {
 	return &kind{}
 }
@@ -0,0 +1,77 @@
+package microk8s
+
+import (
+	"context"
+
+	"github.com/fuseml/fuseml/cli/kubernetes/platform"
+	"github.com/fuseml/fuseml/cli/kubernetes/platform/generic"
+
+	"github.com/kyokomi/emoji"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// MicroK8s represents the MicroK8s kubernetes platform.
+type MicroK8s struct {
+	generic.Generic
+	// metallb is the load balancer of the metallb addon, if enabled
+	metallb bool
+}
+
+// Describe returns information about the platform.
+func (m *MicroK8s) Describe() string {
+	return emoji.Sprintf(":anchor:Detected kubernetes platform: %s\n:earth_americas:ExternalIPs: %s\n:curly_loop:InternalIPs: %s", m.String(), m.ExternalIPs(), m.InternalIPs)
+}
+
+func (m *MicroK8s) String() string { return "microk8s" }
+
+// Detect detects if it is a MicroK8s platform.
+func (m *MicroK8s) Detect(kube *kubernetes.Clientset) bool {
+	nodes, err := kube.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return false
+	}
+	for _, n := range nodes.Items {
+		if _, found := n.GetLabels()["microk8s.io/cluster"]; found {
+			return true
+		}
+	}
+	return false
+}
+
+// Load finds the nodes, and the metallb addon
+func (m *MicroK8s) Load(kube *kubernetes.Clientset) error {
+	if err := m.Generic.Load(kube); err != nil {
+		return err
+	}
+
+	_, err := kube.CoreV1().Namespaces().Get(context.Background(), "metallb-system", metav1.GetOptions{})
+	m.metallb = err == nil
+
+	return nil
+}
+
+// ExternalIPs returns the node IPs.
+func (m *MicroK8s) ExternalIPs() []string {
+	return m.Generic.InternalIPs
+}
+
+// Profile uses the storage of the hostpath-storage addon, and exposes
+// Traefik through a node port, unless the metallb addon is enabled
+func (m *MicroK8s) Profile() platform.Profile {
+	profile := platform.Profile{
+		Options: map[string]interface{}{
+			"storage_class": "microk8s-hostpath",
+		},
+	}
+	if !m.metallb {
+		profile.Options["traefik.service_type"] = "NodePort"
+	}
+
+	return profile
+}
+
+// NewPlatform returns an instance of the MicroK8s struct.
+func NewPlatform() *MicroK8s {
+	return &MicroK8s{}
+}
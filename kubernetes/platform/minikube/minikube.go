@@ -3,6 +3,7 @@ package minikube
 import (
 	"context"
 
+	"github.com/fuseml/fuseml/cli/kubernetes/platform"
 	"github.com/fuseml/fuseml/cli/kubernetes/platform/generic"
 
 	"github.com/kyokomi/emoji"
@@ -13,6 +14,8 @@ import (
 // Minikube represents the minikube kubernetes platform.
 type Minikube struct {
 	generic.Generic
+	// ingress is the controller of the ingress addon, if enabled
+	ingress *platform.Ingress
 }
 
 // Describe returns information about the platform.
@@ -42,6 +45,46 @@ func (m *Minikube) ExternalIPs() []string {
 	return m.Generic.InternalIPs
 }
 
+// ingressAddonNamespaces are where the ingress addon runs, depending on the
+// minikube version
+var ingressAddonNamespaces = []string{"ingress-nginx", "kube-system"}
+
+// Load finds the nodes, and the controller of the ingress addon
+func (m *Minikube) Load(kube *kubernetes.Clientset) error {
+	if err := m.Generic.Load(kube); err != nil {
+		return err
+	}
+
+	m.ingress = nil
+	for _, namespace := range ingressAddonNamespaces {
+		_, err := kube.CoreV1().Services(namespace).Get(context.Background(), "ingress-nginx-controller", metav1.GetOptions{})
+		if err == nil {
+			m.ingress = &platform.Ingress{Class: "nginx", Namespace: namespace, Service: "ingress-nginx-controller"}
+			break
+		}
+	}
+
+	return nil
+}
+
+// Profile reuses the ingress addon, if enabled, or else exposes Traefik
+// through a node port, as load balancers need `minikube tunnel`
+func (m *Minikube) Profile() platform.Profile {
+	profile := platform.Profile{
+		Options: map[string]interface{}{
+			"storage_class": "standard",
+		},
+	}
+	if m.ingress != nil {
+		profile.Ingress = m.ingress
+		profile.Options["ingress_class"] = m.ingress.Class
+	} else {
+		profile.Options["traefik.service_type"] = "NodePort"
+	}
+
+	return profile
+}
+
 // NewPlatform returns an instance of minikube struct.
 func NewPlatform() *Minikube {
 	return &Minikube{}
@@ -0,0 +1,53 @@
+package openshift
+
+import (
+	"github.com/fuseml/fuseml/cli/kubernetes/platform"
+	"github.com/fuseml/fuseml/cli/kubernetes/platform/generic"
+
+	"github.com/kyokomi/emoji"
+	"k8s.io/client-go/kubernetes"
+)
+
+// OpenShift represents the OpenShift kubernetes platform.
+type OpenShift struct {
+	generic.Generic
+}
+
+// Describe returns information about the platform.
+func (o *OpenShift) Describe() string {
+	return emoji.Sprintf(":anchor:Detected kubernetes platform: %s\n:earth_americas:ExternalIPs: %s\n:curly_loop:InternalIPs: %s", o.String(), o.ExternalIPs(), o.InternalIPs)
+}
+
+func (o *OpenShift) String() string { return "openshift" }
+
+// Detect detects if it is an OpenShift platform, by its route API.
+func (o *OpenShift) Detect(kube *kubernetes.Clientset) bool {
+	groups, err := kube.Discovery().ServerGroups()
+	if err != nil {
+		return false
+	}
+	for _, group := range groups.Groups {
+		if group.Name == "route.openshift.io" {
+			return true
+		}
+	}
+	return false
+}
+
+// Profile reuses the OpenShift router, which turns the ingresses into
+// routes, and grants the anyuid SCC to the FuseML namespaces, as the
+// charts run their containers as fixed users
+func (o *OpenShift) Profile() platform.Profile {
+	return platform.Profile{
+		Options: map[string]interface{}{
+			"ingress_class": "openshift-default",
+		},
+		Ingress: &platform.Ingress{Class: "openshift-default", Namespace: "openshift-ingress", Service: "router-default"},
+		SCC:     "anyuid",
+	}
+}
+
+// NewPlatform returns an instance of the OpenShift struct.
+func NewPlatform() *OpenShift {
+	return &OpenShift{}
+}
@@ -0,0 +1,27 @@
+// Package platform holds what the kubernetes platforms, detected by the
+// packages below it, contribute to the installation of FuseML
+package platform
+
+// Profile is what a platform contributes to the installation of FuseML
+type Profile struct {
+	// Options are the values of installation options on the platform, by
+	// their qualified name, e.g. "storage_class" or "traefik.service_type".
+	// They replace the static defaults, but not the values given by the
+	// user.
+	Options map[string]interface{}
+	// Ingress is the ingress controller the platform provides, which is
+	// used instead of installing Traefik, if set
+	Ingress *Ingress
+	// SCC is the OpenShift security context constraint granted to the
+	// service accounts of the FuseML namespaces, if set
+	SCC string
+}
+
+// Ingress is an ingress controller provided by the platform
+type Ingress struct {
+	// Class is the ingress class the controller serves
+	Class string
+	// Namespace and Service locate the service exposing the controller
+	Namespace string
+	Service   string
+}
@@ -0,0 +1,38 @@
+package kubernetes
+
+import (
+	"github.com/fuseml/fuseml/cli/kubernetes/platform"
+)
+
+// PlatformOptionsReader fills options with the values of the profile of the
+// detected platform. It runs after the cli and values file readers, so it
+// skips every option already specified by the user. The values it sets
+// count as specified, so that neither the defaults nor the interactive
+// reader replace them.
+type PlatformOptionsReader struct {
+	profile platform.Profile
+}
+
+// NewPlatformOptionsReader is a reader used by the Installer to fill
+// InstallationOptions with the values of the platform profile
+func NewPlatformOptionsReader(profile platform.Profile) PlatformOptionsReader {
+	return PlatformOptionsReader{profile: profile}
+}
+
+// Read sets the option to the value of the profile, if it has one
+func (reader PlatformOptionsReader) Read(option *InstallationOption) error {
+	if option.UserSpecified {
+		return nil
+	}
+
+	value, ok := reader.profile.Options[option.QualifiedName()]
+	if !ok {
+		return nil
+	}
+
+	option.Value = value
+	option.UserSpecified = true
+	option.Source = SourcePlatform
+
+	return nil
+}
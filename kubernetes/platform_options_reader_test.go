@@ -0,0 +1,46 @@
+package kubernetes_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fuseml/fuseml/cli/kubernetes"
+	"github.com/fuseml/fuseml/cli/kubernetes/platform"
+)
+
+var _ = Describe("PlatformOptionsReader", func() {
+	reader := NewPlatformOptionsReader(platform.Profile{
+		Options: map[string]interface{}{
+			"storage_class":        "local-path",
+			"traefik.service_type": "NodePort",
+		},
+	})
+
+	It("sets global options of the profile", func() {
+		option := InstallationOption{Name: "storage_class", Type: StringType, Default: ""}
+		Expect(reader.Read(&option)).To(Succeed())
+		Expect(option.Value).To(Equal("local-path"))
+		Expect(option.UserSpecified).To(BeTrue())
+		Expect(option.Source).To(Equal(SourcePlatform))
+	})
+
+	It("sets private options by their qualified name", func() {
+		option := InstallationOption{Name: "service_type", DeploymentID: "traefik", Type: StringType, Default: "LoadBalancer"}
+		Expect(reader.Read(&option)).To(Succeed())
+		Expect(option.Value).To(Equal("NodePort"))
+	})
+
+	It("keeps the values of the user", func() {
+		option := InstallationOption{Name: "storage_class", Value: "fast", UserSpecified: true, Source: SourceCLI}
+		Expect(reader.Read(&option)).To(Succeed())
+		Expect(option.Value).To(Equal("fast"))
+		Expect(option.Source).To(Equal(SourceCLI))
+	})
+
+	It("ignores options not in the profile", func() {
+		option := InstallationOption{Name: "system_domain", Type: StringType, Default: ""}
+		Expect(reader.Read(&option)).To(Succeed())
+		Expect(option.UserSpecified).To(BeFalse())
+		Expect(option.Value).To(BeNil())
+	})
+})
@@ -0,0 +1,168 @@
+package kubernetes_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	restclient "k8s.io/client-go/rest"
+
+	. "github.com/fuseml/fuseml/cli/kubernetes"
+	"github.com/fuseml/fuseml/cli/kubernetes/platform"
+)
+
+// platformAPI is a minimal Kubernetes API of a single node cluster,
+// answering GET requests with the objects at their path
+type platformAPI map[string]interface{}
+
+func (a platformAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	object, ok := a[r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(metav1.Status{
+			TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+			Status:   metav1.StatusFailure,
+			Reason:   metav1.StatusReasonNotFound,
+			Code:     http.StatusNotFound,
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(object)
+}
+
+// newPlatformAPI returns the API of a cluster with a node with the given
+// provider ID and labels, and the given extra objects
+func newPlatformAPI(providerID string, labels map[string]string, objects map[string]interface{}) platformAPI {
+	api := platformAPI{
+		"/api": metav1.APIVersions{
+			TypeMeta: metav1.TypeMeta{Kind: "APIVersions"},
+			Versions: []string{"v1"},
+		},
+		"/apis": metav1.APIGroupList{
+			TypeMeta: metav1.TypeMeta{Kind: "APIGroupList", APIVersion: "v1"},
+		},
+		"/api/v1/nodes": v1.NodeList{
+			TypeMeta: metav1.TypeMeta{Kind: "NodeList", APIVersion: "v1"},
+			Items: []v1.Node{{
+				ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: labels},
+				Spec:       v1.NodeSpec{ProviderID: providerID},
+				Status: v1.NodeStatus{Addresses: []v1.NodeAddress{
+					{Type: v1.NodeInternalIP, Address: "10.0.0.1"},
+				}},
+			}},
+		},
+	}
+	for path, object := range objects {
+		api[path] = object
+	}
+
+	return api
+}
+
+func service(namespace, name string) v1.Service {
+	return v1.Service{
+		TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+	}
+}
+
+var _ = Describe("Platforms", func() {
+	DescribeTable("are detected, and profile the installation",
+		func(api platformAPI, name string, expected platform.Profile) {
+			server := httptest.NewServer(api)
+			defer server.Close()
+
+			cluster, err := NewClusterFromClient(&restclient.Config{Host: server.URL})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(cluster.GetPlatform().String()).To(Equal(name))
+			Expect(cluster.GetPlatform().ExternalIPs()).To(Equal([]string{"10.0.0.1"}))
+			Expect(cluster.GetPlatform().Profile()).To(Equal(expected))
+		},
+		Entry("k3s with its bundled traefik",
+			newPlatformAPI("k3s://node", nil, map[string]interface{}{
+				"/api/v1/namespaces/kube-system/services/traefik": service("kube-system", "traefik"),
+			}),
+			"k3s",
+			platform.Profile{
+				Options: map[string]interface{}{"storage_class": "local-path", "ingress_class": "traefik"},
+				Ingress: &platform.Ingress{Class: "traefik", Namespace: "kube-system", Service: "traefik"},
+			}),
+		Entry("k3s without traefik",
+			newPlatformAPI("k3s://node", nil, nil),
+			"k3s",
+			platform.Profile{
+				Options: map[string]interface{}{"storage_class": "local-path"},
+			}),
+		Entry("minikube with the ingress addon",
+			newPlatformAPI("", map[string]string{"minikube.k8s.io/version": "v1.18.1"}, map[string]interface{}{
+				"/api/v1/namespaces/ingress-nginx/services/ingress-nginx-controller": service("ingress-nginx", "ingress-nginx-controller"),
+			}),
+			"minikube",
+			platform.Profile{
+				Options: map[string]interface{}{"storage_class": "standard", "ingress_class": "nginx"},
+				Ingress: &platform.Ingress{Class: "nginx", Namespace: "ingress-nginx", Service: "ingress-nginx-controller"},
+			}),
+		Entry("minikube with the ingress addon of older versions",
+			newPlatformAPI("", map[string]string{"minikube.k8s.io/version": "v1.11.0"}, map[string]interface{}{
+				"/api/v1/namespaces/kube-system/services/ingress-nginx-controller": service("kube-system", "ingress-nginx-controller"),
+			}),
+			"minikube",
+			platform.Profile{
+				Options: map[string]interface{}{"storage_class": "standard", "ingress_class": "nginx"},
+				Ingress: &platform.Ingress{Class: "nginx", Namespace: "kube-system", Service: "ingress-nginx-controller"},
+			}),
+		Entry("minikube without ingress addon",
+			newPlatformAPI("", map[string]string{"minikube.k8s.io/version": "v1.18.1"}, nil),
+			"minikube",
+			platform.Profile{
+				Options: map[string]interface{}{"storage_class": "standard", "traefik.service_type": "NodePort"},
+			}),
+		Entry("microk8s with the metallb addon",
+			newPlatformAPI("", map[string]string{"microk8s.io/cluster": "true"}, map[string]interface{}{
+				"/api/v1/namespaces/metallb-system": v1.Namespace{
+					TypeMeta:   metav1.TypeMeta{Kind: "Namespace", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Name: "metallb-system"},
+				},
+			}),
+			"microk8s",
+			platform.Profile{
+				Options: map[string]interface{}{"storage_class": "microk8s-hostpath"},
+			}),
+		Entry("microk8s without load balancer",
+			newPlatformAPI("", map[string]string{"microk8s.io/cluster": "true"}, nil),
+			"microk8s",
+			platform.Profile{
+				Options: map[string]interface{}{"storage_class": "microk8s-hostpath", "traefik.service_type": "NodePort"},
+			}),
+		Entry("openshift by its route API",
+			newPlatformAPI("", nil, map[string]interface{}{
+				"/apis": metav1.APIGroupList{
+					TypeMeta: metav1.TypeMeta{Kind: "APIGroupList", APIVersion: "v1"},
+					Groups: []metav1.APIGroup{{
+						Name:             "route.openshift.io",
+						Versions:         []metav1.GroupVersionForDiscovery{{GroupVersion: "route.openshift.io/v1", Version: "v1"}},
+						PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "route.openshift.io/v1", Version: "v1"},
+					}},
+				},
+			}),
+			"openshift",
+			platform.Profile{
+				Options: map[string]interface{}{"ingress_class": "openshift-default"},
+				Ingress: &platform.Ingress{Class: "openshift-default", Namespace: "openshift-ingress", Service: "router-default"},
+				SCC:     "anyuid",
+			}),
+		Entry("generic clusters otherwise",
+			newPlatformAPI("", nil, nil),
+			"generic",
+			platform.Profile{}),
+	)
+})
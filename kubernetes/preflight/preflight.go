@@ -0,0 +1,292 @@
+// Package preflight checks whether a cluster, and the machine running the
+// cli, can hold a FuseML installation. The checks work on the state read
+// from the cluster, so that all problems are reported at once, before
+// installing.
+package preflight
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// Status is the outcome of a check
+type Status int
+
+const (
+	// Passed checks found no problem
+	Passed Status = iota
+	// Warning checks found something which may make parts of the
+	// installation, or some serving types, fail
+	Warning
+	// Failed checks found something the installation fails on
+	Failed
+)
+
+func (s Status) String() string {
+	switch s {
+	case Warning:
+		return "warning"
+	case Failed:
+		return "failed"
+	}
+	return "ok"
+}
+
+// Result is the outcome of a check, with details
+type Result struct {
+	Check   string
+	Status  Status
+	Details string
+}
+
+func passed(check, format string, a ...interface{}) Result {
+	return Result{Check: check, Status: Passed, Details: fmt.Sprintf(format, a...)}
+}
+
+func warning(check, format string, a ...interface{}) Result {
+	return Result{Check: check, Status: Warning, Details: fmt.Sprintf(format, a...)}
+}
+
+func failed(check, format string, a ...interface{}) Result {
+	return Result{Check: check, Status: Failed, Details: fmt.Sprintf(format, a...)}
+}
+
+// Failures returns the number of failed results
+func Failures(results []Result) int {
+	count := 0
+	for _, r := range results {
+		if r.Status == Failed {
+			count++
+		}
+	}
+	return count
+}
+
+const (
+	// MinKubernetesVersion is the oldest version FuseML runs on, the first
+	// one serving networking.k8s.io/v1 ingresses, which the cli creates and
+	// reads
+	MinKubernetesVersion = "1.19.0"
+	// MaxKubernetesVersion is the newest minor version FuseML is tested on
+	MaxKubernetesVersion = "1.20"
+)
+
+// KubernetesVersion checks that the server version is in the supported range
+func KubernetesVersion(serverVersion string) Result {
+	const check = "Kubernetes version"
+
+	v, err := version.ParseGeneric(serverVersion)
+	if err != nil {
+		return failed(check, "cannot parse server version %s", serverVersion)
+	}
+
+	if v.LessThan(version.MustParseGeneric(MinKubernetesVersion)) {
+		return failed(check, "%s is older than the required %s", serverVersion, MinKubernetesVersion)
+	}
+
+	max := version.MustParseGeneric(MaxKubernetesVersion)
+	if v.Major() > max.Major() || (v.Major() == max.Major() && v.Minor() > max.Minor()) {
+		return warning(check, "%s is newer than %s, the newest version tested", serverVersion, MaxKubernetesVersion)
+	}
+
+	return passed(check, "%s", serverVersion)
+}
+
+// defaultClassAnnotations mark the default storage class, the beta one is
+// still set by some provisioners
+var defaultClassAnnotations = []string{
+	"storageclass.kubernetes.io/is-default-class",
+	"storageclass.beta.kubernetes.io/is-default-class",
+}
+
+// DefaultStorageClass checks that volume claims without a storage class,
+// as made by the charts FuseML installs, can be provisioned
+func DefaultStorageClass(classes []storagev1.StorageClass) Result {
+	const check = "Default storage class"
+
+	defaults := []string{}
+	for _, class := range classes {
+		for _, annotation := range defaultClassAnnotations {
+			if class.Annotations[annotation] == "true" {
+				defaults = append(defaults, class.Name)
+				break
+			}
+		}
+	}
+
+	switch len(defaults) {
+	case 0:
+		return failed(check, "none of the %d storage classes is the default, the volumes of Gitea, MLflow and the registry would stay pending", len(classes))
+	case 1:
+		return passed(check, "%s", defaults[0])
+	}
+
+	return warning(check, "several defaults, %s", strings.Join(defaults, ", "))
+}
+
+// LoadBalancer checks whether LoadBalancer services get an external IP,
+// which the system domain defaults to, instead of a node IP and the node
+// port of the ingress. It looks at the load balancers existing already, the
+// platform, and the providers of the nodes.
+func LoadBalancer(platform string, services []corev1.Service, nodes []corev1.Node) Result {
+	const check = "Load balancer"
+
+	for _, service := range services {
+		if service.Spec.Type == corev1.ServiceTypeLoadBalancer && len(service.Status.LoadBalancer.Ingress) > 0 {
+			return passed(check, "service %s/%s has an external address", service.Namespace, service.Name)
+		}
+	}
+
+	switch platform {
+	case "k3s":
+		return passed(check, "k3s provides load balancers")
+	case "minikube":
+		return warning(check, "minikube needs `minikube tunnel` running for load balancers")
+	case "kind":
+		return warning(check, "kind has no load balancers, the ingress is reached at a node port")
+	}
+
+	for _, node := range nodes {
+		provider := node.Spec.ProviderID
+		if i := strings.Index(provider, "://"); i > 0 {
+			provider = provider[:i]
+		}
+		switch provider {
+		case "aws", "azure", "gce", "ibm", "openstack", "digitalocean":
+			return passed(check, "cloud provider %s", provider)
+		}
+	}
+
+	return warning(check, "no load balancer found, unless one is installed, e.g. MetalLB, the ingress is reached at a node port")
+}
+
+const (
+	// MinCPU is the CPU the FuseML components request in total
+	MinCPU = "2"
+	// MinMemory is the memory the FuseML components need in total
+	MinMemory = "6Gi"
+)
+
+// Resources checks that the nodes have enough CPU and memory allocatable
+func Resources(nodes []corev1.Node) Result {
+	const check = "Allocatable resources"
+
+	cpu := resource.Quantity{}
+	memory := resource.Quantity{}
+	for _, node := range nodes {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		cpu.Add(*node.Status.Allocatable.Cpu())
+		memory.Add(*node.Status.Allocatable.Memory())
+	}
+
+	details := fmt.Sprintf("%s CPU, %s memory", cpu.String(), memory.String())
+
+	minCPU := resource.MustParse(MinCPU)
+	minMemory := resource.MustParse(MinMemory)
+	if cpu.Cmp(minCPU) < 0 || memory.Cmp(minMemory) < 0 {
+		return failed(check, "%s, at least %s CPU and %s memory are needed", details, MinCPU, MinMemory)
+	}
+
+	return passed(check, "%s", details)
+}
+
+// Commands checks that the commands the cli runs are found in the PATH
+func Commands(commands ...string) Result {
+	const check = "Commands"
+
+	missing := []string{}
+	for _, command := range commands {
+		if _, err := exec.LookPath(command); err != nil {
+			missing = append(missing, command)
+		}
+	}
+
+	if len(missing) > 0 {
+		return failed(check, "not found in PATH: %s", strings.Join(missing, ", "))
+	}
+	if len(commands) == 0 {
+		return passed(check, "none needed")
+	}
+
+	return passed(check, "%s", strings.Join(commands, ", "))
+}
+
+// Namespaces checks that the namespaces FuseML creates are either missing,
+// or belong to FuseML, as the components skip namespaces not owned by it
+func Namespaces(wanted []string, existing []corev1.Namespace) Result {
+	const check = "Namespaces"
+
+	owned := map[string]bool{}
+	for _, namespace := range existing {
+		owned[namespace.Name] = namespace.Labels[kubernetes.FusemlDeploymentLabelKey] == kubernetes.FusemlDeploymentLabelValue
+	}
+
+	conflicts := []string{}
+	for _, name := range wanted {
+		if isOwned, exists := owned[name]; exists && !isOwned {
+			conflicts = append(conflicts, name)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return failed(check, "existing and not owned by FuseML: %s", strings.Join(conflicts, ", "))
+	}
+
+	return passed(check, "no conflicts")
+}
+
+// ServingType is a value of `fuseml push --serve`, with the CRDs it needs
+type ServingType struct {
+	Name string
+	CRDs []string
+}
+
+// ServingTypes are the serving types of applications. Knative and KFServing
+// run behind the istio ingress gateway.
+var ServingTypes = []ServingType{
+	{Name: "deployment"},
+	{Name: "knative", CRDs: []string{"services.serving.knative.dev", "gateways.networking.istio.io"}},
+	{Name: "kfserving", CRDs: []string{"inferenceservices.serving.kubeflow.org", "services.serving.knative.dev", "gateways.networking.istio.io"}},
+	{Name: "seldon_mlflow", CRDs: []string{"seldondeployments.machinelearning.seldon.io"}},
+	{Name: "seldon_sklearn", CRDs: []string{"seldondeployments.machinelearning.seldon.io"}},
+}
+
+// Serving checks which serving types are available, given the installed
+// CRDs. Missing CRDs are no failure, they only disable serving types.
+func Serving(crds []string) []Result {
+	installed := map[string]bool{}
+	for _, crd := range crds {
+		installed[crd] = true
+	}
+
+	results := []Result{}
+	for _, serving := range ServingTypes {
+		check := "--serve " + serving.Name
+
+		missing := []string{}
+		for _, crd := range serving.CRDs {
+			if !installed[crd] {
+				missing = append(missing, crd)
+			}
+		}
+		sort.Strings(missing)
+
+		if len(missing) > 0 {
+			results = append(results, warning(check, "unavailable, missing CRDs %s", strings.Join(missing, ", ")))
+		} else {
+			results = append(results, passed(check, "available"))
+		}
+	}
+
+	return results
+}
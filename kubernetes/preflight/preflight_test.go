@@ -0,0 +1,158 @@
+package preflight_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	. "github.com/fuseml/fuseml/cli/kubernetes/preflight"
+)
+
+var _ = Describe("Preflight checks", func() {
+	Describe("KubernetesVersion", func() {
+		It("passes supported versions", func() {
+			Expect(KubernetesVersion("v1.20.4+k3s1").Status).To(Equal(Passed))
+		})
+
+		It("fails old versions", func() {
+			Expect(KubernetesVersion("v1.18.9").Status).To(Equal(Failed))
+		})
+
+		It("warns about untested versions", func() {
+			Expect(KubernetesVersion("v1.21.0").Status).To(Equal(Warning))
+		})
+
+		It("fails unparsable versions", func() {
+			Expect(KubernetesVersion("unknown").Status).To(Equal(Failed))
+		})
+	})
+
+	Describe("DefaultStorageClass", func() {
+		class := func(name string, annotations map[string]string) storagev1.StorageClass {
+			return storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations}}
+		}
+
+		It("passes with one default", func() {
+			result := DefaultStorageClass([]storagev1.StorageClass{
+				class("standard", nil),
+				class("local-path", map[string]string{"storageclass.kubernetes.io/is-default-class": "true"}),
+			})
+			Expect(result.Status).To(Equal(Passed))
+			Expect(result.Details).To(Equal("local-path"))
+		})
+
+		It("accepts the beta annotation", func() {
+			result := DefaultStorageClass([]storagev1.StorageClass{
+				class("gp2", map[string]string{"storageclass.beta.kubernetes.io/is-default-class": "true"}),
+			})
+			Expect(result.Status).To(Equal(Passed))
+		})
+
+		It("fails without a default", func() {
+			Expect(DefaultStorageClass([]storagev1.StorageClass{class("standard", nil)}).Status).To(Equal(Failed))
+		})
+	})
+
+	Describe("LoadBalancer", func() {
+		It("passes when a load balancer has an address", func() {
+			service := corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "traefik", Namespace: "traefik"}}
+			service.Spec.Type = corev1.ServiceTypeLoadBalancer
+			service.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+			Expect(LoadBalancer("", []corev1.Service{service}, nil).Status).To(Equal(Passed))
+		})
+
+		It("passes on cloud providers", func() {
+			node := corev1.Node{Spec: corev1.NodeSpec{ProviderID: "aws:///eu-west-1a/i-123"}}
+			Expect(LoadBalancer("", nil, []corev1.Node{node}).Status).To(Equal(Passed))
+		})
+
+		It("warns on kind", func() {
+			Expect(LoadBalancer("kind", nil, nil).Status).To(Equal(Warning))
+		})
+
+		It("warns when nothing provides load balancers", func() {
+			Expect(LoadBalancer("", nil, nil).Status).To(Equal(Warning))
+		})
+	})
+
+	Describe("Resources", func() {
+		node := func(cpu, memory string, unschedulable bool) corev1.Node {
+			return corev1.Node{
+				Spec: corev1.NodeSpec{Unschedulable: unschedulable},
+				Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(memory),
+				}},
+			}
+		}
+
+		It("sums the resources of the nodes", func() {
+			result := Resources([]corev1.Node{node("1", "4Gi", false), node("2", "4Gi", false)})
+			Expect(result.Status).To(Equal(Passed))
+			Expect(result.Details).To(Equal("3 CPU, 8Gi memory"))
+		})
+
+		It("skips unschedulable nodes", func() {
+			result := Resources([]corev1.Node{node("1", "4Gi", false), node("2", "4Gi", true)})
+			Expect(result.Status).To(Equal(Failed))
+		})
+	})
+
+	Describe("Commands", func() {
+		It("fails for commands missing from the PATH", func() {
+			result := Commands("fuseml-no-such-command")
+			Expect(result.Status).To(Equal(Failed))
+			Expect(result.Details).To(ContainSubstring("fuseml-no-such-command"))
+		})
+
+		It("passes when no commands are needed", func() {
+			result := Commands()
+			Expect(result.Status).To(Equal(Passed))
+			Expect(result.Details).To(Equal("none needed"))
+		})
+	})
+
+	Describe("Namespaces", func() {
+		namespace := func(name string, owned bool) corev1.Namespace {
+			ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+			if owned {
+				ns.Labels = map[string]string{kubernetes.FusemlDeploymentLabelKey: kubernetes.FusemlDeploymentLabelValue}
+			}
+			return ns
+		}
+
+		It("passes with missing or owned namespaces", func() {
+			result := Namespaces([]string{"gitea", "traefik"}, []corev1.Namespace{namespace("gitea", true)})
+			Expect(result.Status).To(Equal(Passed))
+		})
+
+		It("fails with namespaces not owned by fuseml", func() {
+			result := Namespaces([]string{"gitea", "traefik"}, []corev1.Namespace{namespace("gitea", true), namespace("traefik", false)})
+			Expect(result.Status).To(Equal(Failed))
+			Expect(result.Details).To(ContainSubstring("traefik"))
+		})
+	})
+
+	Describe("Serving", func() {
+		It("reports the serving types missing CRDs as unavailable", func() {
+			results := Serving([]string{"services.serving.knative.dev", "gateways.networking.istio.io"})
+			statuses := map[string]Status{}
+			for _, r := range results {
+				statuses[r.Check] = r.Status
+			}
+			Expect(statuses).To(Equal(map[string]Status{
+				"--serve deployment":     Passed,
+				"--serve knative":        Passed,
+				"--serve kfserving":      Warning,
+				"--serve seldon_mlflow":  Warning,
+				"--serve seldon_sklearn": Warning,
+			}))
+			Expect(Failures(results)).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,70 @@
+package kubernetes
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// Renderer writes manifests into a directory instead of applying them, one
+// numbered file per manifest, in the order they would be applied
+type Renderer struct {
+	Dir   string
+	count int
+}
+
+// NewRenderer returns a renderer writing into dir, which is created if needed
+func NewRenderer(dir string) (*Renderer, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "failed to create render directory %s", dir)
+	}
+
+	return &Renderer{Dir: dir}, nil
+}
+
+// Manifest writes the manifest as the next file, called name
+func (r *Renderer) Manifest(name string, manifest []byte) error {
+	r.count++
+	file := filepath.Join(r.Dir, fmt.Sprintf("%02d-%s.yaml", r.count, name))
+
+	if err := ioutil.WriteFile(file, manifest, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write %s", file)
+	}
+
+	return nil
+}
+
+// Objects writes the objects as a multi-document manifest. Their kind and
+// apiVersion are filled in from the client-go scheme, as typed objects are
+// usually built without them.
+func (r *Renderer) Objects(name string, objects ...runtime.Object) error {
+	var manifest bytes.Buffer
+
+	for _, obj := range objects {
+		if obj.GetObjectKind().GroupVersionKind().Empty() {
+			kinds, _, err := scheme.Scheme.ObjectKinds(obj)
+			if err != nil {
+				return errors.Wrapf(err, "failed to render %s", name)
+			}
+			obj = obj.DeepCopyObject()
+			obj.GetObjectKind().SetGroupVersionKind(kinds[0])
+		}
+
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return errors.Wrapf(err, "failed to render %s", name)
+		}
+
+		manifest.WriteString("---\n")
+		manifest.Write(data)
+	}
+
+	return r.Manifest(name, manifest.Bytes())
+}
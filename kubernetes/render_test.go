@@ -0,0 +1,67 @@
+package kubernetes_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/fuseml/fuseml/cli/kubernetes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Renderer", func() {
+	var (
+		tmpDir string
+		r      *Renderer
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "fuseml-render-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		r, err = NewRenderer(filepath.Join(tmpDir, "deployment"))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	It("numbers the files in the order they are written", func() {
+		Expect(r.Manifest("first", []byte("kind: A\n"))).To(Succeed())
+		Expect(r.Manifest("second", []byte("kind: B\n"))).To(Succeed())
+
+		files, err := ioutil.ReadDir(r.Dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(files).To(HaveLen(2))
+		Expect(files[0].Name()).To(Equal("01-first.yaml"))
+		Expect(files[1].Name()).To(Equal("02-second.yaml"))
+	})
+
+	It("writes typed objects with their kind, as a decodable manifest", func() {
+		err := r.Objects("namespace",
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "fuseml"}},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "fuseml"},
+				StringData: map[string]string{"username": "dev"},
+			},
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		manifest, err := ioutil.ReadFile(filepath.Join(r.Dir, "01-namespace.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+
+		objects, err := DecodeManifest(manifest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(objects).To(HaveLen(2))
+		Expect(objects[0].GetAPIVersion()).To(Equal("v1"))
+		Expect(objects[0].GetKind()).To(Equal("Namespace"))
+		Expect(objects[1].GetKind()).To(Equal("Secret"))
+		Expect(objects[1].GetNamespace()).To(Equal("fuseml"))
+		Expect(objects[1].Object["stringData"]).To(HaveKeyWithValue("username", "dev"))
+	})
+})
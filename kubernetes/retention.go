@@ -0,0 +1,136 @@
+package kubernetes
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// AppNameLabelKey is the label placed on every PipelineRun created for an app
+	AppNameLabelKey = "fuseml/app-name"
+)
+
+// RetentionPolicy describes which finished PipelineRuns of an application
+// are kept in the cluster. A run is expired when it is not among the
+// KeepRuns most recent runs of its app, or when it is older than MaxAge.
+// A zero value for either field disables that limit.
+type RetentionPolicy struct {
+	KeepRuns int
+	MaxAge   time.Duration
+}
+
+// IsEmpty returns true if the policy never expires anything
+func (p RetentionPolicy) IsEmpty() bool {
+	return p.KeepRuns <= 0 && p.MaxAge <= 0
+}
+
+// Expired returns the PipelineRuns which should be removed according to the
+// policy. Runs are grouped per app using the AppNameLabelKey label. Runs
+// which are still executing are never returned, but they do count towards
+// the number of runs kept.
+func (p RetentionPolicy) Expired(runs []tektonv1beta1.PipelineRun, now time.Time) []tektonv1beta1.PipelineRun {
+	result := []tektonv1beta1.PipelineRun{}
+	if p.IsEmpty() {
+		return result
+	}
+
+	perApp := map[string][]tektonv1beta1.PipelineRun{}
+	apps := []string{}
+	for _, run := range runs {
+		app := run.Labels[AppNameLabelKey]
+		if _, found := perApp[app]; !found {
+			apps = append(apps, app)
+		}
+		perApp[app] = append(perApp[app], run)
+	}
+	sort.Strings(apps)
+
+	for _, app := range apps {
+		appRuns := perApp[app]
+
+		// Newest first
+		sort.SliceStable(appRuns, func(i, j int) bool {
+			return appRuns[j].CreationTimestamp.Before(&appRuns[i].CreationTimestamp)
+		})
+
+		for idx, run := range appRuns {
+			if !run.IsDone() {
+				continue
+			}
+
+			tooMany := p.KeepRuns > 0 && idx >= p.KeepRuns
+			tooOld := p.MaxAge > 0 && now.Sub(run.CreationTimestamp.Time) > p.MaxAge
+			if tooMany || tooOld {
+				result = append(result, run)
+			}
+		}
+	}
+
+	return result
+}
+
+// ListPipelineRuns returns the PipelineRuns in `namespace` with the given selector
+func (c *Cluster) ListPipelineRuns(namespace, selector string) (*tektonv1beta1.PipelineRunList, error) {
+	listOptions := metav1.ListOptions{}
+	if len(selector) > 0 {
+		listOptions.LabelSelector = selector
+	}
+
+	return c.TektonCS.TektonV1beta1().PipelineRuns(namespace).List(context.Background(), listOptions)
+}
+
+// DeletePipelineRun removes the given PipelineRun together with the workspace
+// PersistentVolumeClaims created from its volumeClaimTemplate. It returns the
+// storage requested by the removed claims.
+func (c *Cluster) DeletePipelineRun(run tektonv1beta1.PipelineRun) (resource.Quantity, error) {
+	reclaimed := resource.Quantity{}
+
+	pvcs, err := c.Kubectl.CoreV1().PersistentVolumeClaims(run.Namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return reclaimed, errors.Wrapf(err, "failed to list claims of PipelineRun %s", run.Name)
+	}
+
+	propagation := metav1.DeletePropagationBackground
+	err = c.TektonCS.TektonV1beta1().PipelineRuns(run.Namespace).Delete(context.Background(), run.Name,
+		metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil {
+		return reclaimed, errors.Wrapf(err, "failed to delete PipelineRun %s", run.Name)
+	}
+
+	// Tekton makes the PipelineRun the owner of the claims it creates, so
+	// the garbage collector would remove them eventually anyway. Deleting
+	// them here releases the storage right away.
+	for _, pvc := range pvcs.Items {
+		if !isOwnedBy(pvc.OwnerReferences, run.UID) {
+			continue
+		}
+
+		err := c.Kubectl.CoreV1().PersistentVolumeClaims(pvc.Namespace).Delete(context.Background(), pvc.Name, metav1.DeleteOptions{})
+		if err != nil {
+			return reclaimed, errors.Wrapf(err, "failed to delete claim %s of PipelineRun %s", pvc.Name, run.Name)
+		}
+
+		if size, found := pvc.Spec.Resources.Requests[v1.ResourceStorage]; found {
+			reclaimed.Add(size)
+		}
+	}
+
+	return reclaimed, nil
+}
+
+func isOwnedBy(owners []metav1.OwnerReference, uid types.UID) bool {
+	for _, owner := range owners {
+		if owner.UID == uid {
+			return true
+		}
+	}
+	return false
+}
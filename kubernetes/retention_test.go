@@ -0,0 +1,79 @@
+package kubernetes_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+
+	. "github.com/fuseml/fuseml/cli/kubernetes"
+)
+
+var _ = Describe("RetentionPolicy", func() {
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	run := func(name, app string, age time.Duration, done bool) tektonv1beta1.PipelineRun {
+		pr := tektonv1beta1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Labels:            map[string]string{AppNameLabelKey: app},
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+		}
+		status := v1.ConditionUnknown
+		if done {
+			status = v1.ConditionTrue
+		}
+		pr.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: status})
+		return pr
+	}
+
+	names := func(runs []tektonv1beta1.PipelineRun) []string {
+		result := []string{}
+		for _, r := range runs {
+			result = append(result, r.Name)
+		}
+		return result
+	}
+
+	runs := []tektonv1beta1.PipelineRun{
+		run("a-1", "a", 4*time.Hour, true),
+		run("a-3", "a", 2*time.Hour, true),
+		run("a-2", "a", 3*time.Hour, true),
+		run("a-4", "a", 1*time.Hour, false),
+		run("b-1", "b", 30*time.Hour, true),
+	}
+
+	Describe("Expired", func() {
+		It("keeps the most recent runs of every app", func() {
+			policy := RetentionPolicy{KeepRuns: 2}
+			Expect(names(policy.Expired(runs, now))).To(Equal([]string{"a-2", "a-1"}))
+		})
+
+		It("removes runs older than the maximum age", func() {
+			policy := RetentionPolicy{MaxAge: 24 * time.Hour}
+			Expect(names(policy.Expired(runs, now))).To(Equal([]string{"b-1"}))
+		})
+
+		It("combines both limits", func() {
+			policy := RetentionPolicy{KeepRuns: 3, MaxAge: 24 * time.Hour}
+			Expect(names(policy.Expired(runs, now))).To(Equal([]string{"a-1", "b-1"}))
+		})
+
+		It("never removes runs which are still executing", func() {
+			policy := RetentionPolicy{MaxAge: time.Minute}
+			Expect(names(policy.Expired(runs, now))).ToNot(ContainElement("a-4"))
+		})
+
+		It("expires nothing when empty", func() {
+			policy := RetentionPolicy{}
+			Expect(policy.IsEmpty()).To(BeTrue())
+			Expect(policy.Expired(runs, now)).To(BeEmpty())
+		})
+	})
+})
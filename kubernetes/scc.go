@@ -0,0 +1,65 @@
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SCCBinding returns the cluster role binding granting the OpenShift
+// security context constraint to all service accounts of the namespaces
+func SCCBinding(scc string, namespaces []string) *rbacv1.ClusterRoleBinding {
+	subjects := []rbacv1.Subject{}
+	for _, namespace := range namespaces {
+		subjects = append(subjects, rbacv1.Subject{
+			Kind:     rbacv1.GroupKind,
+			APIGroup: rbacv1.GroupName,
+			Name:     "system:serviceaccounts:" + namespace,
+		})
+	}
+
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta: metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   sccBindingName(scc),
+			Labels: map[string]string{FusemlDeploymentLabelKey: FusemlDeploymentLabelValue},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     "system:openshift:scc:" + scc,
+		},
+		Subjects: subjects,
+	}
+}
+
+func sccBindingName(scc string) string {
+	return "fuseml-scc-" + scc
+}
+
+// GrantSCC grants the security context constraint to the namespaces, see
+// SCCBinding
+func (c *Cluster) GrantSCC(scc string, namespaces []string) error {
+	bindings := c.Kubectl.RbacV1().ClusterRoleBindings()
+	binding := SCCBinding(scc, namespaces)
+
+	_, err := bindings.Create(context.Background(), binding, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		_, err = bindings.Update(context.Background(), binding, metav1.UpdateOptions{})
+	}
+
+	return errors.Wrapf(err, "failed to grant the %s security context constraint", scc)
+}
+
+// RevokeSCC removes the binding of GrantSCC, if it exists
+func (c *Cluster) RevokeSCC(scc string) error {
+	err := c.Kubectl.RbacV1().ClusterRoleBindings().Delete(context.Background(), sccBindingName(scc), metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	return errors.Wrapf(err, "failed to revoke the %s security context constraint", scc)
+}
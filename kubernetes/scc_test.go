@@ -0,0 +1,23 @@
+package kubernetes_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fuseml/fuseml/cli/kubernetes"
+)
+
+var _ = Describe("SCCBinding", func() {
+	It("binds the constraint to the service accounts of the namespaces", func() {
+		binding := SCCBinding("anyuid", []string{"fuseml-workloads", "gitea"})
+
+		Expect(binding.Name).To(Equal("fuseml-scc-anyuid"))
+		Expect(binding.Labels).To(HaveKeyWithValue(FusemlDeploymentLabelKey, FusemlDeploymentLabelValue))
+		Expect(binding.RoleRef.Kind).To(Equal("ClusterRole"))
+		Expect(binding.RoleRef.Name).To(Equal("system:openshift:scc:anyuid"))
+		Expect(binding.Subjects).To(HaveLen(2))
+		Expect(binding.Subjects[0].Kind).To(Equal("Group"))
+		Expect(binding.Subjects[0].Name).To(Equal("system:serviceaccounts:fuseml-workloads"))
+		Expect(binding.Subjects[1].Name).To(Equal("system:serviceaccounts:gitea"))
+	})
+})
@@ -0,0 +1,149 @@
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RetainedClaimAnnotation marks the persistent volumes kept on uninstall,
+// naming the claim, as NAMESPACE/NAME, they are given back to on reinstall
+const RetainedClaimAnnotation = "fuseml/retained-claim"
+
+// VolumeClaims returns the names of the persistent volume claims in the
+// namespace matching any of the label selectors, or all of them without
+// selectors
+func (c *Cluster) VolumeClaims(namespace string, selectors ...string) ([]string, error) {
+	claims, err := c.volumeClaims(namespace, selectors)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, claim := range claims {
+		names = append(names, claim.Name)
+	}
+
+	return names, nil
+}
+
+// RetainVolumes makes the persistent volumes bound to the claims in the
+// namespace matching any of the label selectors, or all of them without
+// selectors, outlive their claims, so that removing the claims, with or
+// without their namespace, keeps the data. It returns the names of the
+// retained claims.
+func (c *Cluster) RetainVolumes(namespace string, selectors ...string) ([]string, error) {
+	claims, err := c.volumeClaims(namespace, selectors)
+	if err != nil {
+		return nil, err
+	}
+
+	retained := []string{}
+	for _, claim := range claims {
+		if claim.Spec.VolumeName == "" {
+			continue
+		}
+
+		volumes := c.Kubectl.CoreV1().PersistentVolumes()
+		pv, err := volumes.Get(context.Background(), claim.Spec.VolumeName, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get volume of claim %s/%s", namespace, claim.Name)
+		}
+
+		RetainVolume(pv, claim)
+		if _, err := volumes.Update(context.Background(), pv, metav1.UpdateOptions{}); err != nil {
+			return nil, errors.Wrapf(err, "failed to retain volume of claim %s/%s", namespace, claim.Name)
+		}
+
+		retained = append(retained, claim.Name)
+	}
+
+	return retained, nil
+}
+
+// volumeClaims returns the persistent volume claims in the namespace
+// matching any of the label selectors, each claim once. Without selectors,
+// all claims of the namespace match.
+func (c *Cluster) volumeClaims(namespace string, selectors []string) ([]*v1.PersistentVolumeClaim, error) {
+	if len(selectors) == 0 {
+		selectors = []string{""}
+	}
+
+	result := []*v1.PersistentVolumeClaim{}
+	seen := map[string]bool{}
+	for _, selector := range selectors {
+		claims, err := c.Kubectl.CoreV1().PersistentVolumeClaims(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list volume claims of namespace %s", namespace)
+		}
+
+		for i := range claims.Items {
+			claim := &claims.Items[i]
+			if seen[claim.Name] {
+				continue
+			}
+			seen[claim.Name] = true
+			result = append(result, claim)
+		}
+	}
+
+	return result, nil
+}
+
+// ReleaseRetainedVolumes makes the volumes retained for the claims of the
+// namespace, whose claims are gone, available again to new claims of the
+// same names, e.g. those of a reinstall
+func (c *Cluster) ReleaseRetainedVolumes(namespace string) error {
+	volumes := c.Kubectl.CoreV1().PersistentVolumes()
+
+	pvs, err := volumes.List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list persistent volumes")
+	}
+
+	for i := range pvs.Items {
+		pv := &pvs.Items[i]
+		if !ReleaseVolume(pv, namespace) {
+			continue
+		}
+
+		if _, err := volumes.Update(context.Background(), pv, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to release volume %s", pv.Name)
+		}
+	}
+
+	return nil
+}
+
+// RetainVolume sets the reclaim policy of the volume to Retain and marks it
+// as retained for the claim
+func RetainVolume(pv *v1.PersistentVolume, claim *v1.PersistentVolumeClaim) {
+	pv.Spec.PersistentVolumeReclaimPolicy = v1.PersistentVolumeReclaimRetain
+	if pv.Annotations == nil {
+		pv.Annotations = map[string]string{}
+	}
+	pv.Annotations[RetainedClaimAnnotation] = claim.Namespace + "/" + claim.Name
+}
+
+// ReleaseVolume turns a retained volume, whose claim of the namespace is
+// gone, into one pre-bound to a new claim of the same name. It tells whether
+// the volume was changed.
+func ReleaseVolume(pv *v1.PersistentVolume, namespace string) bool {
+	ref := pv.Spec.ClaimRef
+	if pv.Status.Phase != v1.VolumeReleased || ref == nil || ref.Namespace != namespace {
+		return false
+	}
+	if pv.Annotations[RetainedClaimAnnotation] != ref.Namespace+"/"+ref.Name {
+		return false
+	}
+
+	pv.Spec.ClaimRef = &v1.ObjectReference{
+		Kind:      ref.Kind,
+		Namespace: ref.Namespace,
+		Name:      ref.Name,
+	}
+
+	return true
+}
@@ -0,0 +1,216 @@
+package kubernetes_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8s "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+
+	. "github.com/fuseml/fuseml/cli/kubernetes"
+)
+
+var _ = Describe("Retained volumes", func() {
+	var pv *v1.PersistentVolume
+	var claim *v1.PersistentVolumeClaim
+
+	BeforeEach(func() {
+		claim = &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data-gitea-0", Namespace: "gitea", UID: "1234"},
+		}
+		pv = &v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-1234"},
+			Spec: v1.PersistentVolumeSpec{
+				PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimDelete,
+				ClaimRef: &v1.ObjectReference{
+					Kind:            "PersistentVolumeClaim",
+					Namespace:       "gitea",
+					Name:            "data-gitea-0",
+					UID:             "1234",
+					ResourceVersion: "42",
+				},
+			},
+			Status: v1.PersistentVolumeStatus{Phase: v1.VolumeBound},
+		}
+	})
+
+	Describe("RetainVolume", func() {
+		It("keeps the volume and marks it for the claim", func() {
+			RetainVolume(pv, claim)
+			Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(v1.PersistentVolumeReclaimRetain))
+			Expect(pv.Annotations).To(HaveKeyWithValue(RetainedClaimAnnotation, "gitea/data-gitea-0"))
+		})
+	})
+
+	Describe("ReleaseVolume", func() {
+		BeforeEach(func() {
+			RetainVolume(pv, claim)
+			pv.Status.Phase = v1.VolumeReleased
+		})
+
+		It("pre-binds the released volume to a new claim of the same name", func() {
+			Expect(ReleaseVolume(pv, "gitea")).To(BeTrue())
+			Expect(pv.Spec.ClaimRef).To(Equal(&v1.ObjectReference{
+				Kind:      "PersistentVolumeClaim",
+				Namespace: "gitea",
+				Name:      "data-gitea-0",
+			}))
+		})
+
+		It("ignores volumes of other namespaces", func() {
+			Expect(ReleaseVolume(pv, "registry")).To(BeFalse())
+			Expect(pv.Spec.ClaimRef.UID).To(BeEquivalentTo("1234"))
+		})
+
+		It("ignores volumes still bound", func() {
+			pv.Status.Phase = v1.VolumeBound
+			Expect(ReleaseVolume(pv, "gitea")).To(BeFalse())
+		})
+
+		It("ignores volumes not retained by fuseml", func() {
+			delete(pv.Annotations, RetainedClaimAnnotation)
+			Expect(ReleaseVolume(pv, "gitea")).To(BeFalse())
+		})
+	})
+})
+
+// volumesAPI is a minimal Kubernetes API holding the claims of a namespace
+// and their volumes. It lists the claims matching the label selector, and
+// stores the volumes updated.
+type volumesAPI struct {
+	mu      sync.Mutex
+	claims  []v1.PersistentVolumeClaim
+	volumes map[string]v1.PersistentVolume
+}
+
+func (a *volumesAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case r.URL.Path == "/api/v1/namespaces/fuseml-workloads/persistentvolumeclaims":
+		selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+		Expect(err).ToNot(HaveOccurred())
+
+		list := v1.PersistentVolumeClaimList{
+			TypeMeta: metav1.TypeMeta{Kind: "PersistentVolumeClaimList", APIVersion: "v1"},
+		}
+		for _, claim := range a.claims {
+			if selector.Matches(labels.Set(claim.Labels)) {
+				list.Items = append(list.Items, claim)
+			}
+		}
+		json.NewEncoder(w).Encode(list)
+
+	case strings.HasPrefix(r.URL.Path, "/api/v1/persistentvolumes/"):
+		name := strings.TrimPrefix(r.URL.Path, "/api/v1/persistentvolumes/")
+		if r.Method == http.MethodPut {
+			var pv v1.PersistentVolume
+			Expect(json.NewDecoder(r.Body).Decode(&pv)).To(Succeed())
+			a.volumes[name] = pv
+		}
+		json.NewEncoder(w).Encode(a.volumes[name])
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(metav1.Status{
+			TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+			Status:   metav1.StatusFailure,
+			Reason:   metav1.StatusReasonNotFound,
+			Code:     http.StatusNotFound,
+		})
+	}
+}
+
+// add adds the claim, bound to its volume
+func (a *volumesAPI) add(name string, labels map[string]string) {
+	a.claims = append(a.claims, v1.PersistentVolumeClaim{
+		TypeMeta:   metav1.TypeMeta{Kind: "PersistentVolumeClaim", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "fuseml-workloads", Labels: labels},
+		Spec:       v1.PersistentVolumeClaimSpec{VolumeName: "pv-" + name},
+	})
+	a.volumes["pv-"+name] = v1.PersistentVolume{
+		TypeMeta:   metav1.TypeMeta{Kind: "PersistentVolume", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-" + name},
+		Spec: v1.PersistentVolumeSpec{
+			PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimDelete,
+		},
+	}
+}
+
+var _ = Describe("RetainVolumes", func() {
+	var (
+		api     *volumesAPI
+		server  *httptest.Server
+		cluster *Cluster
+	)
+
+	// The selectors of the MLflow data
+	selectors := []string{
+		"app.kubernetes.io/instance=mlflow",
+		"app=minio,release=mlflow",
+	}
+
+	BeforeEach(func() {
+		api = &volumesAPI{volumes: map[string]v1.PersistentVolume{}}
+		// The labels of the claims of the MySQL and MinIO subcharts of the
+		// embedded MLflow chart
+		api.add("data-mlflow-mysql-0", map[string]string{
+			"app.kubernetes.io/name":      "mysql",
+			"app.kubernetes.io/instance":  "mlflow",
+			"app.kubernetes.io/component": "primary",
+		})
+		api.add("mlflow-minio", map[string]string{
+			"app":      "minio",
+			"chart":    "minio-8.0.10",
+			"release":  "mlflow",
+			"heritage": "Helm",
+		})
+		api.add("cache", map[string]string{
+			"app": "minio",
+		})
+
+		server = httptest.NewServer(api)
+		clientset, err := k8s.NewForConfig(&restclient.Config{Host: server.URL})
+		Expect(err).ToNot(HaveOccurred())
+		cluster = &Cluster{Kubectl: clientset}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("retains the volumes of the claims matching any of the selectors", func() {
+		retained, err := cluster.RetainVolumes("fuseml-workloads", selectors...)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retained).To(ConsistOf("data-mlflow-mysql-0", "mlflow-minio"))
+
+		Expect(api.volumes["pv-data-mlflow-mysql-0"].Spec.PersistentVolumeReclaimPolicy).To(Equal(v1.PersistentVolumeReclaimRetain))
+		Expect(api.volumes["pv-mlflow-minio"].Spec.PersistentVolumeReclaimPolicy).To(Equal(v1.PersistentVolumeReclaimRetain))
+		Expect(api.volumes["pv-mlflow-minio"].Annotations).To(HaveKeyWithValue(RetainedClaimAnnotation, "fuseml-workloads/mlflow-minio"))
+		Expect(api.volumes["pv-cache"].Spec.PersistentVolumeReclaimPolicy).To(Equal(v1.PersistentVolumeReclaimDelete))
+	})
+
+	It("lists each claim once, even if it matches several selectors", func() {
+		claims, err := cluster.VolumeClaims("fuseml-workloads", append(selectors, "release=mlflow")...)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(claims).To(Equal([]string{"data-mlflow-mysql-0", "mlflow-minio"}))
+	})
+
+	It("retains the volumes of all claims of the namespace without selectors", func() {
+		retained, err := cluster.RetainVolumes("fuseml-workloads")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retained).To(ConsistOf("data-mlflow-mysql-0", "mlflow-minio", "cache"))
+	})
+})
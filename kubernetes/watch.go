@@ -0,0 +1,297 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fuseml/fuseml/cli/paas/ui"
+	"github.com/pkg/errors"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+	"knative.dev/pkg/apis"
+)
+
+// This file implements the waits for pods, pipeline runs and namespaces.
+// Instead of polling, they watch the resources, and report the changes of
+// their state to the UI as they happen.
+
+// listWatch returns the lister and watcher of the resources of the client
+// in the namespace, matching the label selector and the field selector
+func listWatch(client rest.Interface, resource, namespace, selector string, fieldSelector fields.Selector) cache.ListerWatcher {
+	return cache.NewFilteredListWatchFromClient(client, resource, namespace, func(options *metav1.ListOptions) {
+		options.LabelSelector = selector
+		if fieldSelector != nil {
+			options.FieldSelector = fieldSelector.String()
+		}
+	})
+}
+
+// watchUntil watches the resources of lw for up to timeout, until condition
+// holds for one of them. An optional precondition is checked first against
+// the state observed when the watch starts.
+func watchUntil(lw cache.ListerWatcher, obj runtime.Object, timeout time.Duration, precondition watchtools.PreconditionFunc, condition func(event watch.Event) (bool, error)) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	_, err := watchtools.UntilWithSync(ctx, lw, obj, precondition, condition)
+
+	return err
+}
+
+// WaitUntilPodBySelectorExist waits up to timeout seconds for a pod in
+// 'namespace' with given 'selector' to be created
+func (c *Cluster) WaitUntilPodBySelectorExist(ui *ui.UI, namespace, selector string, timeout int) error {
+	s := ui.Progressf("Creating %s in %s", selector, namespace)
+	defer s.Stop()
+
+	lw := listWatch(c.Kubectl.CoreV1().RESTClient(), "pods", namespace, selector, nil)
+
+	return watchUntil(lw, &v1.Pod{}, time.Duration(timeout)*time.Second, nil,
+		func(event watch.Event) (bool, error) {
+			return event.Type != watch.Deleted, nil
+		})
+}
+
+// WaitForPodBySelectorRunning waits timeout seconds for all pods in 'namespace'
+// with given 'selector' to enter running state. Returns an error if no pods are
+// found or not all discovered pods enter running state.
+func (c *Cluster) WaitForPodBySelectorRunning(ui *ui.UI, namespace, selector string, timeout int) error {
+	s := ui.Progressf("Starting %s in %s", selector, namespace)
+	defer s.Stop()
+
+	podList, err := c.ListPods(namespace, selector)
+	if err != nil {
+		return errors.Wrapf(err, "failed listingpods with selector %s", selector)
+	}
+
+	if len(podList.Items) == 0 {
+		return fmt.Errorf("no pods in %s with selector %s", namespace, selector)
+	}
+
+	for _, pod := range podList.Items {
+		s.ChangeMessagef("  Starting pod %s in %s", pod.Name, namespace)
+
+		name := pod.Name
+		state := ""
+		lw := listWatch(c.Kubectl.CoreV1().RESTClient(), "pods", namespace, "", fields.OneTermEqualSelector("metadata.name", name))
+		err := watchUntil(lw, &v1.Pod{}, time.Duration(timeout)*time.Second, nil,
+			func(event watch.Event) (bool, error) {
+				pod, ok := event.Object.(*v1.Pod)
+				if !ok || event.Type == watch.Deleted {
+					return false, nil
+				}
+				if PodRunningAndReady(pod) {
+					return true, nil
+				}
+				if reason := PodWaitReason(pod); reason != state {
+					state = reason
+					s.ChangeMessagef("  Starting pod %s in %s: %s", name, namespace, reason)
+				}
+				return false, nil
+			})
+		if err != nil {
+			events, err2 := c.GetPodEvents(namespace, name)
+			if err2 != nil {
+				return errors.Wrap(err, err2.Error())
+			}
+			if state != "" {
+				err = errors.Wrap(err, state)
+			}
+			return errors.New(fmt.Sprintf("Failed waiting for %s: %s\nPod Events: \n%s", name, err.Error(), events))
+		}
+	}
+	return nil
+}
+
+// WaitUntilPipelineRunExists waits up to timeout seconds for a pipelinerun
+// in 'namespace' with given 'selector' to be created
+func (c *Cluster) WaitUntilPipelineRunExists(ui *ui.UI, namespace, selector string, timeout int) error {
+	s := ui.Progressf("Checking existence of PipelineRun identified by %s in %s", selector, namespace)
+	defer s.Stop()
+
+	lw := listWatch(c.TektonCS.TektonV1beta1().RESTClient(), "pipelineruns", namespace, selector, nil)
+
+	return watchUntil(lw, &tektonv1beta1.PipelineRun{}, time.Duration(timeout)*time.Second, nil,
+		func(event watch.Event) (bool, error) {
+			return event.Type != watch.Deleted, nil
+		})
+}
+
+// WaitForPipelineRunSuccess waits timeout seconds for the pipelinerun in
+// 'namespace' with given 'selector' to succeed, showing its running tasks.
+// Returns an error if it fails.
+func (c *Cluster) WaitForPipelineRunSuccess(ui *ui.UI, namespace, selector string, timeout int) error {
+	s := ui.Progressf("Starting %s in %s", selector, namespace)
+	defer s.Stop()
+
+	pipelineRunClient := c.TektonCS.TektonV1beta1().PipelineRuns(namespace)
+	listOptions := metav1.ListOptions{}
+	if len(selector) > 0 {
+		listOptions.LabelSelector = selector
+	}
+	pipelineRunList, err := pipelineRunClient.List(context.Background(), listOptions)
+
+	if err != nil {
+		return errors.Wrapf(err, "Failed listing PipelineRuns with selector %s", selector)
+	}
+
+	if len(pipelineRunList.Items) == 0 {
+		return fmt.Errorf("No pods in %s with selector %s", namespace, selector)
+	}
+
+	name := pipelineRunList.Items[0].Name
+	state := ""
+
+	// wait until it finishes or times out
+	lw := listWatch(c.TektonCS.TektonV1beta1().RESTClient(), "pipelineruns", namespace, "", fields.OneTermEqualSelector("metadata.name", name))
+	err = watchUntil(lw, &tektonv1beta1.PipelineRun{}, time.Duration(timeout)*time.Second, nil,
+		func(event watch.Event) (bool, error) {
+			pr, ok := event.Object.(*tektonv1beta1.PipelineRun)
+			if !ok {
+				return false, nil
+			}
+			if event.Type == watch.Deleted {
+				return false, errors.Errorf("PipelineRun %s was deleted", name)
+			}
+			if done, err := PipelineRunFinished(pr); done || err != nil {
+				return done, err
+			}
+			if progress := PipelineRunProgress(pr); progress != state {
+				state = progress
+				s.ChangeMessagef("PipelineRun %s: %s", name, progress)
+			}
+			return false, nil
+		})
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("Failed waiting for PipelineRun (%s) to succeed %s: ", selector, err.Error()))
+	}
+	return nil
+}
+
+// WaitForNamespaceGone waits up to timeout for the namespace to be removed
+// completely, after its deletion
+func (c *Cluster) WaitForNamespaceGone(namespace string, timeout time.Duration) error {
+	lw := listWatch(c.Kubectl.CoreV1().RESTClient(), "namespaces", "", "", fields.OneTermEqualSelector("metadata.name", namespace))
+
+	return watchUntil(lw, &v1.Namespace{}, timeout,
+		func(store cache.Store) (bool, error) {
+			return len(store.List()) == 0, nil
+		},
+		func(event watch.Event) (bool, error) {
+			return event.Type == watch.Deleted, nil
+		})
+}
+
+// PodRunningAndReady tells whether the pod is running, or done, with all of
+// its init containers finished and its containers ready
+func PodRunningAndReady(pod *v1.Pod) bool {
+	for _, cont := range pod.Status.InitContainerStatuses {
+		if cont.State.Waiting != nil || cont.State.Running != nil {
+			return false
+		}
+	}
+
+	for _, cont := range pod.Status.ContainerStatuses {
+		if cont.State.Waiting != nil || !cont.Ready {
+			return false
+		}
+	}
+
+	return pod.Status.Phase == v1.PodRunning || pod.Status.Phase == v1.PodSucceeded
+}
+
+// PodWaitReason describes why the pod is not running and ready yet, e.g.
+// that it cannot be scheduled, or that the image of a container cannot be
+// pulled
+func PodWaitReason(pod *v1.Pod) string {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == v1.PodScheduled && cond.Status == v1.ConditionFalse {
+			return describeReason(strings.ToLower(cond.Reason), cond.Message)
+		}
+	}
+
+	for _, cont := range pod.Status.InitContainerStatuses {
+		if w := cont.State.Waiting; w != nil && w.Reason != "PodInitializing" {
+			return describeReason("init container "+cont.Name+" "+w.Reason, w.Message)
+		}
+		if cont.State.Running != nil {
+			return "running init container " + cont.Name
+		}
+	}
+
+	for _, cont := range pod.Status.ContainerStatuses {
+		if w := cont.State.Waiting; w != nil {
+			return describeReason("container "+cont.Name+" "+w.Reason, w.Message)
+		}
+	}
+
+	for _, cont := range pod.Status.ContainerStatuses {
+		if !cont.Ready {
+			return "container " + cont.Name + " not ready"
+		}
+	}
+
+	return strings.ToLower(string(pod.Status.Phase))
+}
+
+func describeReason(reason, message string) string {
+	if message == "" {
+		return reason
+	}
+	return reason + ": " + message
+}
+
+// PipelineRunFinished tells whether the pipeline run is done. It returns an
+// error if the run failed.
+func PipelineRunFinished(pr *tektonv1beta1.PipelineRun) (bool, error) {
+	cond := pr.Status.GetCondition(apis.ConditionSucceeded)
+	if cond == nil {
+		return false, nil
+	}
+
+	switch cond.Status {
+	case v1.ConditionTrue:
+		return true, nil
+	case v1.ConditionFalse:
+		return true, errors.Errorf("PipelineRun %s failed: %s", pr.Name, describeReason(cond.Reason, cond.Message))
+	}
+
+	return false, nil
+}
+
+// PipelineRunProgress describes the state of an unfinished pipeline run,
+// i.e. the tasks which are running
+func PipelineRunProgress(pr *tektonv1beta1.PipelineRun) string {
+	running := []string{}
+	for _, tr := range pr.Status.TaskRuns {
+		if tr.Status == nil {
+			continue
+		}
+		cond := tr.Status.GetCondition(apis.ConditionSucceeded)
+		if cond == nil || cond.Status == v1.ConditionUnknown {
+			running = append(running, tr.PipelineTaskName)
+		}
+	}
+	sort.Strings(running)
+
+	if len(running) > 0 {
+		return "running " + strings.Join(running, ", ")
+	}
+
+	if cond := pr.Status.GetCondition(apis.ConditionSucceeded); cond != nil && cond.Reason != "" {
+		return strings.ToLower(cond.Reason)
+	}
+
+	return "pending"
+}
@@ -0,0 +1,125 @@
+package kubernetes_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+
+	. "github.com/fuseml/fuseml/cli/kubernetes"
+)
+
+var _ = Describe("Watched states", func() {
+	Describe("PodRunningAndReady and PodWaitReason", func() {
+		var pod *v1.Pod
+
+		BeforeEach(func() {
+			pod = &v1.Pod{
+				Status: v1.PodStatus{
+					Phase: v1.PodRunning,
+					ContainerStatuses: []v1.ContainerStatus{
+						{Name: "gitea", Ready: true, State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+					},
+				},
+			}
+		})
+
+		It("accepts a running pod with ready containers", func() {
+			Expect(PodRunningAndReady(pod)).To(BeTrue())
+		})
+
+		It("explains an unschedulable pod", func() {
+			pod.Status.Phase = v1.PodPending
+			pod.Status.ContainerStatuses = nil
+			pod.Status.Conditions = []v1.PodCondition{{
+				Type:    v1.PodScheduled,
+				Status:  v1.ConditionFalse,
+				Reason:  "Unschedulable",
+				Message: "0/1 nodes are available: 1 Insufficient cpu.",
+			}}
+			Expect(PodRunningAndReady(pod)).To(BeFalse())
+			Expect(PodWaitReason(pod)).To(Equal("unschedulable: 0/1 nodes are available: 1 Insufficient cpu."))
+		})
+
+		It("explains a waiting container", func() {
+			pod.Status.Phase = v1.PodPending
+			pod.Status.ContainerStatuses[0].Ready = false
+			pod.Status.ContainerStatuses[0].State = v1.ContainerState{
+				Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"},
+			}
+			Expect(PodRunningAndReady(pod)).To(BeFalse())
+			Expect(PodWaitReason(pod)).To(Equal("container gitea ImagePullBackOff: Back-off pulling image"))
+		})
+
+		It("reports running init containers", func() {
+			pod.Status.Phase = v1.PodPending
+			pod.Status.InitContainerStatuses = []v1.ContainerStatus{
+				{Name: "init", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+			}
+			Expect(PodRunningAndReady(pod)).To(BeFalse())
+			Expect(PodWaitReason(pod)).To(Equal("running init container init"))
+		})
+
+		It("reports containers not ready", func() {
+			pod.Status.ContainerStatuses[0].Ready = false
+			Expect(PodRunningAndReady(pod)).To(BeFalse())
+			Expect(PodWaitReason(pod)).To(Equal("container gitea not ready"))
+		})
+	})
+
+	Describe("PipelineRunFinished and PipelineRunProgress", func() {
+		var pr *tektonv1beta1.PipelineRun
+
+		taskRun := func(task string, status v1.ConditionStatus) *tektonv1beta1.PipelineRunTaskRunStatus {
+			trs := &tektonv1beta1.TaskRunStatus{}
+			trs.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: status})
+			return &tektonv1beta1.PipelineRunTaskRunStatus{PipelineTaskName: task, Status: trs}
+		}
+
+		BeforeEach(func() {
+			pr = &tektonv1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "run-1"}}
+		})
+
+		It("is pending without a status", func() {
+			done, err := PipelineRunFinished(pr)
+			Expect(done).To(BeFalse())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(PipelineRunProgress(pr)).To(Equal("pending"))
+		})
+
+		It("lists the running tasks", func() {
+			pr.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: v1.ConditionUnknown, Reason: "Running"})
+			pr.Status.TaskRuns = map[string]*tektonv1beta1.PipelineRunTaskRunStatus{
+				"run-1-clone": taskRun("clone", v1.ConditionTrue),
+				"run-1-train": taskRun("train", v1.ConditionUnknown),
+				"run-1-build": taskRun("build", v1.ConditionUnknown),
+			}
+			done, err := PipelineRunFinished(pr)
+			Expect(done).To(BeFalse())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(PipelineRunProgress(pr)).To(Equal("running build, train"))
+		})
+
+		It("succeeds", func() {
+			pr.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: v1.ConditionTrue})
+			done, err := PipelineRunFinished(pr)
+			Expect(done).To(BeTrue())
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("fails with the reason", func() {
+			pr.Status.SetCondition(&apis.Condition{
+				Type:    apis.ConditionSucceeded,
+				Status:  v1.ConditionFalse,
+				Reason:  "Failed",
+				Message: "Tasks Completed: 2 (Failed: 1, Cancelled 0), Skipped: 3",
+			})
+			done, err := PipelineRunFinished(pr)
+			Expect(done).To(BeTrue())
+			Expect(err).To(MatchError("PipelineRun run-1 failed: Failed: Tasks Completed: 2 (Failed: 1, Cancelled 0), Skipped: 3"))
+		})
+	})
+})
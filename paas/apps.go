@@ -0,0 +1,214 @@
+package paas
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/fuseml/fuseml/cli/paas/config"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	knversionedclient "knative.dev/serving/pkg/client/clientset/versioned"
+)
+
+// appGUIDLabel identifies the application of the serving resources, as
+// ORG.APP
+const appGUIDLabel = "fuseml/app-guid"
+
+// appResources are the serving resources of the applications of an org,
+// indexed by application name. They are fetched with one list per kind,
+// instead of one per application.
+type appResources struct {
+	istio       bool
+	knative     bool
+	domain      string
+	ingressPort int
+	deployments map[string]appsv1.Deployment
+	knServices  map[string]servingv1.Service
+	ingresses   map[string]networkingv1.Ingress
+}
+
+// concurrently runs the functions at the same time and returns the error of
+// the first one in argument order that failed
+func concurrently(fns ...func() error) error {
+	errs := make([]error, len(fns))
+
+	var wg sync.WaitGroup
+	for i, fn := range fns {
+		wg.Add(1)
+		go func(i int, fn func() error) {
+			defer wg.Done()
+			errs[i] = fn()
+		}(i, fn)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// orgAppResources fetches the serving resources of all applications of the
+// org, resolving the independent lookups concurrently
+func (c *FusemlClient) orgAppResources(org string) (*appResources, error) {
+	r := &appResources{
+		ingressPort: c.config.IngressPort,
+		deployments: map[string]appsv1.Deployment{},
+		knServices:  map[string]servingv1.Service{},
+		ingresses:   map[string]networkingv1.Ingress{},
+	}
+
+	err := concurrently(
+		func() error {
+			r.istio = c.kubeClient.HasIstio()
+			return nil
+		},
+		func() error {
+			r.knative = c.kubeClient.HasKnative()
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := c.config.FusemlWorkloadsNamespace
+	selector := metav1.ListOptions{LabelSelector: appGUIDLabel}
+
+	lookups := []func() error{
+		func() error {
+			list, err := c.kubeClient.Kubectl.AppsV1().Deployments(namespace).List(context.Background(), selector)
+			if err != nil {
+				return errors.Wrap(err, "failed to list app deployments")
+			}
+			for _, d := range list.Items {
+				app, ok := orgApp(org, d.Labels)
+				if _, seen := r.deployments[app]; ok && !seen {
+					r.deployments[app] = d
+				}
+			}
+			return nil
+		},
+		func() error {
+			list, err := c.kubeClient.Kubectl.NetworkingV1().Ingresses(namespace).List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				return errors.Wrap(err, "failed to list ingresses")
+			}
+			for _, ing := range list.Items {
+				r.ingresses[ing.Name] = ing
+			}
+			return nil
+		},
+	}
+
+	if r.istio || r.knative {
+		lookups = append(lookups, func() error {
+			domain, err := c.giteaResolver.GetMainDomain()
+			if err != nil {
+				return errors.Wrap(err, "failed to determine fuseml domain")
+			}
+			r.domain = domain
+			return nil
+		})
+	}
+
+	if r.knative {
+		lookups = append(lookups, func() error {
+			knc, err := knversionedclient.NewForConfig(c.kubeClient.RestConfig)
+			if err != nil {
+				return errors.Wrap(err, "failed to create knative client.")
+			}
+
+			list, err := knc.ServingV1().Services(namespace).List(context.Background(), selector)
+			if err != nil {
+				return errors.Wrap(err, "failed to get knative service")
+			}
+			for _, s := range list.Items {
+				app, ok := orgApp(org, s.Labels)
+				if _, seen := r.knServices[app]; ok && !seen {
+					r.knServices[app] = s
+				}
+			}
+			return nil
+		})
+	}
+
+	if err := concurrently(lookups...); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// orgApp returns the name of the application of org the labels belong to
+func orgApp(org string, labels map[string]string) (string, bool) {
+	guid := labels[appGUIDLabel]
+	if !strings.HasPrefix(guid, org+".") {
+		return "", false
+	}
+
+	return strings.TrimPrefix(guid, org+"."), true
+}
+
+// status returns the ready and desired replicas of the application
+func (r *appResources) status(app string) string {
+	d, ok := r.deployments[app]
+	if !ok {
+		return "0/0"
+	}
+
+	return fmt.Sprintf("%d/%d", d.Status.ReadyReplicas, d.Status.Replicas)
+}
+
+// routes returns the routes of the application, without inference path
+func (r *appResources) routes(org, namespace, app string) (string, error) {
+	defaultRoute := "http://" + config.IngressHost(defaultAppRoute(r.istio, r.domain, org, namespace, app), r.ingressPort)
+
+	if r.knative {
+		s, ok := r.knServices[app]
+		if !ok {
+			// knative is deployed, but maybe not used for current app - show the default route instead
+			return defaultRoute, nil
+		}
+		// FIXME: KN services created by KFServing has -predictor-default appended into its URL, this code is hardcoded to replace it for now
+		// but needs a better approach for this
+		u := *s.Status.URL
+		u.Host = config.IngressHost(strings.ReplaceAll(u.Host, "-predictor-default.", "."), r.ingressPort)
+		u.Scheme = config.IngressScheme(u.Scheme, r.ingressPort)
+		return u.String(), nil
+	}
+
+	if r.istio {
+		return defaultRoute, nil
+	}
+
+	ing, ok := r.ingresses[app]
+	if !ok {
+		return "", errors.Errorf("failed to get routes for app '%s': no ingress", app)
+	}
+
+	hosts := []string{}
+	for _, rule := range ing.Spec.Rules {
+		hosts = append(hosts, config.IngressHost(rule.Host, r.ingressPort))
+	}
+
+	return config.IngressScheme("https", r.ingressPort) + "://" + strings.Join(hosts, ", "), nil
+}
+
+// inferenceURL returns the inference path of the application
+func (r *appResources) inferenceURL(org, app string) (string, error) {
+	d, ok := r.deployments[app]
+	if !ok {
+		return "", errors.New(fmt.Sprintf("No deployment of application %s.%s found", org, app))
+	}
+
+	return deploymentInferenceURL(org, app, &d), nil
+}
@@ -0,0 +1,221 @@
+package paas_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	. "github.com/fuseml/fuseml/cli/paas"
+	"github.com/fuseml/fuseml/cli/paas/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"knative.dev/pkg/apis"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// kubeStandIn is a minimal Kubernetes API, answering GET requests with the
+// objects at their path
+type kubeStandIn map[string]interface{}
+
+func (s kubeStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	object, ok := s[r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(metav1.Status{
+			TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+			Status:   metav1.StatusFailure,
+			Reason:   metav1.StatusReasonNotFound,
+			Code:     http.StatusNotFound,
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(object)
+}
+
+func appDeployment(org, app string, ready, replicas int32) appsv1.Deployment {
+	return appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   org + "-" + app,
+			Labels: map[string]string{"fuseml/app-guid": org + "." + app},
+		},
+		Status: appsv1.DeploymentStatus{ReadyReplicas: ready, Replicas: replicas},
+	}
+}
+
+func appIngress(name string, hosts ...string) networkingv1.Ingress {
+	ingress := networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	for _, host := range hosts {
+		ingress.Spec.Rules = append(ingress.Spec.Rules, networkingv1.IngressRule{Host: host})
+	}
+	return ingress
+}
+
+var _ = Describe("OrgApp", func() {
+	It("returns the application of the org", func() {
+		app, ok := OrgApp("workspace", map[string]string{"fuseml/app-guid": "workspace.wine"})
+		Expect(ok).To(BeTrue())
+		Expect(app).To(Equal("wine"))
+	})
+
+	It("ignores the applications of other orgs", func() {
+		_, ok := OrgApp("work", map[string]string{"fuseml/app-guid": "workspace.wine"})
+		Expect(ok).To(BeFalse())
+
+		_, ok = OrgApp("workspace", map[string]string{})
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("OrgAppResources", func() {
+	var (
+		api    kubeStandIn
+		server *httptest.Server
+		cfg    *config.Config
+		client *FusemlClient
+	)
+
+	BeforeEach(func() {
+		api = kubeStandIn{
+			"/apis/apps/v1/namespaces/fuseml-workloads/deployments": appsv1.DeploymentList{
+				TypeMeta: metav1.TypeMeta{Kind: "DeploymentList", APIVersion: "apps/v1"},
+				Items: []appsv1.Deployment{
+					appDeployment("workspace", "wine", 1, 2),
+					appDeployment("other", "wine", 3, 3),
+					appDeployment("workspace", "beer", 1, 1),
+				},
+			},
+			"/apis/networking.k8s.io/v1/namespaces/fuseml-workloads/ingresses": networkingv1.IngressList{
+				TypeMeta: metav1.TypeMeta{Kind: "IngressList", APIVersion: "networking.k8s.io/v1"},
+				Items: []networkingv1.Ingress{
+					appIngress("wine", "wine.example.com"),
+					appIngress("beer", "beer.example.com", "beer.example.org"),
+				},
+			},
+		}
+		server = httptest.NewServer(api)
+
+		restConfig := &restclient.Config{Host: server.URL}
+		clientset, err := k8s.NewForConfig(restConfig)
+		Expect(err).ToNot(HaveOccurred())
+
+		cfg = &config.Config{FusemlWorkloadsNamespace: "fuseml-workloads", GiteaProtocol: "http"}
+		client = NewTestClient(&kubernetes.Cluster{Kubectl: clientset, RestConfig: restConfig}, cfg)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("returns the status of the applications of the org", func() {
+		resources, err := client.OrgAppResources("workspace")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(resources.Status("wine")).To(Equal("1/2"))
+		Expect(resources.Status("beer")).To(Equal("1/1"))
+		Expect(resources.Status("missing")).To(Equal("0/0"))
+	})
+
+	It("returns the routes of the application ingresses", func() {
+		resources, err := client.OrgAppResources("workspace")
+		Expect(err).ToNot(HaveOccurred())
+
+		routes, err := resources.Routes("workspace", "fuseml-workloads", "wine")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(routes).To(Equal("https://wine.example.com"))
+
+		routes, err = resources.Routes("workspace", "fuseml-workloads", "beer")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(routes).To(Equal("https://beer.example.com, beer.example.org"))
+	})
+
+	It("fails for an application without ingress", func() {
+		resources, err := client.OrgAppResources("workspace")
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = resources.Routes("workspace", "fuseml-workloads", "missing")
+		Expect(err).To(MatchError(ContainSubstring("no ingress")))
+	})
+
+	It("appends the node port of the ingress to the routes, reached with plain HTTP", func() {
+		cfg.IngressPort = 31080
+		resources, err := client.OrgAppResources("workspace")
+		Expect(err).ToNot(HaveOccurred())
+
+		routes, err := resources.Routes("workspace", "fuseml-workloads", "beer")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(routes).To(Equal("http://beer.example.com:31080, beer.example.org:31080"))
+
+		routes, err = resources.Routes("workspace", "fuseml-workloads", "wine")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(routes).To(Equal("http://wine.example.com:31080"))
+	})
+
+	Context("with knative", func() {
+		BeforeEach(func() {
+			api["/api/v1/namespaces/knative-serving/services/controller"] = map[string]interface{}{
+				"kind": "Service", "apiVersion": "v1", "metadata": map[string]string{"name": "controller"},
+			}
+			api["/apis/extensions/v1beta1/namespaces/gitea/ingresses"] = v1beta1.IngressList{
+				TypeMeta: metav1.TypeMeta{Kind: "IngressList", APIVersion: "extensions/v1beta1"},
+				Items: []v1beta1.Ingress{{
+					Spec: v1beta1.IngressSpec{Rules: []v1beta1.IngressRule{{Host: "gitea.10.0.0.1.nip.io"}}},
+				}},
+			}
+			url, err := apis.ParseURL("http://workspace-wine-predictor-default.fuseml-workloads.10.0.0.1.nip.io")
+			Expect(err).ToNot(HaveOccurred())
+			service := servingv1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "workspace-wine",
+					Labels: map[string]string{"fuseml/app-guid": "workspace.wine"},
+				},
+			}
+			service.Status.URL = url
+			api["/apis/serving.knative.dev/v1/namespaces/fuseml-workloads/services"] = servingv1.ServiceList{
+				TypeMeta: metav1.TypeMeta{Kind: "ServiceList", APIVersion: "serving.knative.dev/v1"},
+				Items:    []servingv1.Service{service},
+			}
+		})
+
+		It("returns the URL of the knative service", func() {
+			resources, err := client.OrgAppResources("workspace")
+			Expect(err).ToNot(HaveOccurred())
+
+			routes, err := resources.Routes("workspace", "fuseml-workloads", "wine")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(routes).To(Equal("http://workspace-wine.fuseml-workloads.10.0.0.1.nip.io"))
+		})
+
+		It("returns the default route of the applications without knative service", func() {
+			resources, err := client.OrgAppResources("workspace")
+			Expect(err).ToNot(HaveOccurred())
+
+			routes, err := resources.Routes("workspace", "fuseml-workloads", "beer")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(routes).To(Equal("http://beer.10.0.0.1.nip.io"))
+		})
+
+		It("appends the node port of the ingress to the routes", func() {
+			cfg.IngressPort = 31080
+			resources, err := client.OrgAppResources("workspace")
+			Expect(err).ToNot(HaveOccurred())
+
+			routes, err := resources.Routes("workspace", "fuseml-workloads", "wine")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(routes).To(Equal("http://workspace-wine.fuseml-workloads.10.0.0.1.nip.io:31080"))
+
+			routes, err = resources.Routes("workspace", "fuseml-workloads", "beer")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(routes).To(Equal("http://beer.10.0.0.1.nip.io:31080"))
+		})
+	})
+})
@@ -0,0 +1,121 @@
+package paas
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/fuseml/fuseml/cli/deployments"
+	"github.com/fuseml/fuseml/cli/helpers"
+	"github.com/fuseml/fuseml/cli/paas/bundle"
+	"github.com/fuseml/fuseml/cli/paas/ui"
+	"github.com/pkg/errors"
+)
+
+// CreateBundle writes an installation bundle to output, holding every chart
+// and image fuseml installs. The images of the charts are found by
+// rendering them with their default values.
+func CreateBundle(ui *ui.UI, output string) error {
+	ui.Note().WithStringValue("Bundle", output).Msg("Creating installation bundle...")
+
+	dir, err := ioutil.TempDir("", "fuseml-bundle")
+	if err != nil {
+		return errors.Wrap(err, "can't create temp directory")
+	}
+	defer os.RemoveAll(dir)
+
+	b, err := bundle.New(dir)
+	if err != nil {
+		return err
+	}
+
+	images := []string{}
+	charts := []string{}
+
+	for _, url := range deployments.BundleCharts() {
+		ui.Normal().Msg("Downloading chart " + url)
+		if err := b.AddChart(url); err != nil {
+			return err
+		}
+		chart, _ := b.Chart(url)
+		charts = append(charts, chart)
+	}
+
+	for _, file := range deployments.EmbeddedCharts() {
+		chart, err := helpers.ExtractFile(file)
+		if err != nil {
+			return errors.New("Failed to extract embedded file: " + file + " - " + err.Error())
+		}
+		defer os.Remove(chart)
+		charts = append(charts, chart)
+	}
+
+	for _, chart := range charts {
+		out, err := deployments.TemplateChart(chart)
+		if err != nil {
+			return errors.Wrapf(err, "failed to render chart %s", chart)
+		}
+		images = append(images, bundle.ImageReferences(out)...)
+	}
+
+	for _, file := range deployments.EmbeddedManifests() {
+		manifest, err := helpers.ReadEmbeddedFile(file)
+		if err != nil {
+			return errors.New("Failed to extract embedded file: " + file + " - " + err.Error())
+		}
+		images = append(images, bundle.ImageReferences(manifest)...)
+	}
+
+	images = append(images, mlflowBaseImage)
+
+	seen := map[string]bool{}
+	for _, image := range images {
+		if seen[image] {
+			continue
+		}
+		seen[image] = true
+
+		ui.Normal().Msg("Pulling image " + image)
+		if err := b.AddImage(image); err != nil {
+			return err
+		}
+	}
+
+	ui.Normal().Msg("Writing bundle")
+	if err := b.Write(output); err != nil {
+		return err
+	}
+
+	ui.Success().
+		WithStringValue("Bundle", output).
+		WithIntValue("Charts", len(b.Charts)).
+		WithIntValue("Images", len(b.Images)).
+		Msg("Installation bundle created.")
+
+	return nil
+}
+
+// pushBundle opens the bundle at path and pushes its images to the mirror.
+// The returned bundle is unpacked, and must be closed by the caller.
+func pushBundle(ui *ui.UI, path, mirror string) (*bundle.Bundle, error) {
+	ui.Note().
+		WithStringValue("Bundle", path).
+		WithStringValue("Image registry", mirror).
+		Msg("Pushing bundled images...")
+
+	b, err := bundle.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.PushImages(mirror, func(ref, target string) {
+		ui.Normal().Msg("Pushing " + target)
+	})
+	if err != nil {
+		b.Close()
+		return nil, err
+	}
+
+	ui.Success().WithIntValue("Images", len(b.Images)).Msg("Bundled images pushed.")
+
+	return b, nil
+}
@@ -0,0 +1,301 @@
+// Package bundle implements air-gapped installation bundles: tarballs with
+// the charts and container images fuseml installs, which can be pushed to a
+// registry mirror inside a network without internet access
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/fuseml/fuseml/cli/helpers"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+)
+
+const (
+	indexFile = "bundle.json"
+	chartsDir = "charts"
+	imagesDir = "images"
+
+	// refAnnotation records the original reference of a bundled image
+	refAnnotation = "org.opencontainers.image.ref.name"
+)
+
+// Bundle is an unpacked installation bundle. Images are stored in an OCI
+// image layout, complete with all their platforms, so that references
+// pinned by digest stay valid once pushed to a mirror.
+type Bundle struct {
+	// Dir is the directory holding the bundle contents
+	Dir string `json:"-"`
+	// Charts maps the chart URLs to the chart files in the bundle
+	Charts map[string]string `json:"charts"`
+	// Images lists the references of the bundled images
+	Images []string `json:"images"`
+
+	extracted bool
+}
+
+// New creates an empty bundle in dir
+func New(dir string) (*Bundle, error) {
+	if err := os.MkdirAll(filepath.Join(dir, chartsDir), 0755); err != nil {
+		return nil, errors.Wrap(err, "failed to create bundle directory")
+	}
+	if _, err := layout.Write(filepath.Join(dir, imagesDir), empty.Index); err != nil {
+		return nil, errors.Wrap(err, "failed to create bundle image layout")
+	}
+
+	b := &Bundle{
+		Dir:    dir,
+		Charts: map[string]string{},
+		Images: []string{},
+	}
+
+	return b, b.save()
+}
+
+// Open returns the bundle stored in path, which is either a bundle tarball or
+// a directory with its contents. Tarballs are extracted to a temporary
+// directory, removed by Close.
+func Open(path string) (*Bundle, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open bundle %s", path)
+	}
+
+	b := &Bundle{Dir: path}
+
+	if !info.IsDir() {
+		b.Dir, err = ioutil.TempDir("", "fuseml-bundle")
+		if err != nil {
+			return nil, errors.Wrap(err, "can't create temp directory")
+		}
+		b.extracted = true
+
+		if err := helpers.Untar(path, b.Dir); err != nil {
+			b.Close()
+			return nil, errors.Wrapf(err, "failed to extract bundle %s", path)
+		}
+	}
+
+	contents, err := ioutil.ReadFile(filepath.Join(b.Dir, indexFile))
+	if err != nil {
+		b.Close()
+		return nil, errors.Wrapf(err, "%s is not a fuseml bundle", path)
+	}
+	if err := json.Unmarshal(contents, b); err != nil {
+		b.Close()
+		return nil, errors.Wrapf(err, "failed to read bundle index of %s", path)
+	}
+
+	return b, nil
+}
+
+// Close removes the contents extracted by Open
+func (b *Bundle) Close() error {
+	if !b.extracted {
+		return nil
+	}
+
+	return os.RemoveAll(b.Dir)
+}
+
+// AddChart downloads the chart at url into the bundle
+func (b *Bundle) AddChart(url string) error {
+	file := path.Join(chartsDir, path.Base(url))
+
+	err := helpers.DownloadFile(url, path.Base(url), filepath.Join(b.Dir, chartsDir))
+	if err != nil {
+		return errors.Wrapf(err, "failed to download chart %s", url)
+	}
+
+	b.Charts[url] = file
+
+	return b.save()
+}
+
+// Chart returns the path of the bundled copy of the chart at url
+func (b *Bundle) Chart(url string) (string, bool) {
+	file, ok := b.Charts[url]
+	if !ok {
+		return "", false
+	}
+
+	return filepath.Join(b.Dir, file), true
+}
+
+// AddImage pulls the image ref into the bundle, with the credentials of the
+// docker config
+func (b *Bundle) AddImage(ref string) error {
+	parsed, err := name.ParseReference(ref, name.WeakValidation)
+	if err != nil {
+		return errors.Wrapf(err, "invalid image reference '%s'", ref)
+	}
+
+	desc, err := remote.Get(parsed, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return errors.Wrapf(err, "failed to pull image %s", ref)
+	}
+
+	images, err := layout.FromPath(filepath.Join(b.Dir, imagesDir))
+	if err != nil {
+		return errors.Wrap(err, "failed to open bundle image layout")
+	}
+
+	annotations := layout.WithAnnotations(map[string]string{refAnnotation: ref})
+
+	if desc.MediaType.IsIndex() {
+		index, err := desc.ImageIndex()
+		if err != nil {
+			return errors.Wrapf(err, "failed to pull image %s", ref)
+		}
+		if err := images.AppendIndex(index, annotations); err != nil {
+			return errors.Wrapf(err, "failed to store image %s", ref)
+		}
+	} else {
+		image, err := desc.Image()
+		if err != nil {
+			return errors.Wrapf(err, "failed to pull image %s", ref)
+		}
+		if err := images.AppendImage(image, annotations); err != nil {
+			return errors.Wrapf(err, "failed to store image %s", ref)
+		}
+	}
+
+	b.Images = append(b.Images, ref)
+
+	return b.save()
+}
+
+// PushImages pushes all bundled images to the mirror registry, at the
+// locations given by MirrorReference, with the credentials of the docker
+// config. progress is called before each image.
+func (b *Bundle) PushImages(mirror string, progress func(ref, target string)) error {
+	if err := ValidateMirror(mirror); err != nil {
+		return err
+	}
+
+	index, err := layout.ImageIndexFromPath(filepath.Join(b.Dir, imagesDir))
+	if err != nil {
+		return errors.Wrap(err, "failed to open bundle image layout")
+	}
+
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return errors.Wrap(err, "failed to read bundle image layout")
+	}
+
+	auth := remote.WithAuthFromKeychain(authn.DefaultKeychain)
+
+	for _, desc := range manifest.Manifests {
+		ref := desc.Annotations[refAnnotation]
+
+		target, err := MirrorReference(ref, mirror)
+		if err != nil {
+			return err
+		}
+		targetRef, err := name.ParseReference(target, name.WeakValidation)
+		if err != nil {
+			return errors.Wrapf(err, "invalid image reference '%s'", target)
+		}
+
+		if progress != nil {
+			progress(ref, target)
+		}
+
+		if desc.MediaType.IsIndex() {
+			images, err := index.ImageIndex(desc.Digest)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read bundled image %s", ref)
+			}
+			if err := remote.WriteIndex(targetRef, images, auth); err != nil {
+				return errors.Wrapf(err, "failed to push image %s", target)
+			}
+		} else {
+			image, err := index.Image(desc.Digest)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read bundled image %s", ref)
+			}
+			if err := remote.Write(targetRef, image, auth); err != nil {
+				return errors.Wrapf(err, "failed to push image %s", target)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Write stores the bundle as a gzipped tarball
+func (b *Bundle) Write(output string) error {
+	out, err := os.Create(output)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s", output)
+	}
+	defer out.Close()
+
+	gzw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gzw)
+
+	err = filepath.Walk(b.Dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relative, err := filepath.Rel(b.Dir, file)
+		if err != nil || relative == "." {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(relative)
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		in, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+
+		_, err = io.Copy(tw, in)
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to write %s", output)
+	}
+
+	if err := tw.Close(); err != nil {
+		return errors.Wrapf(err, "failed to write %s", output)
+	}
+	if err := gzw.Close(); err != nil {
+		return errors.Wrapf(err, "failed to write %s", output)
+	}
+
+	return out.Close()
+}
+
+func (b *Bundle) save() error {
+	contents, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode bundle index")
+	}
+
+	return ioutil.WriteFile(filepath.Join(b.Dir, indexFile), contents, 0644)
+}
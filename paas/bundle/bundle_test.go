@@ -0,0 +1,121 @@
+package bundle_test
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/fuseml/fuseml/cli/paas/bundle"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bundle", func() {
+	var (
+		source, mirror *httptest.Server
+		tmpDir         string
+	)
+
+	host := func(server *httptest.Server) string {
+		return strings.TrimPrefix(server.URL, "http://")
+	}
+
+	digest := func(ref string) string {
+		parsed, err := name.ParseReference(ref)
+		Expect(err).ToNot(HaveOccurred())
+		desc, err := remote.Get(parsed)
+		Expect(err).ToNot(HaveOccurred())
+		return desc.Digest.String()
+	}
+
+	BeforeEach(func() {
+		quiet := registry.Logger(log.New(ioutil.Discard, "", 0))
+		source = httptest.NewServer(registry.New(quiet))
+		mirror = httptest.NewServer(registry.New(quiet))
+
+		var err error
+		tmpDir, err = ioutil.TempDir("", "fuseml-bundle-test")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		source.Close()
+		mirror.Close()
+		os.RemoveAll(tmpDir)
+	})
+
+	It("carries images and indexes to the mirror, keeping their digests", func() {
+		image, err := random.Image(1024, 2)
+		Expect(err).ToNot(HaveOccurred())
+		imageRef := host(source) + "/org/app:v1"
+		ref, err := name.ParseReference(imageRef)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remote.Write(ref, image)).To(Succeed())
+
+		index, err := random.Index(1024, 1, 2)
+		Expect(err).ToNot(HaveOccurred())
+		indexDigest, err := index.Digest()
+		Expect(err).ToNot(HaveOccurred())
+		indexRef := host(source) + "/multiarch@" + indexDigest.String()
+		ref, err = name.ParseReference(indexRef)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remote.WriteIndex(ref, index)).To(Succeed())
+
+		b, err := New(filepath.Join(tmpDir, "src"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b.AddImage(imageRef)).To(Succeed())
+		Expect(b.AddImage(indexRef)).To(Succeed())
+
+		output := filepath.Join(tmpDir, "bundle.tgz")
+		Expect(b.Write(output)).To(Succeed())
+
+		opened, err := Open(output)
+		Expect(err).ToNot(HaveOccurred())
+		defer opened.Close()
+		Expect(opened.Images).To(Equal([]string{imageRef, indexRef}))
+
+		pushed := []string{}
+		err = opened.PushImages(host(mirror)+"/fuseml", func(ref, target string) {
+			pushed = append(pushed, target)
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pushed).To(Equal([]string{
+			host(mirror) + "/fuseml/org/app:v1",
+			host(mirror) + "/fuseml/multiarch@" + indexDigest.String(),
+		}))
+
+		Expect(digest(pushed[0])).To(Equal(digest(imageRef)))
+		Expect(digest(pushed[1])).To(Equal(indexDigest.String()))
+
+		dir := opened.Dir
+		Expect(opened.Close()).To(Succeed())
+		Expect(dir).ToNot(BeADirectory())
+	})
+
+	It("opens unpacked bundles in place", func() {
+		dir := filepath.Join(tmpDir, "unpacked")
+		_, err := New(dir)
+		Expect(err).ToNot(HaveOccurred())
+
+		opened, err := Open(dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opened.Dir).To(Equal(dir))
+		Expect(opened.Close()).To(Succeed())
+		Expect(dir).To(BeADirectory())
+
+		_, found := opened.Chart("https://example.com/chart.tgz")
+		Expect(found).To(BeFalse())
+	})
+
+	It("rejects directories which are not bundles", func() {
+		_, err := Open(tmpDir)
+		Expect(err).To(MatchError(ContainSubstring("is not a fuseml bundle")))
+	})
+})
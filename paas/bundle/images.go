@@ -0,0 +1,141 @@
+package bundle
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+)
+
+var (
+	// imageField matches the `image:` fields of manifests and rendered charts
+	imageField = regexp.MustCompile(`^(\s*-?\s*image:\s*["']?)([^\s"'#]+)(.*)$`)
+
+	// hostedImage matches image references qualified with a registry host,
+	// wherever they appear, e.g. in the arguments of the tekton controller
+	hostedImage = regexp.MustCompile(`(^|[\s"'\[,=])((?:[a-z0-9-]+\.)+[a-z0-9-]+(?::[0-9]+)?/[a-z0-9._/-]+(?::\w[\w.-]*)?(?:@sha256:[a-f0-9]{64})?)`)
+)
+
+// ImageReferences returns the images referenced by a manifest, in order of
+// appearance and without duplicates
+func ImageReferences(manifest []byte) []string {
+	result := []string{}
+	seen := map[string]bool{}
+
+	mapImages(manifest, func(ref string) string {
+		if !seen[ref] {
+			seen[ref] = true
+			result = append(result, ref)
+		}
+		return ref
+	})
+
+	return result
+}
+
+// RewriteImages points all image references of the manifest to the mirror
+// registry, see MirrorReference
+func RewriteImages(manifest []byte, mirror string) ([]byte, error) {
+	if err := ValidateMirror(mirror); err != nil {
+		return nil, err
+	}
+
+	return mapImages(manifest, func(ref string) string {
+		mirrored, err := MirrorReference(ref, mirror)
+		if err != nil {
+			return ref
+		}
+		return mirrored
+	}), nil
+}
+
+// ValidateMirror checks that mirror is a registry host, optionally followed
+// by a repository path, e.g. `mirror.local:5000/fuseml`
+func ValidateMirror(mirror string) error {
+	if mirror == "" || strings.Contains(mirror, "://") {
+		return errors.Errorf("invalid image registry '%s', expected HOST[:PORT][/PATH]", mirror)
+	}
+	if _, err := name.NewRepository(strings.TrimSuffix(mirror, "/")+"/image", name.StrictValidation); err != nil {
+		return errors.Wrapf(err, "invalid image registry '%s'", mirror)
+	}
+
+	return nil
+}
+
+// MirrorReference returns the location of image ref in the mirror: the
+// repository path moves under the mirror, while the tag and digest are kept.
+// For example quay.io/org/app:v1 becomes mirror.local/fuseml/org/app:v1 and
+// alpine becomes mirror.local/fuseml/library/alpine.
+func MirrorReference(ref, mirror string) (string, error) {
+	parsed, err := name.ParseReference(ref, name.WeakValidation)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid image reference '%s'", ref)
+	}
+
+	// Keep the identifiers exactly as written, as a reference may carry
+	// both a tag and a digest
+	suffix := ""
+	base := ref
+	if i := strings.Index(base, "@"); i >= 0 {
+		suffix = base[i:]
+		base = base[:i]
+	}
+	if i := strings.LastIndex(base, ":"); i > strings.LastIndex(base, "/") {
+		suffix = base[i:] + suffix
+	}
+
+	result := strings.TrimSuffix(mirror, "/") + "/" + parsed.Context().RepositoryStr() + suffix
+	if _, err := name.ParseReference(result, name.WeakValidation); err != nil {
+		return "", errors.Wrapf(err, "invalid image reference '%s' for image registry '%s'", ref, mirror)
+	}
+
+	return result, nil
+}
+
+// mapImages replaces every image reference of the manifest with the result
+// of mapping. Comments are left alone.
+func mapImages(manifest []byte, mapping func(ref string) string) []byte {
+	lines := strings.Split(string(manifest), "\n")
+
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		if match := imageField.FindStringSubmatch(line); match != nil {
+			if isImageReference(match[2]) {
+				lines[i] = match[1] + mapping(match[2]) + match[3]
+			}
+			continue
+		}
+
+		var result strings.Builder
+		last := 0
+		for _, loc := range hostedImage.FindAllStringSubmatchIndex(line, -1) {
+			start, end := loc[4], loc[5]
+			ref := line[start:end]
+			if !isPinned(ref) || !isImageReference(ref) {
+				continue
+			}
+			result.WriteString(line[last:start])
+			result.WriteString(mapping(ref))
+			last = end
+		}
+		result.WriteString(line[last:])
+		lines[i] = result.String()
+	}
+
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// isPinned tells whether the reference carries a tag or a digest, which
+// distinguishes images from other host qualified names, like API groups
+func isPinned(ref string) bool {
+	return strings.Contains(ref, "@") || strings.Contains(ref[strings.LastIndex(ref, "/"):], ":")
+}
+
+func isImageReference(ref string) bool {
+	_, err := name.ParseReference(ref, name.WeakValidation)
+	return err == nil
+}
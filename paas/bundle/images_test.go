@@ -0,0 +1,96 @@
+package bundle_test
+
+import (
+	. "github.com/fuseml/fuseml/cli/paas/bundle"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const manifest = `
+# gcr.io/distroless/base:debug as of November 15, 2020
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app.kubernetes.io/part-of: tekton-pipelines
+spec:
+  template:
+    spec:
+      containers:
+      - name: controller
+        image: gcr.io/tekton-releases/controller:v0.22.0@sha256:e6e92621e4192768fd5f189b5c10724c652bbcc2565f225ebca1c73fb9f52f58
+        args: ["-git-image", "gcr.io/tekton-releases/git-init:v0.22.0", "-kubeconfig", "/etc/kube"]
+      - name: tools
+        image: "alpine"
+      - name: step
+        image: $(params.BUILDER_IMAGE)
+      initContainers:
+      - image: lachlanevenson/k8s-kubectl
+  params:
+  - name: BUILDER_IMAGE
+    value: "gcr.io/kaniko-project/executor:v1.5.1"
+`
+
+var _ = Describe("Images", func() {
+	Describe("ImageReferences", func() {
+		It("finds image fields and registry qualified references", func() {
+			Expect(ImageReferences([]byte(manifest))).To(Equal([]string{
+				"gcr.io/tekton-releases/controller:v0.22.0@sha256:e6e92621e4192768fd5f189b5c10724c652bbcc2565f225ebca1c73fb9f52f58",
+				"gcr.io/tekton-releases/git-init:v0.22.0",
+				"alpine",
+				"lachlanevenson/k8s-kubectl",
+				"gcr.io/kaniko-project/executor:v1.5.1",
+			}))
+		})
+
+		It("ignores duplicates", func() {
+			Expect(ImageReferences([]byte("image: alpine\n---\nimage: alpine\n"))).To(Equal([]string{"alpine"}))
+		})
+	})
+
+	Describe("MirrorReference", func() {
+		It("keeps the repository path, tag and digest", func() {
+			Expect(MirrorReference("quay.io/org/app:v1", "mirror.local/fuseml")).To(Equal("mirror.local/fuseml/org/app:v1"))
+			Expect(MirrorReference("gcr.io/a/b:v1@sha256:e6e92621e4192768fd5f189b5c10724c652bbcc2565f225ebca1c73fb9f52f58", "mirror.local:5000")).
+				To(Equal("mirror.local:5000/a/b:v1@sha256:e6e92621e4192768fd5f189b5c10724c652bbcc2565f225ebca1c73fb9f52f58"))
+		})
+
+		It("expands docker hub references", func() {
+			Expect(MirrorReference("alpine", "mirror.local/")).To(Equal("mirror.local/library/alpine"))
+			Expect(MirrorReference("minio/mc", "mirror.local")).To(Equal("mirror.local/minio/mc"))
+		})
+
+		It("rejects invalid references", func() {
+			_, err := MirrorReference("$(params.IMAGE)", "mirror.local")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("RewriteImages", func() {
+		It("points all references to the mirror, leaving the rest alone", func() {
+			result, err := RewriteImages([]byte(manifest), "mirror.local")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(string(result)).To(ContainSubstring("# gcr.io/distroless/base:debug as of"))
+			Expect(string(result)).To(ContainSubstring("app.kubernetes.io/part-of: tekton-pipelines"))
+			Expect(string(result)).To(ContainSubstring(`args: ["-git-image", "mirror.local/tekton-releases/git-init:v0.22.0", "-kubeconfig", "/etc/kube"]`))
+			Expect(string(result)).To(ContainSubstring(`image: "mirror.local/library/alpine"`))
+			Expect(string(result)).To(ContainSubstring("image: $(params.BUILDER_IMAGE)"))
+			Expect(string(result)).To(ContainSubstring("- image: mirror.local/lachlanevenson/k8s-kubectl\n"))
+			Expect(ImageReferences(result)).To(Equal([]string{
+				"mirror.local/tekton-releases/controller:v0.22.0@sha256:e6e92621e4192768fd5f189b5c10724c652bbcc2565f225ebca1c73fb9f52f58",
+				"mirror.local/tekton-releases/git-init:v0.22.0",
+				"mirror.local/library/alpine",
+				"mirror.local/lachlanevenson/k8s-kubectl",
+				"mirror.local/kaniko-project/executor:v1.5.1",
+			}))
+		})
+
+		It("rejects invalid mirrors", func() {
+			_, err := RewriteImages([]byte(manifest), "https://mirror.local")
+			Expect(err).To(HaveOccurred())
+			_, err = RewriteImages([]byte(manifest), "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
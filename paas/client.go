@@ -5,45 +5,54 @@ import (
 	"fmt"
 	"html/template"
 	"io/ioutil"
-	"net/url"
 	"os"
-	"os/exec"
-	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
-	"code.gitea.io/sdk/gitea"
 	"github.com/fuseml/fuseml/cli/deployments"
 	"github.com/fuseml/fuseml/cli/helpers"
 	"github.com/fuseml/fuseml/cli/kubernetes"
 	"github.com/fuseml/fuseml/cli/kubernetes/tailer"
+	"github.com/fuseml/fuseml/cli/paas/bundle"
 	"github.com/fuseml/fuseml/cli/paas/config"
+	"github.com/fuseml/fuseml/cli/paas/git"
 	paasgitea "github.com/fuseml/fuseml/cli/paas/gitea"
+	"github.com/fuseml/fuseml/cli/paas/githost"
 	"github.com/fuseml/fuseml/cli/paas/ui"
 	"github.com/go-logr/logr"
 	"github.com/otiai10/copy"
 	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	knversionedclient "knative.dev/serving/pkg/client/clientset/versioned"
 )
 
 var (
-	// HookSecret should be generated
-	// TODO: generate this and put it in a secret
-	HookSecret = "74tZTBHkhjMT5Klj6Ik6PqmM"
-
-	// StagingEventListenerURL should not exist
+	// StagingEventListenerURL is the in-cluster URL of the pipeline
+	// trigger, called by the webhooks unless webhook_url is configured
 	// TODO: detect this based on namespaces and services
 	StagingEventListenerURL = "http://el-mlflow-listener.fuseml-workloads:8080"
 )
 
+// mlflowBaseImage is the image application images are built on
+const mlflowBaseImage = "ghcr.io/fuseml/mlflow:1.14.1"
+
+const (
+	// pipelineServiceAccount is the service account the pipelines clone
+	// the application repositories with
+	pipelineServiceAccount = "staging-triggers-admin"
+	// gitlabCredsSecret holds the GitLab credentials of the pipelines
+	gitlabCredsSecret = "gitlab-creds"
+)
+
 // FusemlClient provides functionality for talking to a
 // Fuseml installation on Kubernetes
 type FusemlClient struct {
-	giteaClient   *gitea.Client
+	gitHost       githost.Host
 	kubeClient    *kubernetes.Cluster
 	ui            *ui.UI
 	config        *config.Config
@@ -63,17 +72,17 @@ func (c *FusemlClient) Info() error {
 		return errors.Wrap(err, "failed to get kube version")
 	}
 
-	giteaVersion := "unavailable"
+	gitVersion := "unavailable"
 
-	version, resp, err := c.giteaClient.ServerVersion()
-	if err == nil && resp != nil && resp.StatusCode == 200 {
-		giteaVersion = version
+	version, err := c.gitHost.Version()
+	if err == nil {
+		gitVersion = version
 	}
 
 	c.ui.Success().
 		WithStringValue("Platform", platform.String()).
 		WithStringValue("Kubernetes Version", kubeVersion).
-		WithStringValue("Gitea Version", giteaVersion).
+		WithStringValue(c.gitHost.Name()+" Version", gitVersion).
 		Msg("Fuseml Environment")
 
 	return nil
@@ -89,17 +98,17 @@ func (c *FusemlClient) AppsMatching(prefix string) []string {
 
 	result := []string{}
 
-	apps, _, err := c.giteaClient.ListOrgRepos(c.config.Org, gitea.ListOrgReposOptions{})
+	apps, err := c.gitHost.Repos(c.config.Org)
 	if err != nil {
 		return result
 	}
 
 	for _, app := range apps {
-		details.Info("Found", "Name", app.Name)
+		details.Info("Found", "Name", app)
 
-		if strings.HasPrefix(app.Name, prefix) {
-			details.Info("Matched", "Name", app.Name)
-			result = append(result, app.Name)
+		if strings.HasPrefix(app, prefix) {
+			details.Info("Matched", "Name", app)
+			result = append(result, app)
 		}
 	}
 
@@ -113,7 +122,7 @@ func (c *FusemlClient) Apps() error {
 	defer log.Info("return")
 	details := log.V(1) // NOTE: Increment of level, not absolute.
 
-	c.ui.Note().
+	c.ui.Header().
 		WithStringValue("Organization", c.config.Org).
 		Msg("Listing applications")
 
@@ -123,74 +132,33 @@ func (c *FusemlClient) Apps() error {
 		return err
 	}
 
-	details.Info("gitea list org repos")
-	apps, _, err := c.giteaClient.ListOrgRepos(c.config.Org, gitea.ListOrgReposOptions{})
+	details.Info("list org repos")
+	apps, err := c.gitHost.Repos(c.config.Org)
 	if err != nil {
 		return errors.Wrap(err, "failed to list apps")
 	}
 
+	details.Info("kube list app resources")
+	resources, err := c.orgAppResources(c.config.Org)
+	if err != nil {
+		return err
+	}
+
 	msg := c.ui.Success().WithTable("Name", "Status", "Routes")
 
 	for _, app := range apps {
-		details.Info("kube get status", "App", app.Name)
-		status, err := c.kubeClient.DeploymentStatus(
-			c.config.FusemlWorkloadsNamespace,
-			fmt.Sprintf("fuseml/app-guid=%s.%s", c.config.Org, app.Name),
-		)
+		routes, err := resources.routes(c.config.Org, c.config.FusemlWorkloadsNamespace, app)
 		if err != nil {
-			return errors.Wrapf(err, "failed to get status for app '%s'", app.Name)
-		}
-
-		var routes string
-		if c.kubeClient.HasKnative() {
-			details.Info("kube get knative services", "App", app.Name)
-
-			knc, err := knversionedclient.NewForConfig(c.kubeClient.RestConfig)
-			if err != nil {
-				return errors.Wrap(err, "failed to create knative client.")
-			}
-
-			knService, err := knc.ServingV1().Services(c.config.FusemlWorkloadsNamespace).
-				List(context.TODO(), metav1.ListOptions{LabelSelector: fmt.Sprintf("fuseml/app-guid=%s.%s", c.config.Org, app.Name)})
-			if err != nil {
-				return errors.Wrap(err, "failed to get knative service")
-			}
-			if len(knService.Items) < 1 {
-				// knative is deployed, but maybe not used for current app - show the default route instead
-				defaultRoute, err := c.appDefaultRoute(app.Name)
-				if err != nil {
-					return errors.Wrapf(err, "failed to get for app '%s'", app.Name)
-				}
-				routes = "http://" + defaultRoute
-			} else {
-				// FIXME: KN services created by KFServing has -predictor-default appended into its URL, this code is hardcoded to replace it for now
-				// but needs a better approach for this
-				routes = strings.ReplaceAll(knService.Items[0].Status.URL.String(), "-predictor-default.", ".")
-			}
-		} else if c.kubeClient.HasIstio() {
-			defaultRoute, err := c.appDefaultRoute(app.Name)
-			if err != nil {
-				return errors.Wrapf(err, "failed to get routes for app '%s'", app.Name)
-			}
-			routes = "http://" + defaultRoute
-		} else {
-			details.Info("kube get ingress", "App", app.Name)
-			ingRoutes, err := c.kubeClient.ListIngressRoutes(
-				c.config.FusemlWorkloadsNamespace,
-				app.Name)
-			if err != nil {
-				return errors.Wrapf(err, "failed to get routes for app '%s'", app.Name)
-			}
-			routes = "https://" + strings.Join(ingRoutes, ", ")
+			return err
 		}
 
-		inferenceUrl, err := c.getAppInferenceUrl(app.Name)
+		inferenceUrl, err := resources.inferenceURL(c.config.Org, app)
 		if err != nil {
 			return err
 		}
 		routes = fmt.Sprintf("%s/%s", routes, inferenceUrl)
 
-		msg = msg.WithTableRow(app.Name, status, routes)
+		msg = msg.WithTableRow(app, resources.status(app), routes)
 	}
 
 	msg.Msg("Fuseml Applications:")
@@ -198,34 +166,31 @@ func (c *FusemlClient) Apps() error {
 	return nil
 }
 
-// CreateOrg creates an Org in gitea
+// CreateOrg creates an Org on the git host
 func (c *FusemlClient) CreateOrg(org string) error {
 	log := c.Log.WithName("CreateOrg").WithValues("Organization", org)
 	log.Info("start")
 	defer log.Info("return")
 	details := log.V(1) // NOTE: Increment of level, not absolute.
 
-	c.ui.Note().
+	c.ui.Header().
 		WithStringValue("Name", org).
 		Msg("Creating organization...")
 
 	details.Info("validate")
-	details.Info("gitea get-org")
-	_, resp, err := c.giteaClient.GetOrg(org)
-	if resp == nil && err != nil {
-		return errors.Wrap(err, "failed to make get org request")
+	details.Info("get-org")
+	exists, err := c.gitHost.OrgExists(org)
+	if err != nil {
+		return err
 	}
 
-	if resp.StatusCode == 200 {
+	if exists {
 		c.ui.Exclamation().Msg("Organization already exists.")
 		return nil
 	}
 
-	details.Info("gitea create-org")
-	_, _, err = c.giteaClient.CreateOrg(gitea.CreateOrgOption{
-		Name: org,
-	})
-
+	details.Info("create-org")
+	err = c.gitHost.CreateOrg(org)
 	if err != nil {
 		return errors.Wrap(err, "failed to create org")
 	}
@@ -242,7 +207,7 @@ func (c *FusemlClient) Delete(app string) error {
 	defer log.Info("return")
 	details := log.V(1) // NOTE: Increment of level, not absolute.
 
-	c.ui.Note().
+	c.ui.Header().
 		WithStringValue("Name", app).
 		Msg("Deleting application...")
 
@@ -253,14 +218,18 @@ func (c *FusemlClient) Delete(app string) error {
 	defer os.RemoveAll(appDir)
 
 	details.Info("deleting app workload")
-	out, err := helpers.Kubectl(fmt.Sprintf("delete -n %s --filename %s/.fuseml/serve.yaml", c.config.FusemlWorkloadsNamespace, appDir))
+	manifest, err := ioutil.ReadFile(filepath.Join(appDir, ".fuseml", "serve.yaml"))
 	if err != nil {
-		return errors.Wrap(err, `failed to delete application deployment`+out)
+		return errors.Wrap(err, "failed to read application deployment")
+	}
+	err = c.kubeClient.DeleteManifest(manifest, c.config.FusemlWorkloadsNamespace, false)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete application deployment")
 	}
 	c.ui.Normal().Msg("Deleted app workload.")
 
 	details.Info("delete repo")
-	_, err = c.giteaClient.DeleteRepo(c.config.Org, app)
+	err = c.gitHost.DeleteRepo(c.config.Org, app)
 	if err != nil {
 		return errors.Wrap(err, "failed to delete repo")
 	}
@@ -281,34 +250,34 @@ func (c *FusemlClient) OrgsMatching(prefix string) []string {
 
 	result := []string{}
 
-	orgs, _, err := c.giteaClient.AdminListOrgs(gitea.AdminListOrgsOptions{})
+	orgs, err := c.gitHost.Orgs()
 	if err != nil {
 		return result
 	}
 
 	for _, org := range orgs {
-		details.Info("Found", "Name", org.UserName)
+		details.Info("Found", "Name", org)
 
-		if strings.HasPrefix(org.UserName, prefix) {
-			details.Info("Matched", "Name", org.UserName)
-			result = append(result, org.UserName)
+		if strings.HasPrefix(org, prefix) {
+			details.Info("Matched", "Name", org)
+			result = append(result, org)
 		}
 	}
 
 	return result
 }
 
-// Orgs get a list of all orgs in gitea
+// Orgs get a list of all orgs on the git host
 func (c *FusemlClient) Orgs() error {
 	log := c.Log.WithName("Orgs")
 	log.Info("start")
 	defer log.Info("return")
 	details := log.V(1) // NOTE: Increment of level, not absolute.
 
-	c.ui.Note().Msg("Listing organizations")
+	c.ui.Header().Msg("Listing organizations")
 
-	details.Info("gitea admin list orgs")
-	orgs, _, err := c.giteaClient.AdminListOrgs(gitea.AdminListOrgsOptions{})
+	details.Info("list orgs")
+	orgs, err := c.gitHost.Orgs()
 	if err != nil {
 		return errors.Wrap(err, "failed to list orgs")
 	}
@@ -316,7 +285,7 @@ func (c *FusemlClient) Orgs() error {
 	msg := c.ui.Success().WithTable("Name")
 
 	for _, org := range orgs {
-		msg = msg.WithTableRow(org.UserName)
+		msg = msg.WithTableRow(org)
 	}
 
 	msg.Msg("Fuseml Organizations:")
@@ -335,7 +304,7 @@ func (c *FusemlClient) Push(app string, path string, serve string) error {
 	defer log.Info("return")
 	details := log.V(1) // NOTE: Increment of level, not absolute.
 
-	c.ui.Note().
+	c.ui.Header().
 		WithStringValue("Name", app).
 		WithStringValue("Sources", path).
 		WithStringValue("Organization", c.config.Org).
@@ -363,6 +332,12 @@ func (c *FusemlClient) Push(app string, path string, serve string) error {
 		return errors.Wrap(err, "webhook configuration failed")
 	}
 
+	details.Info("ensure pipeline git credentials")
+	err = c.ensurePipelineGitCredentials()
+	if err != nil {
+		return errors.Wrap(err, "pipeline git credentials configuration failed")
+	}
+
 	details.Info("prepare code")
 	tmpDir, err := c.prepareCode(app, c.config.Org, path, serve)
 	if err != nil {
@@ -371,6 +346,13 @@ func (c *FusemlClient) Push(app string, path string, serve string) error {
 
 	details.Info("git push")
 	err = c.gitPush(app, tmpDir)
+	if err == git.ErrNoChanges {
+		// No pipeline is triggered, the application stays as it is
+		c.ui.Note().
+			WithStringValue("Name", app).
+			Msg("No changes to push, the application is not rebuilt.")
+		return nil
+	}
 	if err != nil {
 		return errors.Wrap(err, "failed to git push code")
 	}
@@ -388,6 +370,9 @@ func (c *FusemlClient) Push(app string, path string, serve string) error {
 		return errors.Wrap(err, "waiting for app failed")
 	}
 
+	details.Info("collect old pipeline runs")
+	c.gcAfterPush(app)
+
 	details.Info("get app default route")
 	route, err := c.appDefaultRoute(app)
 	if err != nil {
@@ -408,13 +393,13 @@ func (c *FusemlClient) Push(app string, path string, serve string) error {
 	c.ui.Success().
 		WithStringValue("Name", app).
 		WithStringValue("Organization", c.config.Org).
-		WithStringValue("Route", fmt.Sprintf("%s://%s/%s", protocol, route, inferenceUrl)).
+		WithStringValue("Route", fmt.Sprintf("%s://%s/%s", config.IngressScheme(protocol, c.config.IngressPort), config.IngressHost(route, c.config.IngressPort), inferenceUrl)).
 		Msg("App is online.")
 
 	return nil
 }
 
-// Target targets an org in gitea
+// Target targets an org on the git host
 func (c *FusemlClient) Target(org string) error {
 	log := c.Log.WithName("Target").WithValues("Organization", org)
 	log.Info("start")
@@ -429,7 +414,7 @@ func (c *FusemlClient) Target(org string) error {
 		return nil
 	}
 
-	c.ui.Note().
+	c.ui.Header().
 		WithStringValue("Name", org).
 		Msg("Targeting organization...")
 
@@ -451,28 +436,18 @@ func (c *FusemlClient) Target(org string) error {
 	return nil
 }
 
-func (c *FusemlClient) check() {
-	c.giteaClient.GetMyUserInfo()
-}
-
 func (c *FusemlClient) createRepo(name string) error {
-	_, resp, err := c.giteaClient.GetRepo(c.config.Org, name)
-	if resp == nil && err != nil {
-		return errors.Wrap(err, "failed to make get repo request")
+	exists, err := c.gitHost.RepoExists(c.config.Org, name)
+	if err != nil {
+		return err
 	}
 
-	if resp.StatusCode == 200 {
+	if exists {
 		c.ui.Note().Msg("Application already exists. Updating.")
 		return nil
 	}
 
-	_, _, err = c.giteaClient.CreateOrgRepo(c.config.Org, gitea.CreateRepoOption{
-		Name:          name,
-		AutoInit:      true,
-		Private:       true,
-		DefaultBranch: "main",
-	})
-
+	err = c.gitHost.CreateRepo(c.config.Org, name, git.DefaultBranch)
 	if err != nil {
 		return errors.Wrap(err, "failed to create application")
 	}
@@ -483,34 +458,88 @@ func (c *FusemlClient) createRepo(name string) error {
 }
 
 func (c *FusemlClient) createRepoWebhook(name string) error {
-	hooks, _, err := c.giteaClient.ListRepoHooks(c.config.Org, name, gitea.ListHooksOptions{})
+	exists, err := c.gitHost.HasWebhook(c.config.Org, name, c.webhookURL())
 	if err != nil {
 		return errors.Wrap(err, "failed to list webhooks")
 	}
 
-	for _, hook := range hooks {
-		url := hook.Config["url"]
-		if url == StagingEventListenerURL {
-			c.ui.Normal().Msg("Webhook already exists.")
-			return nil
-		}
+	if exists {
+		c.ui.Normal().Msg("Webhook already exists.")
+		return nil
 	}
 
 	c.ui.Normal().Msg("Creating webhook in the repo...")
 
-	c.giteaClient.CreateRepoHook(c.config.Org, name, gitea.CreateHookOption{
-		Active:       true,
-		BranchFilter: "*",
-		Config: map[string]string{
-			"secret":       HookSecret,
-			"http_method":  "POST",
-			"url":          StagingEventListenerURL,
-			"content_type": "json",
+	secret, err := deployments.EnsureWebhookSecret(c.kubeClient)
+	if err != nil {
+		return err
+	}
+
+	return c.gitHost.CreateWebhook(c.config.Org, name, c.webhookURL(), secret)
+}
+
+// webhookURL returns the URL the webhooks of the applications call to
+// trigger their pipeline
+func (c *FusemlClient) webhookURL() string {
+	if c.config.WebhookURL != "" {
+		return c.config.WebhookURL
+	}
+	return StagingEventListenerURL
+}
+
+// ensurePipelineGitCredentials lets the pipelines clone from the git host.
+// The credentials of the installed Gitea are set up by its deployment; those
+// of GitLab are written into a secret of the pipeline service account.
+func (c *FusemlClient) ensurePipelineGitCredentials() error {
+	if c.config.GitProvider != githost.GitLab {
+		return nil
+	}
+
+	username, password, err := c.gitHost.Credentials()
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      gitlabCredsSecret,
+			Namespace: c.config.FusemlWorkloadsNamespace,
+			Annotations: map[string]string{
+				"tekton.dev/git-0": c.config.GitlabURL,
+			},
 		},
-		Type: "gitea",
-	})
+		StringData: map[string]string{
+			"username": username,
+			"password": password,
+		},
+		Type: corev1.SecretTypeBasicAuth,
+	}
 
-	return nil
+	secrets := c.kubeClient.Kubectl.CoreV1().Secrets(secret.Namespace)
+	_, err = secrets.Create(context.Background(), secret, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		_, err = secrets.Update(context.Background(), secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to write the gitlab credentials")
+	}
+
+	accounts := c.kubeClient.Kubectl.CoreV1().ServiceAccounts(secret.Namespace)
+	account, err := accounts.Get(context.Background(), pipelineServiceAccount, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to get the pipeline service account")
+	}
+
+	for _, ref := range account.Secrets {
+		if ref.Name == gitlabCredsSecret {
+			return nil
+		}
+	}
+
+	account.Secrets = append(account.Secrets, corev1.ObjectReference{Name: gitlabCredsSecret})
+	_, err = accounts.Update(context.Background(), account, metav1.UpdateOptions{})
+
+	return errors.Wrap(err, "failed to update the pipeline service account")
 }
 
 func (c *FusemlClient) appDefaultRoute(name string) (string, error) {
@@ -518,13 +547,18 @@ func (c *FusemlClient) appDefaultRoute(name string) (string, error) {
 	if err != nil {
 		return "", errors.Wrap(err, "failed to determine fuseml domain")
 	}
-	route := fmt.Sprintf("%s.%s", name, domain)
 
-	if c.kubeClient.HasIstio() {
-		route = fmt.Sprintf("%s-%s.%s.%s", c.config.Org, name, c.config.FusemlWorkloadsNamespace, domain)
+	return defaultAppRoute(c.kubeClient.HasIstio(), domain, c.config.Org, c.config.FusemlWorkloadsNamespace, name), nil
+}
+
+// defaultAppRoute returns the host an application is served at, when its
+// serving does not define its own
+func defaultAppRoute(istio bool, domain, org, namespace, name string) string {
+	if istio {
+		return fmt.Sprintf("%s-%s.%s.%s", org, name, namespace, domain)
 	}
 
-	return route, nil
+	return fmt.Sprintf("%s.%s", name, domain)
 }
 
 func (c *FusemlClient) prepareCode(name, org, appDir string, serve string) (string, error) {
@@ -545,8 +579,16 @@ func (c *FusemlClient) prepareCode(name, org, appDir string, serve string) (stri
 		return "", errors.Wrap(err, "failed to setup kube resources directory in temp app location")
 	}
 
+	baseImage := mlflowBaseImage
+	if c.config.ImageRegistry != "" {
+		baseImage, err = bundle.MirrorReference(mlflowBaseImage, c.config.ImageRegistry)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	dockerfileDef := `
-FROM ghcr.io/fuseml/mlflow:1.14.1
+FROM ` + baseImage + `
 
 COPY conda.yaml /env/
 RUN env=$(awk '/name:/ {print $2}' /env/conda.yaml) && \
@@ -596,11 +638,13 @@ RUN conda env create -f /env/conda.yaml
 		Route              string
 		Org                string
 		ServiceAccountName string
+		MLflowConfig       string
 	}{
 		AppName:            name,
 		Route:              route,
 		Org:                c.config.Org,
 		ServiceAccountName: deployments.WorkloadsDeploymentID,
+		MLflowConfig:       deployments.MLflowConfigSecret,
 	})
 	if err != nil {
 		return "", errors.Wrap(err, "failed to render kube resource definition")
@@ -614,52 +658,32 @@ func (c *FusemlClient) gitPush(name, tmpDir string) error {
 
 	defer os.RemoveAll(tmpDir)
 
-	giteaURL, err := c.giteaResolver.GetGiteaURL()
+	remote, err := c.appRepoURL(name)
 	if err != nil {
-		return errors.Wrap(err, "failed to resolve gitea host")
-	}
-
-	u, err := url.Parse(giteaURL)
-	if err != nil {
-		return errors.Wrap(err, "failed to parse gitea url")
+		return err
 	}
 
-	username, password, err := c.giteaResolver.GetGiteaCredentials()
-	if err != nil {
-		return errors.Wrap(err, "failed to resolve gitea credentials")
+	err = git.Push(tmpDir, remote, git.DefaultBranch,
+		fmt.Sprintf("pushed at %s", time.Now().Format("20060102150405")),
+		c.gitHost.Credentials)
+	if err == git.ErrNoChanges {
+		return err
 	}
-
-	u.User = url.UserPassword(username, password)
-	u.Path = path.Join(u.Path, c.config.Org, name)
-
-	cmd := exec.Command("/bin/sh", "-c", fmt.Sprintf(`
-cd "%s" 
-git init
-git config user.name "Fuseml"
-git config user.email ci@fuseml
-git remote add fuseml "%s"
-git fetch --all
-git reset --soft fuseml/main
-git add --all
-git commit --no-gpg-sign -m "pushed at %s"
-git push fuseml master:main
-`, tmpDir, u.String(), time.Now().Format("20060102150405")))
-
-	output, err := cmd.CombinedOutput()
 	if err != nil {
-		c.ui.Problem().
-			WithStringValue("Stdout", string(output)).
-			WithStringValue("Stderr", "").
-			Msg("App push failed")
-		return errors.Wrap(err, "push script failed")
+		c.ui.Problem().Msg("App push failed")
+		return err
 	}
 
-	c.ui.Note().V(1).WithStringValue("Output", string(output)).Msg("")
 	c.ui.Success().Msg("Application push successful")
 
 	return nil
 }
 
+// appRepoURL returns the url of the app's repository, without credentials
+func (c *FusemlClient) appRepoURL(name string) (string, error) {
+	return c.gitHost.RepoURL(c.config.Org, name)
+}
+
 func (c *FusemlClient) logs(name string) (context.CancelFunc, error) {
 	c.ui.ProgressNote().V(1).Msg("Tailing application logs ...")
 
@@ -734,12 +758,12 @@ func (c *FusemlClient) waitForApp(org, name string) error {
 }
 
 func (c *FusemlClient) ensureGoodOrg(org, msg string) error {
-	_, resp, err := c.giteaClient.GetOrg(org)
-	if resp == nil && err != nil {
-		return errors.Wrap(err, "failed to make get org request")
+	exists, err := c.gitHost.OrgExists(org)
+	if err != nil {
+		return err
 	}
 
-	if resp.StatusCode == 404 {
+	if !exists {
 		errmsg := "Organization does not exist."
 		if msg != "" {
 			errmsg += " " + msg
@@ -768,39 +792,17 @@ func (c *FusemlClient) gitCloneApp(name string) (string, error) {
 		return "", errors.Wrap(err, "can't create temp directory")
 	}
 
-	giteaURL, err := c.giteaResolver.GetGiteaURL()
-	if err != nil {
-		return "", errors.Wrap(err, "failed to resolve gitea host")
-	}
-
-	u, err := url.Parse(giteaURL)
+	remote, err := c.appRepoURL(name)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to parse gitea url")
+		return "", err
 	}
 
-	username, password, err := c.giteaResolver.GetGiteaCredentials()
+	err = git.Clone(tmpDir, remote, git.DefaultBranch, c.gitHost.Credentials)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to resolve gitea credentials")
+		c.ui.Problem().Msg("App clone failed")
+		return "", err
 	}
 
-	u.User = url.UserPassword(username, password)
-	u.Path = path.Join(u.Path, c.config.Org, name)
-
-	cmd := exec.Command("/bin/sh", "-c", fmt.Sprintf(`
-cd "%s"
-git clone --depth 1 "%s" .
-`, tmpDir, u.String()))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		c.ui.Problem().
-			WithStringValue("Stdout", string(output)).
-			WithStringValue("Stderr", "").
-			Msg("App push failed")
-		return "", errors.Wrap(err, "push script failed")
-	}
-
-	c.ui.Note().V(1).WithStringValue("Output", string(output)).Msg("")
 	c.ui.Success().Msg("Application clone successful")
 	return tmpDir, nil
 }
@@ -815,12 +817,18 @@ func (c *FusemlClient) getAppInferenceUrl(appName string) (string, error) {
 		return "", errors.New(fmt.Sprintf("No deployment of application %s.%s found", c.config.Org, appName))
 	}
 
+	return deploymentInferenceURL(c.config.Org, appName, &appDeployment.Items[0]), nil
+}
+
+// deploymentInferenceURL returns the inference path of an application, from
+// the labels of its deployment
+func deploymentInferenceURL(org, appName string, deployment *appsv1.Deployment) string {
 	// Labels have the limitations of 63 characters, to overcome that use '-NAME-' on the label to represent the deployment name
 	// that is also used on the URL for some inference services.
-	inferUrl := strings.ReplaceAll(appDeployment.Items[0].Labels["fuseml/infer-url"], "-NAME-", fmt.Sprintf("%s-%s", c.config.Org, appName))
+	inferUrl := strings.ReplaceAll(deployment.Labels["fuseml/infer-url"], "-NAME-", fmt.Sprintf("%s-%s", org, appName))
 
 	// Labels does not allow '/' characters, in that way we are replacing '/' with '_' on the template, so
 	// we need to replace '_' back to '/' here. This is not a solution, but a temporary workaround that will break
 	// as soon as a url has '_' on it.
-	return strings.ReplaceAll(inferUrl, "_", "/"), nil
+	return strings.ReplaceAll(inferUrl, "_", "/")
 }
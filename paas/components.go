@@ -0,0 +1,88 @@
+package paas
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/fuseml/fuseml/cli/deployments"
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	// componentsConfigMap records the components installed by fuseml
+	// install, so that those skipped are not checked by fuseml status
+	componentsConfigMap = "fuseml-components"
+	componentsKey       = "installed"
+)
+
+// installedComponents returns the IDs of the recorded components, and
+// whether they were recorded at all, which older installations did not
+func installedComponents(configMaps typedcorev1.ConfigMapInterface) ([]string, bool, error) {
+	cm, err := configMaps.Get(context.Background(), componentsConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to read the installed components")
+	}
+
+	return strings.Fields(cm.Data[componentsKey]), true, nil
+}
+
+// recordComponents adds the installed components to the record, and removes
+// the uninstalled ones from it
+func recordComponents(configMaps typedcorev1.ConfigMapInterface, installed, uninstalled kubernetes.Deployments) error {
+	ids, ok, err := installedComponents(configMaps)
+	if err != nil {
+		return err
+	}
+	if !ok && len(installed) == 0 {
+		return nil
+	}
+
+	recorded := map[string]bool{}
+	for _, id := range ids {
+		recorded[id] = true
+	}
+	for _, id := range installed.IDs() {
+		recorded[id] = true
+	}
+	for _, id := range uninstalled.IDs() {
+		delete(recorded, id)
+	}
+
+	ids = []string{}
+	for id := range recorded {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      componentsConfigMap,
+			Namespace: deployments.WorkloadsDeploymentID,
+		},
+		Data: map[string]string{
+			componentsKey: strings.Join(ids, "\n"),
+		},
+	}
+
+	_, err = configMaps.Create(context.Background(), cm, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		_, err = configMaps.Update(context.Background(), cm, metav1.UpdateOptions{})
+	}
+
+	return errors.Wrap(err, "failed to record the installed components")
+}
+
+// componentsRecord returns the config maps of the namespace holding the
+// record of the installed components
+func (c *InstallClient) componentsRecord() typedcorev1.ConfigMapInterface {
+	return c.kubeClient.Kubectl.CoreV1().ConfigMaps(deployments.WorkloadsDeploymentID)
+}
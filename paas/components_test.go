@@ -0,0 +1,96 @@
+package paas_test
+
+import (
+	"net/http/httptest"
+
+	"github.com/fuseml/fuseml/cli/deployments"
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	. "github.com/fuseml/fuseml/cli/paas"
+	"github.com/fuseml/fuseml/cli/paas/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	restclient "k8s.io/client-go/rest"
+)
+
+var _ = Describe("Installed components", func() {
+	var configMaps typedcorev1.ConfigMapInterface
+
+	BeforeEach(func() {
+		configMaps = fake.NewSimpleClientset().CoreV1().ConfigMaps(deployments.WorkloadsDeploymentID)
+	})
+
+	It("are not recorded by older installations", func() {
+		_, recorded, err := InstalledComponents(configMaps)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recorded).To(BeFalse())
+	})
+
+	It("records the installed components and forgets the uninstalled ones", func() {
+		err := RecordComponents(configMaps, kubernetes.Deployments{&deployments.Quarks{}, &deployments.Gitea{}}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		err = RecordComponents(configMaps, kubernetes.Deployments{&deployments.Tekton{}}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		err = RecordComponents(configMaps, nil, kubernetes.Deployments{&deployments.Gitea{}})
+		Expect(err).ToNot(HaveOccurred())
+
+		installed, recorded, err := InstalledComponents(configMaps)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recorded).To(BeTrue())
+		Expect(installed).To(ConsistOf(deployments.QuarksDeploymentID, deployments.TektonDeploymentID))
+	})
+
+	It("does not start a record when uninstalling", func() {
+		err := RecordComponents(configMaps, nil, kubernetes.Deployments{&deployments.Gitea{}})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, recorded, err := InstalledComponents(configMaps)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recorded).To(BeFalse())
+	})
+})
+
+var _ = Describe("Status", func() {
+	var (
+		api    kubeStandIn
+		server *httptest.Server
+		client *InstallClient
+	)
+
+	BeforeEach(func() {
+		api = kubeStandIn{}
+		server = httptest.NewServer(api)
+
+		restConfig := &restclient.Config{Host: server.URL}
+		clientset, err := k8s.NewForConfig(restConfig)
+		Expect(err).ToNot(HaveOccurred())
+
+		client = NewTestInstallClient(&kubernetes.Cluster{Kubectl: clientset, RestConfig: restConfig}, &config.Config{})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	record := func(ids string) {
+		api["/api/v1/namespaces/fuseml-workloads/configmaps/fuseml-components"] = corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "fuseml-components", Namespace: "fuseml-workloads"},
+			Data:       map[string]string{"installed": ids},
+		}
+	}
+
+	It("does not check the components skipped at install", func() {
+		record("")
+		Expect(client.Status("json")).To(Succeed())
+	})
+
+	It("checks the installed components", func() {
+		record(deployments.QuarksDeploymentID)
+		Expect(client.Status("json")).To(MatchError(ErrDegraded))
+	})
+})
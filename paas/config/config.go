@@ -1,8 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -19,10 +23,72 @@ type Config struct {
 	GiteaProtocol            string `mapstructure:"gitea_protocol"`
 	FusemlWorkloadsNamespace string `mapstructure:"fuseml_workloads_namespace"`
 	Org                      string `mapstructure:"org"`
+	GCKeepRuns               int    `mapstructure:"gc_keep_runs"`
+	GCMaxAge                 string `mapstructure:"gc_max_age"`
+	ImageRegistry            string `mapstructure:"image_registry"`
+	GitProvider              string `mapstructure:"git_provider"`
+	GitlabURL                string `mapstructure:"gitlab_url"`
+	GitlabToken              string `mapstructure:"gitlab_token"`
+	WebhookURL               string `mapstructure:"webhook_url"`
+	// IngressPort is the node port the ingress is reached at, when there is
+	// no load balancer
+	IngressPort int `mapstructure:"ingress_port"`
+	// The Gitea user logged in with fuseml login, and their personal access
+	// token, named GiteaTokenName, or OAuth2 token. Without them, the admin
+	// credentials of the cluster are used.
+	GiteaUsername     string `mapstructure:"gitea_username"`
+	GiteaToken        string `mapstructure:"gitea_token"`
+	GiteaTokenName    string `mapstructure:"gitea_token_name"`
+	GiteaRefreshToken string `mapstructure:"gitea_refresh_token"`
+	GiteaTokenExpiry  string `mapstructure:"gitea_token_expiry"`
+	// The OAuth2 application of the cli registered in Gitea, for fuseml
+	// login --oauth2
+	GiteaOAuth2ClientID     string `mapstructure:"gitea_oauth2_client_id"`
+	GiteaOAuth2ClientSecret string `mapstructure:"gitea_oauth2_client_secret"`
+	// Contexts are the named installations the cli talks to, e.g. on a dev,
+	// a staging and a prod cluster
+	Contexts map[string]*Context `mapstructure:"contexts"`
+	// CurrentContext is the name of the context used when no --context is
+	// given, empty to use the top level settings
+	CurrentContext string `mapstructure:"current_context"`
 
-	v *viper.Viper
+	v       *viper.Viper
+	file    map[string]interface{} // The settings of the file, as written by Save.
+	context string
+	// top and loaded are the settings before and after applying the
+	// context, to find the ones changed by the commands
+	top, loaded *Config
+	unset       map[string]bool
 }
 
+// Context holds the settings of one installation. Empty settings are taken
+// from the top level of the configuration, except the credentials, which
+// belong to the installation.
+type Context struct {
+	Kubeconfig               string `mapstructure:"kubeconfig" yaml:"kubeconfig,omitempty"`
+	KubeContext              string `mapstructure:"kube_context" yaml:"kube_context,omitempty"`
+	FusemlWorkloadsNamespace string `mapstructure:"fuseml_workloads_namespace" yaml:"fuseml_workloads_namespace,omitempty"`
+	Org                      string `mapstructure:"org" yaml:"org,omitempty"`
+	GiteaProtocol            string `mapstructure:"gitea_protocol" yaml:"gitea_protocol,omitempty"`
+	ImageRegistry            string `mapstructure:"image_registry" yaml:"image_registry,omitempty"`
+	GitProvider              string `mapstructure:"git_provider" yaml:"git_provider,omitempty"`
+	GitlabURL                string `mapstructure:"gitlab_url" yaml:"gitlab_url,omitempty"`
+	GitlabToken              string `mapstructure:"gitlab_token" yaml:"gitlab_token,omitempty"`
+	WebhookURL               string `mapstructure:"webhook_url" yaml:"webhook_url,omitempty"`
+	IngressPort              int    `mapstructure:"ingress_port" yaml:"ingress_port,omitempty"`
+	GiteaUsername            string `mapstructure:"gitea_username" yaml:"gitea_username,omitempty"`
+	GiteaToken               string `mapstructure:"gitea_token" yaml:"gitea_token,omitempty"`
+	GiteaTokenName           string `mapstructure:"gitea_token_name" yaml:"gitea_token_name,omitempty"`
+	GiteaRefreshToken        string `mapstructure:"gitea_refresh_token" yaml:"gitea_refresh_token,omitempty"`
+	GiteaTokenExpiry         string `mapstructure:"gitea_token_expiry" yaml:"gitea_token_expiry,omitempty"`
+	GiteaOAuth2ClientID      string `mapstructure:"gitea_oauth2_client_id" yaml:"gitea_oauth2_client_id,omitempty"`
+	GiteaOAuth2ClientSecret  string `mapstructure:"gitea_oauth2_client_secret" yaml:"gitea_oauth2_client_secret,omitempty"`
+}
+
+// contextName matches the names of contexts. They are lower case, as the
+// keys of the configuration file are case insensitive.
+var contextName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
 // DefaultLocation returns the standard location for the configuration file
 func DefaultLocation() string {
 	return defaultConfigFilePath
@@ -31,29 +97,46 @@ func DefaultLocation() string {
 // Load loads the Fuseml config
 func Load(flags *pflag.FlagSet) (*Config, error) {
 	v := viper.New()
-	file := location()
+	file := Location()
 
 	v.SetConfigType("yaml")
 	v.SetConfigFile(file)
 	v.SetEnvPrefix("FUSEML")
 	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
 
-	v.SetDefault("gitea_namespace", "gitea")
-	v.SetDefault("gitea_protocol", "http")
-	v.SetDefault("fuseml_workloads_namespace", "fuseml-workloads")
-	v.SetDefault("org", "workspace")
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+	// Bound one by one, as settings without default are not found by
+	// AutomaticEnv when unmarshaling
+	for _, key := range Keys() {
+		if err := v.BindEnv(key); err != nil {
+			return nil, errors.Wrapf(err, "failed to bind env of '%s'", key)
+		}
+	}
 
+	settings := map[string]interface{}{}
+	f := viper.New()
 	configExists, err := fileExists(file)
 	if err != nil {
 		return nil, errors.Wrapf(err, "filesystem error")
 	}
 
 	if configExists {
-		if err := v.ReadInConfig(); err != nil {
+		f.SetConfigType("yaml")
+		f.SetConfigFile(file)
+		if err := f.ReadInConfig(); err != nil {
+			return nil, errors.Wrapf(err, "failed to read config file '%s'", file)
+		}
+		settings = migrate(f.AllSettings())
+		if err := v.MergeConfigMap(settings); err != nil {
 			return nil, errors.Wrapf(err, "failed to read config file '%s'", file)
 		}
+		// Written by Save from the fields of the Config
+		delete(settings, "version")
+		delete(settings, "contexts")
+		delete(settings, "current_context")
 	}
-	v.AutomaticEnv()
 
 	cfg := new(Config)
 
@@ -61,29 +144,206 @@ func Load(flags *pflag.FlagSet) (*Config, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to unmarshal config file")
 	}
+	// Contexts without settings are missing from the merged settings
+	if configExists {
+		err = f.UnmarshalKey("contexts", &cfg.Contexts)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal contexts")
+		}
+	}
 
 	cfg.v = v
+	cfg.file = settings
+	cfg.unset = map[string]bool{}
+	if cfg.Contexts == nil {
+		cfg.Contexts = map[string]*Context{}
+	}
+
+	top := *cfg
+	cfg.top = &top
+	name := viper.GetString("context")
+	if name == "" {
+		name = cfg.CurrentContext
+	}
+	if name != "" {
+		ctx, ok := cfg.Contexts[name]
+		if !ok {
+			return nil, errors.Errorf("unknown context '%s', see fuseml context list", name)
+		}
+		cfg.apply(ctx)
+		cfg.context = name
+	}
+	loaded := *cfg
+	cfg.loaded = &loaded
+
 	return cfg, nil
 }
 
-// Save saves the Fuseml config
+// apply overrides the top level settings with those set by the context.
+// The credentials of the top level are dropped, unless given by the
+// environment.
+func (c *Config) apply(ctx *Context) {
+	for _, key := range contextKeys() {
+		value := setting(ctx, key)
+		field := setting(c, key)
+		switch {
+		case !value.IsZero():
+			field.Set(value)
+		case credentialKeys[key] && !inEnv(key):
+			field.Set(reflect.Zero(field.Type()))
+		}
+	}
+}
+
+// Context returns the name and the settings of the active context, chosen
+// with --context or by CurrentContext, or nil if none is
+func (c *Config) Context() (string, *Context) {
+	if c.context == "" {
+		return "", nil
+	}
+	return c.context, c.Contexts[c.context]
+}
+
+// ContextNames returns the names of all contexts, sorted
+func (c *Config) ContextNames() []string {
+	names := []string{}
+	for name := range c.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// AddContext adds a new context with the given settings
+func (c *Config) AddContext(name string, ctx *Context) error {
+	if !contextName.MatchString(name) {
+		return errors.Errorf("invalid context name '%s', use lower case letters, digits and '-'", name)
+	}
+	if _, ok := c.Contexts[name]; ok {
+		return errors.Errorf("context '%s' exists already", name)
+	}
+
+	c.Contexts[name] = ctx
+
+	return nil
+}
+
+// RemoveContext removes the context. Removing the current context makes
+// the top level settings current again.
+func (c *Config) RemoveContext(name string) error {
+	if _, ok := c.Contexts[name]; !ok {
+		return errors.Errorf("unknown context '%s'", name)
+	}
+
+	delete(c.Contexts, name)
+	if c.CurrentContext == name {
+		c.CurrentContext = ""
+	}
+	if c.context == name {
+		c.context = ""
+		for _, key := range contextKeys() {
+			setting(c, key).Set(setting(c.top, key))
+			setting(c.loaded, key).Set(setting(c.top, key))
+		}
+	}
+
+	return nil
+}
+
+// UseContext makes the context current, or the top level settings if name
+// is empty
+func (c *Config) UseContext(name string) error {
+	if _, ok := c.Contexts[name]; !ok && name != "" {
+		return errors.Errorf("unknown context '%s'", name)
+	}
+
+	c.CurrentContext = name
+
+	return nil
+}
+
+// Save saves the Fuseml config. Only the settings read from the file, or
+// changed since loading it, are written, so that the others keep following
+// their defaults.
 func (c *Config) Save() error {
-	c.v.Set("org", c.Org)
+	// The settings changed by the commands belong to the active context,
+	// if it holds them
+	_, ctx := c.Context()
+	for _, key := range Keys() {
+		inContext := ctx != nil && hasSetting(ctx, key)
+		switch {
+		case c.unset[key] && inContext:
+			value := setting(ctx, key)
+			value.Set(reflect.Zero(value.Type()))
+		case c.unset[key]:
+			delete(c.file, key)
+		case setting(c, key).Interface() == setting(c.loaded, key).Interface():
+		case inContext:
+			setting(ctx, key).Set(setting(c, key))
+		default:
+			c.file[key] = setting(c, key).Interface()
+		}
+	}
+
+	w := viper.New()
+	w.SetConfigType("yaml")
+	w.SetConfigFile(c.v.ConfigFileUsed())
+	for key, value := range c.file {
+		w.Set(key, value)
+	}
+	w.Set("version", SchemaVersion)
+	if len(c.Contexts) > 0 {
+		w.Set("contexts", c.Contexts)
+	}
+	if c.CurrentContext != "" {
+		w.Set("current_context", c.CurrentContext)
+	}
+
+	err := os.MkdirAll(filepath.Dir(w.ConfigFileUsed()), 0700)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create config dir '%s'", filepath.Dir(w.ConfigFileUsed()))
+	}
 
-	err := os.MkdirAll(filepath.Dir(c.v.ConfigFileUsed()), 0700)
+	// The file holds tokens and credentials
+	w.SetConfigPermissions(0600)
+	err = w.WriteConfig()
 	if err != nil {
-		return errors.Wrapf(err, "failed to create config dir '%s'", filepath.Dir(c.v.ConfigFileUsed()))
+		return errors.Wrapf(err, "failed to write config file '%s'", w.ConfigFileUsed())
 	}
 
-	err = c.v.WriteConfig()
+	// The permissions only apply to new files, narrow those of older ones
+	err = os.Chmod(w.ConfigFileUsed(), 0600)
 	if err != nil {
-		return errors.Wrapf(err, "failed to write config file '%s'", c.v.ConfigFileUsed())
+		return errors.Wrapf(err, "failed to restrict the permissions of config file '%s'", w.ConfigFileUsed())
 	}
 
 	return nil
 }
 
-func location() string {
+// IngressHost returns the host as reached through the ingress, i.e. with
+// the node port when there is no load balancer
+func IngressHost(host string, port int) string {
+	if port == 0 {
+		return host
+	}
+
+	return fmt.Sprintf("%s:%d", host, port)
+}
+
+// IngressScheme returns the scheme the ingress is reached with: plain HTTP
+// with the node port, which is that of the HTTP entrypoint, when there is no
+// load balancer
+func IngressScheme(scheme string, port int) string {
+	if port == 0 {
+		return scheme
+	}
+
+	return "http"
+}
+
+// Location returns the location of the configuration file in use
+func Location() string {
 	return viper.GetString("config-file")
 }
 
@@ -0,0 +1,363 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+
+	"github.com/fuseml/fuseml/cli/paas/config"
+)
+
+var _ = Describe("Contexts", func() {
+	var dir, file string
+
+	load := func() *config.Config {
+		cfg, err := config.Load(nil)
+		Expect(err).ToNot(HaveOccurred())
+		return cfg
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "fuseml-config")
+		Expect(err).ToNot(HaveOccurred())
+		file = filepath.Join(dir, "config.yaml")
+
+		err = ioutil.WriteFile(file, []byte(`
+org: workspace
+contexts:
+  dev:
+    kubeconfig: /kube/dev
+    org: team
+  prod:
+    kube_context: prod-admin
+    fuseml_workloads_namespace: prod-workloads
+`), 0600)
+		Expect(err).ToNot(HaveOccurred())
+
+		viper.Set("config-file", file)
+		viper.Set("context", "")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("uses the top level settings without a current context", func() {
+		cfg := load()
+
+		name, ctx := cfg.Context()
+		Expect(name).To(BeEmpty())
+		Expect(ctx).To(BeNil())
+		Expect(cfg.Org).To(Equal("workspace"))
+		Expect(cfg.ContextNames()).To(Equal([]string{"dev", "prod"}))
+	})
+
+	It("applies the settings of the context chosen with --context", func() {
+		viper.Set("context", "prod")
+		cfg := load()
+
+		name, ctx := cfg.Context()
+		Expect(name).To(Equal("prod"))
+		Expect(ctx.KubeContext).To(Equal("prod-admin"))
+		Expect(cfg.FusemlWorkloadsNamespace).To(Equal("prod-workloads"))
+		Expect(cfg.Org).To(Equal("workspace"))
+	})
+
+	It("fails for an unknown context", func() {
+		viper.Set("context", "staging")
+
+		_, err := config.Load(nil)
+		Expect(err).To(MatchError(ContainSubstring("unknown context 'staging'")))
+	})
+
+	It("saves changed settings into the current context", func() {
+		cfg := load()
+		Expect(cfg.UseContext("dev")).To(Succeed())
+		Expect(cfg.Save()).To(Succeed())
+
+		cfg = load()
+		Expect(cfg.Org).To(Equal("team"))
+		cfg.Org = "other"
+		Expect(cfg.Save()).To(Succeed())
+
+		cfg = load()
+		Expect(cfg.Org).To(Equal("other"))
+		Expect(cfg.UseContext("")).To(Succeed())
+		Expect(cfg.Save()).To(Succeed())
+
+		cfg = load()
+		Expect(cfg.Org).To(Equal("workspace"))
+	})
+
+	Context("with credentials at the top level", func() {
+		BeforeEach(func() {
+			err := ioutil.WriteFile(file, []byte(`
+org: workspace
+gitea_username: alice
+gitea_token: alice-token
+gitea_token_name: fuseml-cli
+gitea_refresh_token: alice-refresh
+gitea_token_expiry: "2030-01-01T00:00:00Z"
+gitlab_token: gitlab-token
+contexts:
+  dev:
+    kubeconfig: /kube/dev
+    gitea_username: bob
+    gitea_token: bob-token
+  prod:
+    kube_context: prod-admin
+`), 0600)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("uses them without a current context", func() {
+			cfg := load()
+			Expect(cfg.GiteaUsername).To(Equal("alice"))
+			Expect(cfg.GiteaToken).To(Equal("alice-token"))
+			Expect(cfg.GitlabToken).To(Equal("gitlab-token"))
+		})
+
+		It("does not take them into a context", func() {
+			viper.Set("context", "prod")
+			cfg := load()
+
+			Expect(cfg.Org).To(Equal("workspace"))
+			Expect(cfg.GiteaUsername).To(BeEmpty())
+			Expect(cfg.GiteaToken).To(BeEmpty())
+			Expect(cfg.GiteaTokenName).To(BeEmpty())
+			Expect(cfg.GiteaRefreshToken).To(BeEmpty())
+			Expect(cfg.GiteaTokenExpiry).To(BeEmpty())
+			Expect(cfg.GitlabToken).To(BeEmpty())
+
+			value, source, err := cfg.Get("gitea_token")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(value).To(BeEmpty())
+			Expect(source).To(Equal(config.SourceDefault))
+		})
+
+		It("uses those of the context, without mixing in the others", func() {
+			viper.Set("context", "dev")
+			cfg := load()
+
+			Expect(cfg.GiteaUsername).To(Equal("bob"))
+			Expect(cfg.GiteaToken).To(Equal("bob-token"))
+			Expect(cfg.GiteaRefreshToken).To(BeEmpty())
+			Expect(cfg.GiteaTokenExpiry).To(BeEmpty())
+		})
+
+		It("uses those of the environment in a context", func() {
+			os.Setenv("FUSEML_GITEA_TOKEN", "env-token")
+			defer os.Unsetenv("FUSEML_GITEA_TOKEN")
+			viper.Set("context", "prod")
+			cfg := load()
+
+			value, source, err := cfg.Get("gitea_token")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(value).To(Equal("env-token"))
+			Expect(source).To(Equal(config.SourceEnv))
+		})
+
+		It("keeps them at the top level when saving a context", func() {
+			viper.Set("context", "prod")
+			cfg := load()
+			Expect(cfg.Save()).To(Succeed())
+
+			viper.Set("context", "")
+			cfg = load()
+			Expect(cfg.GiteaToken).To(Equal("alice-token"))
+			Expect(cfg.Contexts["prod"].GiteaToken).To(BeEmpty())
+		})
+	})
+
+	It("adds and removes contexts", func() {
+		cfg := load()
+		Expect(cfg.AddContext("Staging", &config.Context{})).To(MatchError(ContainSubstring("invalid context name")))
+		Expect(cfg.AddContext("dev", &config.Context{})).To(MatchError(ContainSubstring("exists already")))
+		Expect(cfg.AddContext("staging", &config.Context{Org: "qa"})).To(Succeed())
+		Expect(cfg.UseContext("dev")).To(Succeed())
+		Expect(cfg.RemoveContext("dev")).To(Succeed())
+		Expect(cfg.Save()).To(Succeed())
+
+		cfg = load()
+		Expect(cfg.CurrentContext).To(BeEmpty())
+		Expect(cfg.ContextNames()).To(Equal([]string{"prod", "staging"}))
+		Expect(cfg.Contexts["staging"].Org).To(Equal("qa"))
+	})
+})
+
+var _ = Describe("Settings", func() {
+	var dir, file string
+
+	load := func() *config.Config {
+		cfg, err := config.Load(nil)
+		Expect(err).ToNot(HaveOccurred())
+		return cfg
+	}
+
+	write := func(content string) {
+		Expect(ioutil.WriteFile(file, []byte(content), 0600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "fuseml-config")
+		Expect(err).ToNot(HaveOccurred())
+		file = filepath.Join(dir, "config.yaml")
+
+		viper.Set("config-file", file)
+		viper.Set("context", "")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("knows the settings of the Config", func() {
+		Expect(config.Keys()).To(ContainElement("gitea_protocol"))
+		Expect(config.Keys()).To(ContainElement("gc_keep_runs"))
+		Expect(config.Keys()).ToNot(ContainElement("contexts"))
+		Expect(config.Keys()).ToNot(ContainElement("current_context"))
+	})
+
+	It("tells where the values come from", func() {
+		write("org: team\n")
+		os.Setenv("FUSEML_GC_MAX_AGE", "24h")
+		defer os.Unsetenv("FUSEML_GC_MAX_AGE")
+		cfg := load()
+
+		value, source, err := cfg.Get("org")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value).To(Equal("team"))
+		Expect(source).To(Equal(config.SourceFile))
+
+		value, source, _ = cfg.Get("gc_max_age")
+		Expect(value).To(Equal("24h"))
+		Expect(source).To(Equal(config.SourceEnv))
+
+		value, source, _ = cfg.Get("gc_keep_runs")
+		Expect(value).To(Equal(5))
+		Expect(source).To(Equal(config.SourceDefault))
+
+		_, _, err = cfg.Get("gitea_namespace")
+		Expect(err).To(MatchError(ContainSubstring("unknown setting 'gitea_namespace'")))
+	})
+
+	It("ignores empty environment variables", func() {
+		write("org: team\n")
+		os.Setenv("FUSEML_ORG", "")
+		defer os.Unsetenv("FUSEML_ORG")
+		cfg := load()
+
+		value, source, err := cfg.Get("org")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value).To(Equal("team"))
+		Expect(source).To(Equal(config.SourceFile))
+	})
+
+	It("prefers the active context over the environment", func() {
+		write(`
+org: team
+current_context: dev
+contexts:
+  dev:
+    org: dev-team
+`)
+		os.Setenv("FUSEML_ORG", "env-team")
+		defer os.Unsetenv("FUSEML_ORG")
+		os.Setenv("FUSEML_GITEA_PROTOCOL", "https")
+		defer os.Unsetenv("FUSEML_GITEA_PROTOCOL")
+		cfg := load()
+
+		value, source, err := cfg.Get("org")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value).To(Equal("dev-team"))
+		Expect(source).To(Equal(config.SourceContext))
+
+		// The context does not set it
+		value, source, err = cfg.Get("gitea_protocol")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value).To(Equal("https"))
+		Expect(source).To(Equal(config.SourceEnv))
+	})
+
+	It("writes only the settings set in the file", func() {
+		cfg := load()
+		Expect(cfg.Set("gc_keep_runs", "three")).To(MatchError(ContainSubstring("needs an integer")))
+		Expect(cfg.Set("gc_keep_runs", "3")).To(Succeed())
+		Expect(cfg.Save()).To(Succeed())
+
+		content, err := ioutil.ReadFile(file)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("gc_keep_runs: 3\nversion: 1\n"))
+
+		cfg = load()
+		Expect(cfg.GCKeepRuns).To(Equal(3))
+		Expect(cfg.Unset("gc_keep_runs")).To(Succeed())
+		Expect(cfg.Save()).To(Succeed())
+
+		cfg = load()
+		_, source, _ := cfg.Get("gc_keep_runs")
+		Expect(source).To(Equal(config.SourceDefault))
+	})
+
+	It("keeps the file private to the user", func() {
+		cfg := load()
+		Expect(cfg.Save()).To(Succeed())
+
+		info, err := os.Stat(file)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+		Expect(os.Chmod(file, 0644)).To(Succeed())
+		cfg = load()
+		Expect(cfg.Save()).To(Succeed())
+
+		info, err = os.Stat(file)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+	})
+
+	It("migrates files of the first schema", func() {
+		write(`
+fuseml_workloads_namespace: fuseml-workloads
+gc_keep_runs: 5
+gitea_namespace: gitea
+gitea_protocol: https
+image_registry: ""
+ingress_port: 0
+org: workspace
+`)
+		cfg := load()
+		Expect(cfg.GiteaProtocol).To(Equal("https"))
+		Expect(cfg.Save()).To(Succeed())
+
+		content, err := ioutil.ReadFile(file)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("gitea_protocol: https\nversion: 1\n"))
+	})
+})
+
+var _ = Describe("IngressHost", func() {
+	It("appends the node port of the ingress", func() {
+		Expect(config.IngressHost("gitea.10.0.0.1.nip.io", 31080)).To(Equal("gitea.10.0.0.1.nip.io:31080"))
+	})
+
+	It("keeps the host without node port", func() {
+		Expect(config.IngressHost("gitea.10.0.0.1.nip.io", 0)).To(Equal("gitea.10.0.0.1.nip.io"))
+	})
+})
+
+var _ = Describe("IngressScheme", func() {
+	It("reaches the node port of the ingress with plain HTTP", func() {
+		Expect(config.IngressScheme("https", 31080)).To(Equal("http"))
+	})
+
+	It("keeps the scheme without node port", func() {
+		Expect(config.IngressScheme("https", 0)).To(Equal("https"))
+	})
+})
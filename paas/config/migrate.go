@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+)
+
+// SchemaVersion is the version of the layout of the config file written by
+// Save. Files of older versions are migrated when loaded.
+const SchemaVersion = 1
+
+// migrations upgrade the settings of a config file from the version of
+// their index to the next one
+var migrations = []func(settings map[string]interface{}){
+	// Version 0 files hold every setting, as Save wrote the defaults too,
+	// and the unused gitea_namespace. Settings equal to their default are
+	// dropped, so that they follow changes of the defaults.
+	func(settings map[string]interface{}) {
+		delete(settings, "gitea_namespace")
+		for _, key := range Keys() {
+			value, ok := settings[key]
+			if !ok {
+				continue
+			}
+			if value == nil || value == "" || value == 0 ||
+				(defaults[key] != nil && fmt.Sprint(value) == fmt.Sprint(defaults[key])) {
+				delete(settings, key)
+			}
+		}
+	},
+}
+
+// migrate upgrades the settings of a config file to SchemaVersion
+func migrate(settings map[string]interface{}) map[string]interface{} {
+	version := 0
+	if v, ok := settings["version"].(int); ok {
+		version = v
+	}
+
+	for ; version < len(migrations); version++ {
+		migrations[version](settings)
+	}
+	settings["version"] = SchemaVersion
+
+	return settings
+}
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Source tells where the value of a setting comes from
+type Source string
+
+// The sources of the settings, in order of precedence
+const (
+	SourceContext Source = "context"
+	SourceEnv     Source = "env"
+	SourceFile    Source = "file"
+	SourceDefault Source = "default"
+)
+
+// defaults are the values of the settings missing from the file and the
+// env
+var defaults = map[string]interface{}{
+	"gitea_protocol":             "http",
+	"fuseml_workloads_namespace": "fuseml-workloads",
+	"org":                        "workspace",
+	"gc_keep_runs":               5,
+	"gc_max_age":                 "168h",
+	"git_provider":               "gitea",
+}
+
+// secretKeys are the settings holding credentials, hidden by fuseml config
+// view
+var secretKeys = map[string]bool{
+	"gitlab_token":               true,
+	"gitea_token":                true,
+	"gitea_refresh_token":        true,
+	"gitea_oauth2_client_secret": true,
+}
+
+// credentialKeys are the settings belonging to the Git host of one
+// installation, which contexts do not take from the top level, so that they
+// are not sent to another installation
+var credentialKeys = map[string]bool{
+	"gitlab_token":               true,
+	"gitea_username":             true,
+	"gitea_token":                true,
+	"gitea_token_name":           true,
+	"gitea_refresh_token":        true,
+	"gitea_token_expiry":         true,
+	"gitea_oauth2_client_secret": true,
+}
+
+// Keys returns the keys of the settings of the Config, sorted. The
+// contexts are managed by fuseml context, and are not part of them.
+func Keys() []string {
+	keys := []string{}
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Tag.Get("mapstructure")
+		if key == "" || key == "current_context" || field.Type.Kind() == reflect.Map {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// IsSecret returns whether the setting holds credentials
+func IsSecret(key string) bool {
+	return secretKeys[key]
+}
+
+// contextKeys returns the keys of the settings a context can hold
+func contextKeys() []string {
+	keys := []string{}
+	for _, key := range Keys() {
+		if hasSetting(&Context{}, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// setting returns the field of the settings struct s, a *Config or a
+// *Context, with the mapstructure tag key, or the zero Value
+func setting(s interface{}, key string) reflect.Value {
+	v := reflect.ValueOf(s).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).Tag.Get("mapstructure") == key {
+			return v.Field(i)
+		}
+	}
+
+	return reflect.Value{}
+}
+
+func hasSetting(s interface{}, key string) bool {
+	return setting(s, key).IsValid()
+}
+
+func checkKey(key string) error {
+	for _, known := range Keys() {
+		if key == known {
+			return nil
+		}
+	}
+
+	return errors.Errorf("unknown setting '%s', expected one of %s", key, strings.Join(Keys(), ", "))
+}
+
+// Get returns the value of the setting, and where it comes from
+func (c *Config) Get(key string) (interface{}, Source, error) {
+	if err := checkKey(key); err != nil {
+		return nil, "", err
+	}
+
+	return setting(c, key).Interface(), c.source(key), nil
+}
+
+// source returns where the value of the setting comes from, as loaded
+func (c *Config) source(key string) Source {
+	if _, ctx := c.Context(); ctx != nil && hasSetting(ctx, key) && !setting(ctx, key).IsZero() {
+		return SourceContext
+	}
+	if inEnv(key) {
+		return SourceEnv
+	}
+	if _, ctx := c.Context(); ctx != nil && credentialKeys[key] {
+		return SourceDefault
+	}
+	if _, ok := c.file[key]; ok {
+		return SourceFile
+	}
+
+	return SourceDefault
+}
+
+// inEnv returns whether the setting is given by its environment variable.
+// Like viper, variables which are set but empty are ignored.
+func inEnv(key string) bool {
+	return os.Getenv("FUSEML_"+strings.ToUpper(key)) != ""
+}
+
+// Set changes the setting, in the active context if it holds the setting,
+// else at the top level. The value is parsed according to the type of the
+// setting.
+func (c *Config) Set(key, value string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	field := setting(c, key)
+	switch field.Kind() {
+	case reflect.Int:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.Errorf("setting '%s' needs an integer, not '%s'", key, value)
+		}
+		field.SetInt(int64(i))
+	default:
+		field.SetString(value)
+	}
+	delete(c.unset, key)
+
+	return nil
+}
+
+// Unset removes the setting from the active context if it holds the
+// setting, else from the file, so that it follows its default again
+func (c *Config) Unset(key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	c.unset[key] = true
+
+	return nil
+}
+
+// SourceName returns the source for display, naming the active context
+func (c *Config) SourceName(source Source) string {
+	if source == SourceContext {
+		return fmt.Sprintf("%s %s", source, c.context)
+	}
+
+	return string(source)
+}
@@ -0,0 +1,132 @@
+package paas
+
+import (
+	"strconv"
+
+	kubeconfig "github.com/fuseml/fuseml/cli/kubernetes/config"
+	"github.com/fuseml/fuseml/cli/paas/config"
+	"github.com/fuseml/fuseml/cli/paas/ui"
+	"github.com/spf13/pflag"
+)
+
+// kubeLocation returns where the kube config of the active context is read
+// from. A kubeconfig given on the command line takes precedence over the
+// one of the context, which takes precedence over the KUBECONFIG env.
+func kubeLocation(flags *pflag.FlagSet, cfg *config.Config) kubeconfig.Location {
+	location := kubeconfig.DefaultLocation()
+
+	_, ctx := cfg.Context()
+	if ctx == nil {
+		return location
+	}
+
+	location.Context = ctx.KubeContext
+	if ctx.Kubeconfig != "" && (flags == nil || !flags.Changed("kubeconfig")) {
+		location.Path = ctx.Kubeconfig
+	}
+
+	return location
+}
+
+// NewContextUI returns the UI of a client, showing the active context in the
+// header of every command
+func NewContextUI(cfg *config.Config) *ui.UI {
+	u := ui.NewUI()
+	name, _ := cfg.Context()
+	u.SetContext(name)
+
+	return u
+}
+
+// ListContexts prints the contexts of the configuration, marking the active
+// one
+func ListContexts(ui *ui.UI, cfg *config.Config) error {
+	ui.Note().Msg("Listing contexts")
+
+	names := cfg.ContextNames()
+	if len(names) == 0 {
+		ui.Normal().Msg("No contexts, add one with fuseml context add")
+		return nil
+	}
+
+	active, _ := cfg.Context()
+	msg := ui.Success().WithTable("", "Name", "Kubeconfig", "Kube Context", "Namespace", "Org", "Ingress Port")
+	for _, name := range names {
+		ctx := cfg.Contexts[name]
+		current := ""
+		if name == active {
+			current = "*"
+		}
+		port := ""
+		if ctx.IngressPort != 0 {
+			port = strconv.Itoa(ctx.IngressPort)
+		}
+		msg = msg.WithTableRow(current, name, ctx.Kubeconfig, ctx.KubeContext, ctx.FusemlWorkloadsNamespace, ctx.Org, port)
+	}
+	msg.Msg("Contexts:")
+
+	return nil
+}
+
+// UseContext makes the context current, or the top level settings of the
+// configuration if name is empty
+func UseContext(ui *ui.UI, cfg *config.Config, name string) error {
+	if err := cfg.UseContext(name); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	if name == "" {
+		ui.Success().Msg("Using the settings outside of any context")
+		return nil
+	}
+
+	ui.Success().WithStringValue("Name", name).Msg("Switched context")
+
+	return nil
+}
+
+// AddContext adds a context to the configuration, and makes it current if
+// use is set
+func AddContext(ui *ui.UI, cfg *config.Config, name string, ctx *config.Context, use bool) error {
+	ui.Note().WithStringValue("Name", name).Msg("Adding context...")
+
+	if err := cfg.AddContext(name, ctx); err != nil {
+		return err
+	}
+	if use {
+		if err := cfg.UseContext(name); err != nil {
+			return err
+		}
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	if use {
+		ui.Success().WithStringValue("Name", name).Msg("Context added and made current")
+		return nil
+	}
+
+	ui.Success().WithStringValue("Name", name).Msg("Context added")
+
+	return nil
+}
+
+// RemoveContext removes the context from the configuration
+func RemoveContext(ui *ui.UI, cfg *config.Config, name string) error {
+	ui.Note().WithStringValue("Name", name).Msg("Removing context...")
+
+	if err := cfg.RemoveContext(name); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	ui.Success().WithStringValue("Name", name).Msg("Context removed")
+
+	return nil
+}
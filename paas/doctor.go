@@ -0,0 +1,93 @@
+package paas
+
+import (
+	"context"
+
+	"github.com/fuseml/fuseml/cli/deployments"
+	"github.com/fuseml/fuseml/cli/kubernetes/preflight"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Commands are the external commands the cli runs. None are needed, helm
+// and the post-renderer of its charts run in process.
+var Commands = []string{}
+
+// Doctor checks whether FuseML can be installed on the cluster, and reports
+// all problems found
+func (c *InstallClient) Doctor() error {
+	log := c.Log.WithName("Doctor")
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	c.ui.Header().Msg("Checking the cluster...")
+
+	results, err := c.preflight()
+	if err != nil {
+		return err
+	}
+
+	msg := c.ui.Normal().WithTable("Check", "Status", "Details")
+	for _, result := range results {
+		details.Info("checked", "Check", result.Check, "Status", result.Status.String())
+		msg = msg.WithTableRow(result.Check, result.Status.String(), result.Details)
+	}
+	msg.Msg("Preflight checks:")
+
+	if failures := preflight.Failures(results); failures > 0 {
+		c.ui.Problem().Msgf("%d checks failed, FuseML cannot be installed", failures)
+		return errors.New("preflight checks failed")
+	}
+
+	c.ui.Success().Msg("Ready to install FuseML")
+
+	return nil
+}
+
+// preflight reads the state of the cluster and checks it
+func (c *InstallClient) preflight() ([]preflight.Result, error) {
+	kube := c.kubeClient.Kubectl
+	ctx := context.Background()
+
+	serverVersion, err := kube.ServerVersion()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get kube server version")
+	}
+	classes, err := kube.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list storage classes")
+	}
+	services, err := kube.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list services")
+	}
+	nodes, err := kube.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+	namespaces, err := kube.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list namespaces")
+	}
+	crds, err := c.kubeClient.CRDNames()
+	if err != nil {
+		return nil, err
+	}
+
+	platform := ""
+	if p := c.kubeClient.GetPlatform(); p != nil {
+		platform = p.String()
+	}
+
+	results := []preflight.Result{
+		preflight.KubernetesVersion(serverVersion.GitVersion),
+		preflight.DefaultStorageClass(classes.Items),
+		preflight.LoadBalancer(platform, services.Items, nodes.Items),
+		preflight.Resources(nodes.Items),
+		preflight.Commands(Commands...),
+		preflight.Namespaces(deployments.Namespaces(), namespaces.Items),
+	}
+
+	return append(results, preflight.Serving(crds)...), nil
+}
@@ -0,0 +1,67 @@
+package paas
+
+import (
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	"github.com/fuseml/fuseml/cli/paas/config"
+	paasgitea "github.com/fuseml/fuseml/cli/paas/gitea"
+	"github.com/fuseml/fuseml/cli/paas/ui"
+	"github.com/go-logr/logr"
+)
+
+// Unexported helpers, exported for the tests of package paas_test
+
+// NewTestClient returns a client of the cluster, without git host
+func NewTestClient(cluster *kubernetes.Cluster, cfg *config.Config) *FusemlClient {
+	return &FusemlClient{
+		kubeClient:    cluster,
+		ui:            ui.NewUI(),
+		config:        cfg,
+		giteaResolver: paasgitea.NewResolver(cfg, cluster),
+		Log:           logr.Discard(),
+	}
+}
+
+// AppResources are the serving resources of the applications of an org
+type AppResources interface {
+	Status(app string) string
+	Routes(org, namespace, app string) (string, error)
+}
+
+func (c *FusemlClient) OrgAppResources(org string) (AppResources, error) {
+	return c.orgAppResources(org)
+}
+
+func (r *appResources) Status(app string) string {
+	return r.status(app)
+}
+
+func (r *appResources) Routes(org, namespace, app string) (string, error) {
+	return r.routes(org, namespace, app)
+}
+
+var (
+	CallbackHandler     = callbackHandler
+	OrgApp              = orgApp
+	InstalledComponents = installedComponents
+	RecordComponents    = recordComponents
+	RunningPipelineRuns = runningPipelineRuns
+)
+
+// NewTestInstallClient returns an install client of the cluster
+func NewTestInstallClient(cluster *kubernetes.Cluster, cfg *config.Config) *InstallClient {
+	return &InstallClient{
+		kubeClient: cluster,
+		ui:         ui.NewUI(),
+		config:     cfg,
+		Log:        logr.Discard(),
+	}
+}
+
+// NewTestLoginClient returns a login client keeping the user in cfg
+func NewTestLoginClient(cfg *config.Config) *LoginClient {
+	return &LoginClient{
+		ui:     ui.NewUI(),
+		config: cfg,
+		Log:    logr.Discard(),
+	}
+}
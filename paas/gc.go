@@ -0,0 +1,109 @@
+package paas
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// DefaultRetentionPolicy returns the PipelineRun retention policy set in the
+// fuseml configuration
+func (c *FusemlClient) DefaultRetentionPolicy() (kubernetes.RetentionPolicy, error) {
+	policy := kubernetes.RetentionPolicy{KeepRuns: c.config.GCKeepRuns}
+
+	if c.config.GCMaxAge != "" {
+		maxAge, err := time.ParseDuration(c.config.GCMaxAge)
+		if err != nil {
+			return policy, errors.Wrapf(err, "invalid gc_max_age '%s' in configuration", c.config.GCMaxAge)
+		}
+		policy.MaxAge = maxAge
+	}
+
+	return policy, nil
+}
+
+// GC removes the PipelineRuns and workspace volumes of an app, or of all
+// apps when app is empty, which are expired according to the given policy
+func (c *FusemlClient) GC(app string, policy kubernetes.RetentionPolicy) error {
+	log := c.Log.WithName("GC").WithValues("Application", app)
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	msg := c.ui.Header().
+		WithIntValue("Keep runs", policy.KeepRuns).
+		WithStringValue("Max age", policy.MaxAge.String())
+	if app != "" {
+		msg = msg.WithStringValue("Application", app)
+	}
+	msg.Msg("Collecting garbage...")
+
+	details.Info("collect")
+	removed, reclaimed, err := c.collectPipelineRuns(app, policy)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().
+		WithIntValue("PipelineRuns removed", removed).
+		WithStringValue("Storage reclaimed", reclaimed.String()).
+		Msg("Garbage collected.")
+
+	return nil
+}
+
+// gcAfterPush enforces the configured retention policy on the runs of an
+// app which was just pushed. Problems are reported, but do not fail the push.
+func (c *FusemlClient) gcAfterPush(app string) {
+	policy, err := c.DefaultRetentionPolicy()
+	if err != nil {
+		c.ui.Exclamation().Msg(err.Error())
+		return
+	}
+
+	removed, reclaimed, err := c.collectPipelineRuns(app, policy)
+	if err != nil {
+		c.ui.Exclamation().Msg("Failed to remove old PipelineRuns: " + err.Error())
+		return
+	}
+
+	if removed > 0 {
+		c.ui.Normal().
+			WithIntValue("PipelineRuns removed", removed).
+			WithStringValue("Storage reclaimed", reclaimed.String()).
+			Msg("Removed old application builds.")
+	}
+}
+
+func (c *FusemlClient) collectPipelineRuns(app string, policy kubernetes.RetentionPolicy) (int, resource.Quantity, error) {
+	reclaimed := resource.Quantity{}
+
+	selector := kubernetes.AppNameLabelKey
+	if app != "" {
+		selector = fmt.Sprintf("%s=%s", kubernetes.AppNameLabelKey, app)
+	}
+
+	runs, err := c.kubeClient.ListPipelineRuns(c.config.FusemlWorkloadsNamespace, selector)
+	if err != nil {
+		return 0, reclaimed, errors.Wrap(err, "failed to list PipelineRuns")
+	}
+
+	expired := policy.Expired(runs.Items, time.Now())
+	for _, run := range expired {
+		c.ui.Normal().V(1).
+			WithStringValue("PipelineRun", run.Name).
+			WithStringValue("Application", run.Labels[kubernetes.AppNameLabelKey]).
+			Msg("Removing")
+
+		size, err := c.kubeClient.DeletePipelineRun(run)
+		if err != nil {
+			return 0, reclaimed, err
+		}
+		reclaimed.Add(size)
+	}
+
+	return len(expired), reclaimed, nil
+}
@@ -0,0 +1,193 @@
+// Package git implements the git operations of the fuseml client, without
+// depending on a git binary.
+package git
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/pkg/errors"
+)
+
+// DefaultBranch is the branch holding the code of fuseml applications
+const DefaultBranch = "main"
+
+const (
+	remoteName  = "fuseml"
+	authorName  = "Fuseml"
+	authorEmail = "ci@fuseml"
+)
+
+// ErrNoChanges is returned by Push when dir holds the contents of the remote
+// branch already, so that nothing is pushed
+var ErrNoChanges = errors.New("no changes to push")
+
+// CredentialsFunc returns the username and password used to authenticate
+// against a remote. It is only called for remotes accessed over http(s).
+type CredentialsFunc func() (string, string, error)
+
+// Push commits the contents of dir on top of branch of the remote repository,
+// and pushes the result back. The remote branch is created if it doesn't
+// exist. Files missing from dir are removed from the branch. Without
+// changes, ErrNoChanges is returned.
+func Push(dir, remoteURL, branch, message string, credentials CredentialsFunc) error {
+	auth, err := authMethod(remoteURL, credentials)
+	if err != nil {
+		return err
+	}
+
+	repo, err := gogit.PlainInit(dir, false)
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize repository")
+	}
+
+	remote, err := repo.CreateRemote(&config.RemoteConfig{
+		Name: remoteName,
+		URLs: []string{remoteURL},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to add remote")
+	}
+
+	branchRef := plumbing.NewBranchReferenceName(branch)
+	remoteRef := plumbing.NewRemoteReferenceName(remoteName, branch)
+
+	err = remote.Fetch(&gogit.FetchOptions{
+		RemoteName: remoteName,
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("+refs/heads/*:refs/remotes/%s/*", remoteName))},
+		Auth:       auth,
+	})
+	switch err {
+	case nil, gogit.NoErrAlreadyUpToDate, transport.ErrEmptyRemoteRepository:
+	default:
+		return remoteError(err, remoteURL, "fetch")
+	}
+
+	// Point the local branch at the remote one, leaving the index empty, so
+	// the commit below records the contents of dir as a change on top of it
+	err = repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, branchRef))
+	if err != nil {
+		return errors.Wrap(err, "failed to set HEAD")
+	}
+	parent, err := repo.Reference(remoteRef, true)
+	switch err {
+	case nil:
+		err = repo.Storer.SetReference(plumbing.NewHashReference(branchRef, parent.Hash()))
+		if err != nil {
+			return errors.Wrap(err, "failed to set branch")
+		}
+	case plumbing.ErrReferenceNotFound:
+	default:
+		return errors.Wrap(err, "failed to resolve remote branch")
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return errors.Wrap(err, "failed to open worktree")
+	}
+
+	_, err = worktree.Add(".")
+	if err != nil {
+		return errors.Wrap(err, "failed to add files")
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return errors.Wrap(err, "failed to get worktree status")
+	}
+	if status.IsClean() {
+		if parent == nil {
+			return errors.New("nothing to push")
+		}
+		// Same contents as the remote branch, there is nothing to commit
+		return ErrNoChanges
+	}
+
+	_, err = worktree.Commit(message, &gogit.CommitOptions{
+		Author: &object.Signature{
+			Name:  authorName,
+			Email: authorEmail,
+			When:  time.Now(),
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to commit")
+	}
+
+	err = repo.Push(&gogit.PushOptions{
+		RemoteName: remoteName,
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", branchRef, branchRef))},
+		Auth:       auth,
+	})
+	if err != nil && err != gogit.NoErrAlreadyUpToDate {
+		return remoteError(err, remoteURL, "push")
+	}
+
+	return nil
+}
+
+// Clone checks out the tip of branch of the remote repository into dir,
+// without its history.
+func Clone(dir, remoteURL, branch string, credentials CredentialsFunc) error {
+	auth, err := authMethod(remoteURL, credentials)
+	if err != nil {
+		return err
+	}
+
+	_, err = gogit.PlainClone(dir, false, &gogit.CloneOptions{
+		URL:           remoteURL,
+		Auth:          auth,
+		ReferenceName: plumbing.NewBranchReferenceName(branch),
+		SingleBranch:  true,
+		Depth:         1,
+	})
+	if err != nil {
+		return remoteError(err, remoteURL, "clone")
+	}
+
+	return nil
+}
+
+// authMethod returns the authentication for the remote, asking for the
+// credentials only when the transport makes use of them
+func authMethod(remoteURL string, credentials CredentialsFunc) (transport.AuthMethod, error) {
+	endpoint, err := transport.NewEndpoint(remoteURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid remote url")
+	}
+
+	if credentials == nil || (endpoint.Protocol != "http" && endpoint.Protocol != "https") {
+		return nil, nil
+	}
+
+	username, password, err := credentials()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve credentials")
+	}
+
+	return &http.BasicAuth{Username: username, Password: password}, nil
+}
+
+// remoteError turns the errors of an operation on the remote into messages
+// telling what went wrong
+func remoteError(err error, remoteURL, operation string) error {
+	switch {
+	case err == transport.ErrAuthenticationRequired, err == transport.ErrAuthorizationFailed:
+		return errors.Wrapf(err, "git %s %s rejected, check the credentials", operation, remoteURL)
+	case err == transport.ErrRepositoryNotFound:
+		return errors.Wrapf(err, "git %s failed, %s does not exist", operation, remoteURL)
+	case err == gogit.ErrForceNeeded, strings.HasPrefix(err.Error(), "non-fast-forward update"):
+		return errors.Wrapf(err, "git %s %s rejected, the remote branch was updated concurrently, try again", operation, remoteURL)
+	case strings.HasPrefix(err.Error(), "command error on"), strings.HasPrefix(err.Error(), "unpack error"):
+		return errors.Wrapf(err, "git %s %s rejected by the remote", operation, remoteURL)
+	}
+
+	return errors.Wrapf(err, "git %s %s failed", operation, remoteURL)
+}
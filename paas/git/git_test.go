@@ -0,0 +1,177 @@
+package git_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	"github.com/go-git/go-git/v5/plumbing/transport/file"
+	"github.com/pkg/errors"
+
+	. "github.com/fuseml/fuseml/cli/paas/git"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// hookedTransport serves file:// remotes, running beforePush ahead of the
+// next push
+type hookedTransport struct {
+	transport.Transport
+	beforePush func()
+}
+
+func (t *hookedTransport) NewReceivePackSession(ep *transport.Endpoint, auth transport.AuthMethod) (transport.ReceivePackSession, error) {
+	if t.beforePush != nil {
+		hook := t.beforePush
+		t.beforePush = nil
+		hook()
+	}
+	return t.Transport.NewReceivePackSession(ep, auth)
+}
+
+var _ = Describe("Git", func() {
+	var tmpDir, remote string
+	var hooked *hookedTransport
+
+	noCredentials := func() (string, string, error) {
+		return "", "", errors.New("no credentials")
+	}
+
+	writeFiles := func(files map[string]string) string {
+		dir, err := ioutil.TempDir(tmpDir, "src")
+		Expect(err).ToNot(HaveOccurred())
+		for name, content := range files {
+			path := filepath.Join(dir, name)
+			Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(path, []byte(content), 0644)).To(Succeed())
+		}
+		return dir
+	}
+
+	clone := func() string {
+		dir, err := ioutil.TempDir(tmpDir, "clone")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(Clone(dir, remote, DefaultBranch, noCredentials)).To(Succeed())
+		return dir
+	}
+
+	history := func() []string {
+		repo, err := gogit.PlainOpen(remote)
+		Expect(err).ToNot(HaveOccurred())
+		ref, err := repo.Reference(plumbing.NewBranchReferenceName(DefaultBranch), true)
+		Expect(err).ToNot(HaveOccurred())
+		commits, err := repo.Log(&gogit.LogOptions{From: ref.Hash()})
+		Expect(err).ToNot(HaveOccurred())
+		messages := []string{}
+		Expect(commits.ForEach(func(c *object.Commit) error {
+			messages = append(messages, c.Message)
+			return nil
+		})).To(Succeed())
+		return messages
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "fuseml-git")
+		Expect(err).ToNot(HaveOccurred())
+
+		remote = filepath.Join(tmpDir, "remote.git")
+		repo, err := gogit.PlainInit(remote, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD,
+			plumbing.NewBranchReferenceName(DefaultBranch)))).To(Succeed())
+
+		hooked = &hookedTransport{Transport: file.DefaultClient}
+		client.InstallProtocol("file", hooked)
+	})
+
+	AfterEach(func() {
+		client.InstallProtocol("file", nil)
+		os.RemoveAll(tmpDir)
+	})
+
+	Describe("Push", func() {
+		It("creates the branch of an empty remote", func() {
+			src := writeFiles(map[string]string{"MLproject": "name: app", "code/train.py": "print(1)"})
+			Expect(Push(src, remote, DefaultBranch, "first", noCredentials)).To(Succeed())
+
+			dir := clone()
+			content, err := ioutil.ReadFile(filepath.Join(dir, "code", "train.py"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("print(1)"))
+			Expect(history()).To(Equal([]string{"first"}))
+		})
+
+		It("commits on top of the remote branch", func() {
+			src := writeFiles(map[string]string{"MLproject": "name: app", "old.py": "old"})
+			Expect(Push(src, remote, DefaultBranch, "first", nil)).To(Succeed())
+			src = writeFiles(map[string]string{"MLproject": "name: renamed"})
+			Expect(Push(src, remote, DefaultBranch, "second", nil)).To(Succeed())
+
+			dir := clone()
+			content, err := ioutil.ReadFile(filepath.Join(dir, "MLproject"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("name: renamed"))
+			Expect(filepath.Join(dir, "old.py")).ToNot(BeAnExistingFile())
+			Expect(history()).To(Equal([]string{"second", "first"}))
+		})
+
+		It("does not create a commit without changes", func() {
+			files := map[string]string{"MLproject": "name: app"}
+			Expect(Push(writeFiles(files), remote, DefaultBranch, "first", nil)).To(Succeed())
+			Expect(Push(writeFiles(files), remote, DefaultBranch, "second", nil)).To(MatchError(ErrNoChanges))
+			Expect(history()).To(Equal([]string{"first"}))
+		})
+
+		It("fails when there is nothing to push", func() {
+			err := Push(writeFiles(nil), remote, DefaultBranch, "first", nil)
+			Expect(err).To(MatchError("nothing to push"))
+		})
+
+		It("reports a push rejected because of a concurrent update", func() {
+			Expect(Push(writeFiles(map[string]string{"a": "1"}), remote, DefaultBranch, "first", nil)).To(Succeed())
+
+			other := writeFiles(map[string]string{"a": "2"})
+			hooked.beforePush = func() {
+				Expect(Push(other, remote, DefaultBranch, "concurrent", nil)).To(Succeed())
+			}
+
+			err := Push(writeFiles(map[string]string{"a": "3"}), remote, DefaultBranch, "second", nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("rejected, the remote branch was updated concurrently"))
+			Expect(history()).To(Equal([]string{"concurrent", "first"}))
+		})
+
+		It("reports a missing remote repository", func() {
+			err := Push(writeFiles(map[string]string{"a": "1"}), filepath.Join(tmpDir, "missing.git"), DefaultBranch, "first", nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("does not exist"))
+		})
+
+		It("asks for credentials when pushing over http", func() {
+			err := Push(writeFiles(map[string]string{"a": "1"}), "http://127.0.0.1:1/org/app", DefaultBranch, "first", noCredentials)
+			Expect(err).To(MatchError("failed to resolve credentials: no credentials"))
+		})
+	})
+
+	Describe("Clone", func() {
+		It("checks out the branch without history", func() {
+			Expect(Push(writeFiles(map[string]string{"a": "1"}), remote, DefaultBranch, "first", nil)).To(Succeed())
+			Expect(Push(writeFiles(map[string]string{"a": "2"}), remote, DefaultBranch, "second", nil)).To(Succeed())
+
+			dir := clone()
+			repo, err := gogit.PlainOpen(dir)
+			Expect(err).ToNot(HaveOccurred())
+			head, err := repo.Head()
+			Expect(err).ToNot(HaveOccurred())
+			shallow, err := repo.Storer.Shallow()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(shallow).To(Equal([]plumbing.Hash{head.Hash()}))
+		})
+	})
+})
@@ -6,13 +6,26 @@ import (
 )
 
 // NewGiteaClient creates a new gitea client
-// and performs authentication
+// and performs authentication, with the token of the user logged in with
+// fuseml login, or else the admin credentials
 func NewGiteaClient(resolver *Resolver) (*gitea.Client, error) {
 	url, err := resolver.GetGiteaURL()
 	if err != nil {
 		return nil, errors.Wrap(err, "can't get gitea url")
 	}
 
+	_, token, err := resolver.GetGiteaToken()
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		client, err := gitea.NewClient(url, gitea.SetToken(token))
+		if err != nil {
+			return nil, errors.Wrap(err, "gitea client failed")
+		}
+		return client, nil
+	}
+
 	client, err := gitea.NewClient(url)
 	if err != nil {
 		return nil, errors.Wrap(err, "gitea client failed")
@@ -0,0 +1,17 @@
+package gitea
+
+import (
+	"code.gitea.io/sdk/gitea"
+	"github.com/fuseml/fuseml/cli/paas/config"
+)
+
+// Unexported helpers, exported for the tests of package gitea_test
+
+// NewTestHost returns the host talking to Gitea through the client
+func NewTestHost(client *gitea.Client, cfg *config.Config) *Host {
+	return &Host{client: client, resolver: &Resolver{config: cfg}}
+}
+
+const PageSize = pageSize
+
+var TeamRole = teamRole
@@ -0,0 +1,200 @@
+package gitea
+
+import (
+	"net/url"
+	"path"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/fuseml/fuseml/cli/paas/githost"
+	"github.com/pkg/errors"
+)
+
+// Host is the Gitea installed with fuseml, as githost.Host
+type Host struct {
+	client   *gitea.Client
+	resolver *Resolver
+}
+
+var _ githost.Host = &Host{}
+
+// NewHost creates the githost.Host of the installed Gitea, authenticated
+// as the user logged in with fuseml login, or else with its admin
+// credentials
+func NewHost(resolver *Resolver) (*Host, error) {
+	client, err := NewGiteaClient(resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Host{client: client, resolver: resolver}, nil
+}
+
+// Name returns Gitea
+func (h *Host) Name() string {
+	return "Gitea"
+}
+
+// Version returns the version of the Gitea server
+func (h *Host) Version() (string, error) {
+	version, _, err := h.client.ServerVersion()
+	return version, err
+}
+
+// Credentials returns the user logged in and their token, or else the Gitea
+// admin credentials
+func (h *Host) Credentials() (string, string, error) {
+	username, token, err := h.resolver.GetGiteaToken()
+	if err != nil || token != "" {
+		return username, token, err
+	}
+
+	return h.resolver.GetGiteaCredentials()
+}
+
+// RepoURL returns the URL of the repository, without credentials
+func (h *Host) RepoURL(org, repo string) (string, error) {
+	giteaURL, err := h.resolver.GetGiteaURL()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to resolve gitea host")
+	}
+
+	u, err := url.Parse(giteaURL)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse gitea url")
+	}
+
+	u.Path = path.Join(u.Path, org, repo)
+
+	return u.String(), nil
+}
+
+// pageSize is the size of the pages requested from list endpoints, the
+// maximum Gitea answers with by default
+const pageSize = 50
+
+// Orgs returns the names of all orgs, or those of the user logged in
+func (h *Host) Orgs() ([]string, error) {
+	names := []string{}
+
+	for page := 1; ; page++ {
+		var orgs []*gitea.Organization
+		var err error
+		options := gitea.ListOptions{Page: page, PageSize: pageSize}
+		if h.resolver.config.GiteaToken != "" {
+			orgs, _, err = h.client.ListMyOrgs(gitea.ListOrgsOptions{ListOptions: options})
+		} else {
+			orgs, _, err = h.client.AdminListOrgs(gitea.AdminListOrgsOptions{ListOptions: options})
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(orgs) == 0 {
+			return names, nil
+		}
+
+		for _, org := range orgs {
+			names = append(names, org.UserName)
+		}
+	}
+}
+
+// OrgExists tells whether the org exists
+func (h *Host) OrgExists(org string) (bool, error) {
+	_, resp, err := h.client.GetOrg(org)
+	if resp == nil && err != nil {
+		return false, errors.Wrap(err, "failed to make get org request")
+	}
+
+	return resp.StatusCode == 200, nil
+}
+
+// CreateOrg creates the org
+func (h *Host) CreateOrg(org string) error {
+	_, _, err := h.client.CreateOrg(gitea.CreateOrgOption{
+		Name: org,
+	})
+
+	return err
+}
+
+// Repos returns the names of the repositories of the org
+func (h *Host) Repos(org string) ([]string, error) {
+	names := []string{}
+
+	for page := 1; ; page++ {
+		repos, _, err := h.client.ListOrgRepos(org, gitea.ListOrgReposOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: pageSize},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(repos) == 0 {
+			return names, nil
+		}
+
+		for _, repo := range repos {
+			names = append(names, repo.Name)
+		}
+	}
+}
+
+// RepoExists tells whether the org has the repository
+func (h *Host) RepoExists(org, repo string) (bool, error) {
+	_, resp, err := h.client.GetRepo(org, repo)
+	if resp == nil && err != nil {
+		return false, errors.Wrap(err, "failed to make get repo request")
+	}
+
+	return resp.StatusCode == 200, nil
+}
+
+// CreateRepo creates a private repository, initialized on branch
+func (h *Host) CreateRepo(org, repo, branch string) error {
+	_, _, err := h.client.CreateOrgRepo(org, gitea.CreateRepoOption{
+		Name:          repo,
+		AutoInit:      true,
+		Private:       true,
+		DefaultBranch: branch,
+	})
+
+	return err
+}
+
+// DeleteRepo removes the repository
+func (h *Host) DeleteRepo(org, repo string) error {
+	_, err := h.client.DeleteRepo(org, repo)
+	return err
+}
+
+// HasWebhook tells whether the repository calls url on pushes
+func (h *Host) HasWebhook(org, repo, url string) (bool, error) {
+	hooks, _, err := h.client.ListRepoHooks(org, repo, gitea.ListHooksOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	for _, hook := range hooks {
+		if hook.Config["url"] == url {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// CreateWebhook makes the repository call url on pushes to any branch
+func (h *Host) CreateWebhook(org, repo, url, secret string) error {
+	_, _, err := h.client.CreateRepoHook(org, repo, gitea.CreateHookOption{
+		Active:       true,
+		BranchFilter: "*",
+		Config: map[string]string{
+			"secret":       secret,
+			"http_method":  "POST",
+			"url":          url,
+			"content_type": "json",
+		},
+		Type: "gitea",
+	})
+
+	return err
+}
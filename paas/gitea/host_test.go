@@ -0,0 +1,128 @@
+package gitea_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/fuseml/fuseml/cli/paas/config"
+	. "github.com/fuseml/fuseml/cli/paas/gitea"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// standIn is a minimal Gitea API, holding orgs and their repositories. It
+// pages its lists as Gitea does, answering an empty page past the end.
+type standIn struct {
+	mu       sync.Mutex
+	orgs     []string
+	repos    map[string][]string
+	requests []string
+}
+
+func (s *standIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1")
+	s.requests = append(s.requests, path)
+
+	reply := func(value interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(value)
+	}
+	page := func(names []string) []map[string]string {
+		number, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		items := []map[string]string{}
+		for i := (number - 1) * limit; i >= 0 && i < number*limit && i < len(names); i++ {
+			items = append(items, map[string]string{"username": names[i], "name": names[i]})
+		}
+		return items
+	}
+
+	switch {
+	case path == "/version":
+		reply(map[string]string{"version": "1.13.0"})
+	case path == "/admin/orgs" || path == "/user/orgs":
+		reply(page(s.orgs))
+	case strings.HasPrefix(path, "/orgs/") && strings.HasSuffix(path, "/repos"):
+		reply(page(s.repos[strings.TrimSuffix(strings.TrimPrefix(path, "/orgs/"), "/repos")]))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		reply(map[string]string{"message": "Not Found"})
+	}
+}
+
+func names(prefix string, n int) []string {
+	result := []string{}
+	for i := 0; i < n; i++ {
+		result = append(result, fmt.Sprintf("%s%d", prefix, i))
+	}
+	return result
+}
+
+var _ = Describe("Host", func() {
+	var (
+		api    *standIn
+		server *httptest.Server
+		cfg    *config.Config
+	)
+
+	newHost := func() *Host {
+		client, err := gitea.NewClient(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		return NewTestHost(client, cfg)
+	}
+
+	BeforeEach(func() {
+		api = &standIn{
+			orgs:  names("org", 2*PageSize+1),
+			repos: map[string][]string{"workspace": names("app", PageSize+3)},
+		}
+		server = httptest.NewServer(api)
+		cfg = &config.Config{}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("Orgs", func() {
+		It("returns the orgs of all pages", func() {
+			orgs, err := newHost().Orgs()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(orgs).To(Equal(api.orgs))
+			Expect(api.requests).To(ContainElement("/admin/orgs"))
+		})
+
+		It("returns the orgs of the user logged in", func() {
+			cfg.GiteaToken = "token"
+
+			orgs, err := newHost().Orgs()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(orgs).To(Equal(api.orgs))
+			Expect(api.requests).To(ContainElement("/user/orgs"))
+			Expect(api.requests).ToNot(ContainElement("/admin/orgs"))
+		})
+	})
+
+	Describe("Repos", func() {
+		It("returns the repositories of all pages", func() {
+			repos, err := newHost().Repos("workspace")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(repos).To(Equal(api.repos["workspace"]))
+		})
+
+		It("returns no repositories of an empty org", func() {
+			repos, err := newHost().Repos("empty")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(repos).To(BeEmpty())
+		})
+	})
+})
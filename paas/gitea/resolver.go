@@ -59,7 +59,7 @@ func (r *Resolver) GetGiteaURL() (string, error) {
 		return "", err
 	}
 
-	return fmt.Sprintf("%s://%s", r.config.GiteaProtocol, host), nil
+	return fmt.Sprintf("%s://%s", config.IngressScheme(r.config.GiteaProtocol, r.config.IngressPort), config.IngressHost(host, r.config.IngressPort)), nil
 }
 
 // GetGiteaCredentials resolves Gitea's credentials
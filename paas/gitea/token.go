@@ -0,0 +1,170 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+// tokenRefreshMargin is how long before its expiry an OAuth2 token is
+// refreshed, so that it is still valid for a git push
+const tokenRefreshMargin = time.Minute
+
+// GetGiteaToken returns the user logged in with fuseml login and their
+// token, or empty strings if nobody is. An expired OAuth2 token is
+// refreshed, and the new one saved.
+func (r *Resolver) GetGiteaToken() (string, string, error) {
+	if r.config.GiteaToken == "" {
+		return "", "", nil
+	}
+	if r.config.GiteaRefreshToken == "" {
+		return r.config.GiteaUsername, r.config.GiteaToken, nil
+	}
+
+	token := &oauth2.Token{
+		AccessToken:  r.config.GiteaToken,
+		RefreshToken: r.config.GiteaRefreshToken,
+	}
+	if r.config.GiteaTokenExpiry != "" {
+		expiry, err := time.Parse(time.RFC3339, r.config.GiteaTokenExpiry)
+		if err != nil {
+			return "", "", errors.Wrap(err, "invalid gitea_token_expiry")
+		}
+		if time.Until(expiry) > tokenRefreshMargin {
+			return r.config.GiteaUsername, r.config.GiteaToken, nil
+		}
+	}
+
+	oauthConfig, err := r.OAuth2Config("")
+	if err != nil {
+		return "", "", err
+	}
+	// Marked as expired, for the source to refresh it within the margin
+	token.Expiry = time.Now().Add(-time.Second)
+	token, err = oauthConfig.TokenSource(context.Background(), token).Token()
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to refresh the gitea token, log in again with fuseml login")
+	}
+
+	r.SetOAuth2Token(r.config.GiteaUsername, token)
+	if err := r.config.Save(); err != nil {
+		return "", "", err
+	}
+
+	return r.config.GiteaUsername, r.config.GiteaToken, nil
+}
+
+// SetOAuth2Token keeps the OAuth2 token of the user in the config, without
+// saving it
+func (r *Resolver) SetOAuth2Token(username string, token *oauth2.Token) {
+	r.config.GiteaUsername = username
+	r.config.GiteaToken = token.AccessToken
+	r.config.GiteaTokenName = ""
+	r.config.GiteaRefreshToken = token.RefreshToken
+	r.config.GiteaTokenExpiry = ""
+	if !token.Expiry.IsZero() {
+		r.config.GiteaTokenExpiry = token.Expiry.Format(time.RFC3339)
+	}
+}
+
+// OAuth2Config returns the OAuth2 configuration of the application of the
+// cli registered in Gitea, redirecting to redirectURL
+func (r *Resolver) OAuth2Config(redirectURL string) (*oauth2.Config, error) {
+	if r.config.GiteaOAuth2ClientID == "" {
+		return nil, errors.New("no gitea_oauth2_client_id set, see fuseml config")
+	}
+
+	url, err := r.GetGiteaURL()
+	if err != nil {
+		return nil, errors.Wrap(err, "can't get gitea url")
+	}
+
+	return &oauth2.Config{
+		ClientID:     r.config.GiteaOAuth2ClientID,
+		ClientSecret: r.config.GiteaOAuth2ClientSecret,
+		RedirectURL:  redirectURL,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   url + "/login/oauth/authorize",
+			TokenURL:  url + "/login/oauth/access_token",
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}, nil
+}
+
+// CreateToken creates a personal access token of the user, authenticated
+// with their password and one-time password, if enabled. It returns the
+// name and the value of the token.
+func (r *Resolver) CreateToken(username, password, otp string) (string, string, error) {
+	client, err := r.userClient(username, password, otp)
+	if err != nil {
+		return "", "", err
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	name := fmt.Sprintf("fuseml-%s-%d", hostname, time.Now().Unix())
+
+	token, _, err := client.CreateAccessToken(gitea.CreateAccessTokenOption{Name: name})
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to create gitea access token")
+	}
+
+	return name, token.Token, nil
+}
+
+// DeleteToken deletes the personal access token of the user, authenticated
+// with their password and one-time password, if enabled
+func (r *Resolver) DeleteToken(username, password, otp, name string) error {
+	client, err := r.userClient(username, password, otp)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DeleteAccessToken(name)
+
+	return errors.Wrap(err, "failed to delete gitea access token")
+}
+
+// TokenUser returns the name of the user owning the token
+func (r *Resolver) TokenUser(token string) (string, error) {
+	url, err := r.GetGiteaURL()
+	if err != nil {
+		return "", errors.Wrap(err, "can't get gitea url")
+	}
+
+	client, err := gitea.NewClient(url, gitea.SetToken(token))
+	if err != nil {
+		return "", errors.Wrap(err, "gitea client failed")
+	}
+
+	user, _, err := client.GetMyUserInfo()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the gitea user of the token")
+	}
+
+	return user.UserName, nil
+}
+
+func (r *Resolver) userClient(username, password, otp string) (*gitea.Client, error) {
+	url, err := r.GetGiteaURL()
+	if err != nil {
+		return nil, errors.Wrap(err, "can't get gitea url")
+	}
+
+	client, err := gitea.NewClient(url, gitea.SetBasicAuth(username, password))
+	if err != nil {
+		return nil, errors.Wrap(err, "gitea client failed")
+	}
+	if otp != "" {
+		client.SetOTP(otp)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,184 @@
+package gitea_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	"github.com/fuseml/fuseml/cli/paas/config"
+	. "github.com/fuseml/fuseml/cli/paas/gitea"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+)
+
+// giteaIngress is a minimal Kubernetes API, holding the ingress of Gitea
+// at host
+func giteaIngress(host string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/apis/extensions/v1beta1/namespaces/gitea/ingresses" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(metav1.Status{
+				TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+				Status:   metav1.StatusFailure,
+				Reason:   metav1.StatusReasonNotFound,
+				Code:     http.StatusNotFound,
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(v1beta1.IngressList{
+			TypeMeta: metav1.TypeMeta{Kind: "IngressList", APIVersion: "extensions/v1beta1"},
+			Items: []v1beta1.Ingress{{
+				Spec: v1beta1.IngressSpec{Rules: []v1beta1.IngressRule{{Host: host}}},
+			}},
+		})
+	})
+}
+
+var _ = Describe("GetGiteaToken", func() {
+	var (
+		dir       string
+		refreshes int
+		refused   bool
+		gitea     *httptest.Server
+		kube      *httptest.Server
+		cfg       *config.Config
+		resolver  *Resolver
+	)
+
+	load := func() *config.Config {
+		cfg, err := config.Load(nil)
+		Expect(err).ToNot(HaveOccurred())
+		return cfg
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "fuseml-config")
+		Expect(err).ToNot(HaveOccurred())
+		viper.Set("config-file", filepath.Join(dir, "config.yaml"))
+		viper.Set("context", "")
+
+		refreshes, refused = 0, false
+		gitea = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/login/oauth/access_token"))
+			Expect(r.ParseForm()).To(Succeed())
+			Expect(r.PostForm.Get("grant_type")).To(Equal("refresh_token"))
+			Expect(r.PostForm.Get("refresh_token")).To(Equal("refr3sh"))
+			Expect(r.PostForm.Get("client_id")).To(Equal("fuseml-cli"))
+			refreshes++
+
+			w.Header().Set("Content-Type", "application/json")
+			if refused {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":"invalid_grant"}`)
+				return
+			}
+			fmt.Fprint(w, `{"access_token":"n3w","refresh_token":"n3wrefr3sh","token_type":"bearer","expires_in":3600}`)
+		}))
+
+		giteaURL, err := url.Parse(gitea.URL)
+		Expect(err).ToNot(HaveOccurred())
+		kube = httptest.NewServer(giteaIngress(giteaURL.Hostname()))
+
+		restConfig := &restclient.Config{Host: kube.URL}
+		clientset, err := k8s.NewForConfig(restConfig)
+		Expect(err).ToNot(HaveOccurred())
+
+		cfg = load()
+		cfg.GiteaProtocol = "http"
+		cfg.IngressPort, err = strconv.Atoi(giteaURL.Port())
+		Expect(err).ToNot(HaveOccurred())
+		cfg.GiteaOAuth2ClientID = "fuseml-cli"
+		cfg.GiteaUsername = "dev"
+		cfg.GiteaToken = "t0ken"
+		cfg.GiteaRefreshToken = "refr3sh"
+		resolver = NewResolver(cfg, &kubernetes.Cluster{Kubectl: clientset, RestConfig: restConfig})
+	})
+
+	AfterEach(func() {
+		gitea.Close()
+		kube.Close()
+		os.RemoveAll(dir)
+	})
+
+	It("returns nothing without user logged in", func() {
+		cfg.GiteaToken = ""
+
+		username, token, err := resolver.GetGiteaToken()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(username).To(BeEmpty())
+		Expect(token).To(BeEmpty())
+	})
+
+	It("returns personal access tokens as they are", func() {
+		cfg.GiteaRefreshToken = ""
+
+		username, token, err := resolver.GetGiteaToken()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(username).To(Equal("dev"))
+		Expect(token).To(Equal("t0ken"))
+		Expect(refreshes).To(BeZero())
+	})
+
+	It("fails on an invalid expiry", func() {
+		cfg.GiteaTokenExpiry = "tomorrow"
+
+		_, _, err := resolver.GetGiteaToken()
+		Expect(err).To(MatchError(ContainSubstring("invalid gitea_token_expiry")))
+	})
+
+	It("returns OAuth2 tokens which do not expire soon", func() {
+		cfg.GiteaTokenExpiry = time.Now().Add(time.Hour).Format(time.RFC3339)
+
+		_, token, err := resolver.GetGiteaToken()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).To(Equal("t0ken"))
+		Expect(refreshes).To(BeZero())
+	})
+
+	It("refreshes OAuth2 tokens within the margin of their expiry, and saves them", func() {
+		cfg.GiteaTokenExpiry = time.Now().Add(30 * time.Second).Format(time.RFC3339)
+
+		username, token, err := resolver.GetGiteaToken()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(username).To(Equal("dev"))
+		Expect(token).To(Equal("n3w"))
+		Expect(refreshes).To(Equal(1))
+
+		saved := load()
+		Expect(saved.GiteaToken).To(Equal("n3w"))
+		Expect(saved.GiteaRefreshToken).To(Equal("n3wrefr3sh"))
+		expiry, err := time.Parse(time.RFC3339, saved.GiteaTokenExpiry)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expiry).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+	})
+
+	It("refreshes OAuth2 tokens without expiry", func() {
+		_, token, err := resolver.GetGiteaToken()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).To(Equal("n3w"))
+		Expect(refreshes).To(Equal(1))
+	})
+
+	It("asks to log in again when the refresh is refused", func() {
+		refused = true
+
+		_, _, err := resolver.GetGiteaToken()
+		Expect(err).To(MatchError(ContainSubstring("log in again with fuseml login")))
+		Expect(load().GiteaToken).To(BeEmpty())
+	})
+})
@@ -0,0 +1,219 @@
+package gitea
+
+import (
+	"net/http"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/fuseml/fuseml/cli/paas/githost"
+	"github.com/pkg/errors"
+)
+
+var _ githost.Users = &Host{}
+
+// teamPrefix is the prefix of the names of the teams holding the members
+// of an org, one team per role, e.g. fuseml-write
+const teamPrefix = "fuseml-"
+
+// ownersTeam is the team Gitea creates for the owners of each org
+const ownersTeam = "Owners"
+
+// teamUnits are the parts of the repositories the teams have access to
+var teamUnits = []string{"repo.code", "repo.issues", "repo.ext_issues", "repo.wiki", "repo.pulls", "repo.releases", "repo.ext_wiki"}
+
+// Users returns all users
+func (h *Host) Users() ([]githost.User, error) {
+	result := []githost.User{}
+
+	for page := 1; ; page++ {
+		users, _, err := h.client.AdminListUsers(gitea.AdminListUsersOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: pageSize},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(users) == 0 {
+			return result, nil
+		}
+
+		for _, user := range users {
+			result = append(result, githost.User{
+				Username: user.UserName,
+				FullName: user.FullName,
+				Email:    user.Email,
+				Admin:    user.IsAdmin,
+			})
+		}
+	}
+}
+
+// CreateUser creates the user with the initial password, which they have
+// to change when logging in first
+func (h *Host) CreateUser(user githost.User, password string) error {
+	mustChange := true
+	_, _, err := h.client.AdminCreateUser(gitea.CreateUserOption{
+		Username:           user.Username,
+		FullName:           user.FullName,
+		Email:              user.Email,
+		Password:           password,
+		MustChangePassword: &mustChange,
+	})
+
+	return err
+}
+
+// DeleteUser removes the user
+func (h *Host) DeleteUser(username string) error {
+	_, err := h.client.AdminDeleteUser(username)
+	return err
+}
+
+// Members returns the members of the org, with the owners
+func (h *Host) Members(org string) ([]githost.Member, error) {
+	teams, err := h.teams(org)
+	if err != nil {
+		return nil, err
+	}
+
+	members := []githost.Member{}
+	for _, role := range append([]string{githost.RoleOwner}, githost.Roles...) {
+		team, ok := teams[role]
+		if !ok {
+			continue
+		}
+
+		for page := 1; ; page++ {
+			users, _, err := h.client.ListTeamMembers(team.ID, gitea.ListTeamMembersOptions{
+				ListOptions: gitea.ListOptions{Page: page, PageSize: pageSize},
+			})
+			if err != nil {
+				return nil, err
+			}
+			if len(users) == 0 {
+				break
+			}
+			for _, user := range users {
+				members = append(members, githost.Member{Username: user.UserName, Role: role})
+			}
+		}
+	}
+
+	return members, nil
+}
+
+// AddMember adds the user to the team of the role in the org, creating it
+// if needed, and removes them from the teams of the other roles
+func (h *Host) AddMember(org, username, role string) error {
+	if err := githost.ValidateRole(role); err != nil {
+		return err
+	}
+
+	teams, err := h.teams(org)
+	if err != nil {
+		return err
+	}
+
+	team, ok := teams[role]
+	if !ok {
+		team, _, err = h.client.CreateTeam(org, gitea.CreateTeamOption{
+			Name:                    teamPrefix + role,
+			Description:             "Members of the org with " + role + " access, managed by fuseml",
+			Permission:              gitea.AccessMode(role),
+			CanCreateOrgRepo:        role != githost.RoleRead,
+			IncludesAllRepositories: true,
+			Units:                   teamUnits,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to create the %s team", teamPrefix+role)
+		}
+	}
+
+	if _, err := h.client.AddTeamMember(team.ID, username); err != nil {
+		return errors.Wrapf(err, "failed to add %s to the %s team", username, team.Name)
+	}
+
+	for _, other := range githost.Roles {
+		if other == role {
+			continue
+		}
+		if err := h.removeTeamMember(teams[other], username); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveMember removes the user from the teams of all roles in the org
+func (h *Host) RemoveMember(org, username string) error {
+	teams, err := h.teams(org)
+	if err != nil {
+		return err
+	}
+
+	for _, role := range githost.Roles {
+		if err := h.removeTeamMember(teams[role], username); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// teams returns the teams of the org by role: the owners and the teams
+// managed by fuseml
+func (h *Host) teams(org string) (map[string]*gitea.Team, error) {
+	result := map[string]*gitea.Team{}
+
+	for page := 1; ; page++ {
+		teams, _, err := h.client.ListOrgTeams(org, gitea.ListTeamsOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: pageSize},
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list the teams of %s", org)
+		}
+		if len(teams) == 0 {
+			return result, nil
+		}
+
+		for _, team := range teams {
+			if role, ok := teamRole(team.Name); ok {
+				result[role] = team
+			}
+		}
+	}
+}
+
+// teamRole returns the role of the members of the team, if it is the owners
+// team or one managed by fuseml
+func teamRole(name string) (string, bool) {
+	if name == ownersTeam {
+		return githost.RoleOwner, true
+	}
+	for _, role := range githost.Roles {
+		if name == teamPrefix+role {
+			return role, true
+		}
+	}
+
+	return "", false
+}
+
+// removeTeamMember removes the user from the team, if it exists and they
+// are a member
+func (h *Host) removeTeamMember(team *gitea.Team, username string) error {
+	if team == nil {
+		return nil
+	}
+
+	_, resp, err := h.client.GetTeamMember(team.ID, username)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the members of the %s team", team.Name)
+	}
+
+	_, err = h.client.RemoveTeamMember(team.ID, username)
+
+	return errors.Wrapf(err, "failed to remove %s from the %s team", username, team.Name)
+}
@@ -0,0 +1,236 @@
+package gitea_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/fuseml/fuseml/cli/paas/config"
+	. "github.com/fuseml/fuseml/cli/paas/gitea"
+	"github.com/fuseml/fuseml/cli/paas/githost"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+// teamsStandIn is a minimal Gitea API, holding the teams of a single org and
+// their members. It pages its lists as Gitea does.
+type teamsStandIn struct {
+	mu       sync.Mutex
+	teams    []*gitea.Team
+	members  map[int64][]string
+	requests []string
+}
+
+func (s *teamsStandIn) team(name string) *gitea.Team {
+	for _, team := range s.teams {
+		if team.Name == name {
+			return team
+		}
+	}
+	return nil
+}
+
+func (s *teamsStandIn) add(name string, members ...string) {
+	team := &gitea.Team{ID: int64(len(s.teams) + 1), Name: name}
+	s.teams = append(s.teams, team)
+	s.members[team.ID] = members
+}
+
+func (s *teamsStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1")
+	s.requests = append(s.requests, r.Method+" "+path)
+
+	reply := func(status int, value interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(value)
+	}
+	page := func(n int) (int, int) {
+		number, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		from, to := (number-1)*limit, number*limit
+		if from > n {
+			from = n
+		}
+		if to > n {
+			to = n
+		}
+		return from, to
+	}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case path == "/version":
+		reply(http.StatusOK, map[string]string{"version": "1.13.0"})
+
+	case path == "/orgs/workspace/teams" && r.Method == http.MethodGet:
+		from, to := page(len(s.teams))
+		reply(http.StatusOK, s.teams[from:to])
+
+	case path == "/orgs/workspace/teams" && r.Method == http.MethodPost:
+		var opt gitea.CreateTeamOption
+		json.NewDecoder(r.Body).Decode(&opt)
+		s.add(opt.Name)
+		reply(http.StatusCreated, s.teams[len(s.teams)-1])
+
+	case len(parts) >= 3 && parts[0] == "teams" && parts[2] == "members":
+		id, _ := strconv.ParseInt(parts[1], 10, 64)
+		members, ok := s.members[id]
+		if !ok {
+			reply(http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+
+		if len(parts) == 3 {
+			from, to := page(len(members))
+			users := []gitea.User{}
+			for _, name := range members[from:to] {
+				users = append(users, gitea.User{UserName: name})
+			}
+			reply(http.StatusOK, users)
+			return
+		}
+
+		username := parts[3]
+		index := -1
+		for i, name := range members {
+			if name == username {
+				index = i
+			}
+		}
+		switch r.Method {
+		case http.MethodGet:
+			if index < 0 {
+				reply(http.StatusNotFound, map[string]string{"message": "Not Found"})
+				return
+			}
+			reply(http.StatusOK, gitea.User{UserName: username})
+		case http.MethodPut:
+			if index < 0 {
+				s.members[id] = append(members, username)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			if index >= 0 {
+				s.members[id] = append(members[:index:index], members[index+1:]...)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+
+	default:
+		reply(http.StatusNotFound, map[string]string{"message": "Not Found"})
+	}
+}
+
+var _ = Describe("Users", func() {
+	var (
+		api    *teamsStandIn
+		server *httptest.Server
+		host   *Host
+	)
+
+	BeforeEach(func() {
+		api = &teamsStandIn{members: map[int64][]string{}}
+		// Teams not managed by fuseml fill the first page
+		for _, name := range names("other", PageSize) {
+			api.add(name, "mallory")
+		}
+		api.add("Owners", "root")
+		api.add("fuseml-read", "alice")
+		api.add("fuseml-write", names("dev", PageSize+1)...)
+
+		server = httptest.NewServer(api)
+		client, err := gitea.NewClient(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		host = NewTestHost(client, &config.Config{})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	DescribeTable("TeamRole",
+		func(name, role string, ok bool) {
+			r, found := TeamRole(name)
+			Expect(found).To(Equal(ok))
+			Expect(r).To(Equal(role))
+		},
+		Entry("owners", "Owners", githost.RoleOwner, true),
+		Entry("read", "fuseml-read", githost.RoleRead, true),
+		Entry("write", "fuseml-write", githost.RoleWrite, true),
+		Entry("admin", "fuseml-admin", githost.RoleAdmin, true),
+		Entry("not managed", "developers", "", false),
+		Entry("unknown role", "fuseml-maintain", "", false),
+		Entry("owner is not a managed role", "fuseml-owner", "", false),
+	)
+
+	Describe("Members", func() {
+		It("returns the members of all teams and pages, by role", func() {
+			members, err := host.Members("workspace")
+			Expect(err).ToNot(HaveOccurred())
+
+			expected := []githost.Member{
+				{Username: "root", Role: githost.RoleOwner},
+				{Username: "alice", Role: githost.RoleRead},
+			}
+			for _, name := range names("dev", PageSize+1) {
+				expected = append(expected, githost.Member{Username: name, Role: githost.RoleWrite})
+			}
+			Expect(members).To(Equal(expected))
+		})
+	})
+
+	Describe("AddMember", func() {
+		It("rejects unknown roles", func() {
+			err := host.AddMember("workspace", "bob", githost.RoleOwner)
+			Expect(err).To(MatchError(ContainSubstring("unknown role 'owner'")))
+			Expect(api.requests).ToNot(ContainElement(ContainSubstring("/teams")))
+		})
+
+		It("adds the user to the team of the role", func() {
+			Expect(host.AddMember("workspace", "bob", githost.RoleWrite)).To(Succeed())
+			Expect(api.members[api.team("fuseml-write").ID]).To(ContainElement("bob"))
+			Expect(api.requests).ToNot(ContainElement("POST /orgs/workspace/teams"))
+		})
+
+		It("creates the team of the role on a later page", func() {
+			Expect(host.AddMember("workspace", "bob", githost.RoleAdmin)).To(Succeed())
+			Expect(api.requests).To(ContainElement("POST /orgs/workspace/teams"))
+			Expect(api.team("fuseml-admin")).ToNot(BeNil())
+			Expect(api.members[api.team("fuseml-admin").ID]).To(Equal([]string{"bob"}))
+		})
+
+		It("moves the user from the team of their former role", func() {
+			Expect(host.AddMember("workspace", "alice", githost.RoleWrite)).To(Succeed())
+			Expect(api.members[api.team("fuseml-read").ID]).To(BeEmpty())
+			Expect(api.members[api.team("fuseml-write").ID]).To(ContainElement("alice"))
+			Expect(api.members[api.team("Owners").ID]).To(Equal([]string{"root"}))
+			Expect(api.members[api.team("other0").ID]).To(Equal([]string{"mallory"}))
+		})
+
+		It("does not create the teams of the other roles", func() {
+			Expect(host.AddMember("workspace", "alice", githost.RoleWrite)).To(Succeed())
+			Expect(api.team("fuseml-admin")).To(BeNil())
+		})
+	})
+
+	Describe("RemoveMember", func() {
+		It("removes the user from the teams of all roles", func() {
+			Expect(host.AddMember("workspace", "bob", githost.RoleAdmin)).To(Succeed())
+			Expect(host.RemoveMember("workspace", "alice")).To(Succeed())
+			Expect(host.RemoveMember("workspace", "bob")).To(Succeed())
+
+			for _, role := range githost.Roles {
+				Expect(api.members[api.team("fuseml-"+role).ID]).ToNot(ContainElement(Or(Equal("alice"), Equal("bob"))))
+			}
+		})
+	})
+})
@@ -0,0 +1,30 @@
+package paas
+
+import (
+	"github.com/fuseml/fuseml/cli/paas/config"
+	"github.com/fuseml/fuseml/cli/paas/gitea"
+	"github.com/fuseml/fuseml/cli/paas/githost"
+	"github.com/fuseml/fuseml/cli/paas/gitlab"
+)
+
+// NewGitHost creates the git hosting service selected by the git_provider
+// setting: the Gitea installed with fuseml, or an existing GitLab
+func NewGitHost(config *config.Config, resolver *gitea.Resolver) (githost.Host, error) {
+	if err := githost.ValidateProvider(config.GitProvider); err != nil {
+		return nil, err
+	}
+
+	if config.GitProvider == githost.GitLab {
+		host, err := gitlab.New(config.GitlabURL, config.GitlabToken)
+		if err != nil {
+			return nil, err
+		}
+		return host, nil
+	}
+
+	host, err := gitea.NewHost(resolver)
+	if err != nil {
+		return nil, err
+	}
+	return host, nil
+}
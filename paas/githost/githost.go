@@ -0,0 +1,121 @@
+// Package githost abstracts the git hosting service keeping the code of
+// fuseml applications. Each org holds the repositories of its applications,
+// whose webhooks trigger the pipelines.
+package githost
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Gitea is the git hosting service installed with fuseml, the default
+	Gitea = "gitea"
+	// GitLab is an existing GitLab instance, e.g. gitlab.com
+	GitLab = "gitlab"
+)
+
+// Host is a git hosting service
+type Host interface {
+	// Name returns the name of the service, for display
+	Name() string
+	// Version returns the version of the service
+	Version() (string, error)
+	// Credentials returns the username and password used for git over http
+	Credentials() (string, string, error)
+	// RepoURL returns the URL of the repository, without credentials
+	RepoURL(org, repo string) (string, error)
+
+	// Orgs returns the names of all orgs
+	Orgs() ([]string, error)
+	// OrgExists tells whether the org exists
+	OrgExists(org string) (bool, error)
+	// CreateOrg creates the org
+	CreateOrg(org string) error
+
+	// Repos returns the names of the repositories of the org
+	Repos(org string) ([]string, error)
+	// RepoExists tells whether the org has the repository
+	RepoExists(org, repo string) (bool, error)
+	// CreateRepo creates a private repository, initialized with a commit on
+	// branch
+	CreateRepo(org, repo, branch string) error
+	// DeleteRepo removes the repository
+	DeleteRepo(org, repo string) error
+
+	// HasWebhook tells whether the repository calls url on pushes
+	HasWebhook(org, repo, url string) (bool, error)
+	// CreateWebhook makes the repository call url on pushes, authenticated
+	// with secret
+	CreateWebhook(org, repo, url, secret string) error
+}
+
+// ValidateProvider checks that provider names a supported git hosting service
+func ValidateProvider(provider string) error {
+	switch provider {
+	case Gitea, GitLab:
+		return nil
+	}
+
+	return errors.Errorf("unknown git provider '%s', expected %s or %s", provider, Gitea, GitLab)
+}
+
+// The roles of org members, giving read, write or admin access to all
+// repositories of the org
+const (
+	RoleRead  = "read"
+	RoleWrite = "write"
+	RoleAdmin = "admin"
+	// RoleOwner is the role of the owners of the org, who are not managed
+	// as members
+	RoleOwner = "owner"
+)
+
+// Roles are the roles members can be given
+var Roles = []string{RoleRead, RoleWrite, RoleAdmin}
+
+// User is a user of the git hosting service
+type User struct {
+	Username string
+	FullName string
+	Email    string
+	Admin    bool
+}
+
+// Member is a member of an org, with their role
+type Member struct {
+	Username string
+	Role     string
+}
+
+// Users is implemented by the git hosting services whose users and org
+// members can be managed by fuseml
+type Users interface {
+	// Users returns all users
+	Users() ([]User, error)
+	// CreateUser creates the user with the initial password, which they
+	// have to change when logging in first
+	CreateUser(user User, password string) error
+	// DeleteUser removes the user
+	DeleteUser(username string) error
+
+	// Members returns the members of the org, with the owners
+	Members(org string) ([]Member, error)
+	// AddMember adds the user to the org with the role, or changes their
+	// role
+	AddMember(org, username, role string) error
+	// RemoveMember removes the user from the org
+	RemoveMember(org, username string) error
+}
+
+// ValidateRole checks that role can be given to members
+func ValidateRole(role string) error {
+	for _, r := range Roles {
+		if role == r {
+			return nil
+		}
+	}
+
+	return errors.Errorf("unknown role '%s', expected %s", role, strings.Join(Roles, ", "))
+}
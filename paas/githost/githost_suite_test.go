@@ -0,0 +1,13 @@
+package githost_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestGithost(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Githost Suite")
+}
@@ -0,0 +1,29 @@
+package githost_test
+
+import (
+	. "github.com/fuseml/fuseml/cli/paas/githost"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValidateRole", func() {
+	DescribeTable("accepts the roles members can be given",
+		func(role string) {
+			Expect(ValidateRole(role)).To(Succeed())
+		},
+		Entry("read", RoleRead),
+		Entry("write", RoleWrite),
+		Entry("admin", RoleAdmin),
+	)
+
+	DescribeTable("rejects other roles",
+		func(role string) {
+			Expect(ValidateRole(role)).To(MatchError("unknown role '" + role + "', expected read, write, admin"))
+		},
+		Entry("owner, which is not managed", RoleOwner),
+		Entry("empty", ""),
+		Entry("other case", "Write"),
+		Entry("unknown", "maintainer"),
+	)
+})
@@ -0,0 +1,340 @@
+// Package gitlab implements githost.Host against the REST API of GitLab, so
+// that existing GitLab groups and projects can hold fuseml applications.
+// Orgs are GitLab groups, repositories are the projects of a group.
+package gitlab
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/fuseml/fuseml/cli/paas/githost"
+	"github.com/pkg/errors"
+)
+
+// tokenUser is the username git accepts for access tokens over http
+const tokenUser = "oauth2"
+
+// perPage is the size of the pages requested from list endpoints
+const perPage = 100
+
+// Client talks to a GitLab instance, authenticated with a personal or group
+// access token with the api scope
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+var _ githost.Host = &Client{}
+
+// New creates a client for the GitLab instance at baseURL, e.g.
+// https://gitlab.com
+func New(baseURL, token string) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.Errorf("invalid gitlab url '%s'", baseURL)
+	}
+	if token == "" {
+		return nil, errors.New("a gitlab access token is required")
+	}
+
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		http:    http.DefaultClient,
+	}, nil
+}
+
+// Name returns GitLab
+func (c *Client) Name() string {
+	return "GitLab"
+}
+
+// Version returns the version of the GitLab instance
+func (c *Client) Version() (string, error) {
+	var result struct {
+		Version string `json:"version"`
+	}
+	if _, err := c.do("GET", "/version", nil, &result); err != nil {
+		return "", err
+	}
+
+	return result.Version, nil
+}
+
+// Credentials returns the access token, as git password
+func (c *Client) Credentials() (string, string, error) {
+	return tokenUser, c.token, nil
+}
+
+// RepoURL returns the http URL of the project, without credentials
+func (c *Client) RepoURL(org, repo string) (string, error) {
+	return fmt.Sprintf("%s/%s/%s.git", c.baseURL, org, repo), nil
+}
+
+// Orgs returns the full paths of the groups the token has access to
+func (c *Client) Orgs() ([]string, error) {
+	var groups []struct {
+		FullPath string `json:"full_path"`
+	}
+	if err := c.list("/groups", &groups); err != nil {
+		return nil, errors.Wrap(err, "failed to list gitlab groups")
+	}
+
+	names := []string{}
+	for _, group := range groups {
+		names = append(names, group.FullPath)
+	}
+
+	return names, nil
+}
+
+// OrgExists tells whether the group exists
+func (c *Client) OrgExists(org string) (bool, error) {
+	return c.exists("/groups/" + url.PathEscape(org))
+}
+
+// CreateOrg creates a private top level group
+func (c *Client) CreateOrg(org string) error {
+	_, err := c.do("POST", "/groups", map[string]interface{}{
+		"name":       org,
+		"path":       org,
+		"visibility": "private",
+	}, nil)
+
+	return errors.Wrapf(err, "failed to create gitlab group '%s'", org)
+}
+
+// Repos returns the paths of the projects of the group
+func (c *Client) Repos(org string) ([]string, error) {
+	var projects []struct {
+		Path string `json:"path"`
+	}
+	if err := c.list("/groups/"+url.PathEscape(org)+"/projects", &projects); err != nil {
+		return nil, errors.Wrapf(err, "failed to list the projects of gitlab group '%s'", org)
+	}
+
+	names := []string{}
+	for _, project := range projects {
+		names = append(names, project.Path)
+	}
+
+	return names, nil
+}
+
+// RepoExists tells whether the group has the project
+func (c *Client) RepoExists(org, repo string) (bool, error) {
+	return c.exists(projectPath(org, repo))
+}
+
+// CreateRepo creates a private project in the group, initialized with a
+// readme on branch
+func (c *Client) CreateRepo(org, repo, branch string) error {
+	var group struct {
+		ID int `json:"id"`
+	}
+	if _, err := c.do("GET", "/groups/"+url.PathEscape(org), nil, &group); err != nil {
+		return errors.Wrapf(err, "failed to find gitlab group '%s'", org)
+	}
+
+	_, err := c.do("POST", "/projects", map[string]interface{}{
+		"name":                   repo,
+		"path":                   repo,
+		"namespace_id":           group.ID,
+		"visibility":             "private",
+		"initialize_with_readme": true,
+		"default_branch":         branch,
+	}, nil)
+
+	return errors.Wrapf(err, "failed to create gitlab project '%s/%s'", org, repo)
+}
+
+// DeleteRepo removes the project
+func (c *Client) DeleteRepo(org, repo string) error {
+	_, err := c.do("DELETE", projectPath(org, repo), nil, nil)
+	return errors.Wrapf(err, "failed to delete gitlab project '%s/%s'", org, repo)
+}
+
+// HasWebhook tells whether the project calls url on pushes
+func (c *Client) HasWebhook(org, repo, hookURL string) (bool, error) {
+	var hooks []struct {
+		URL        string `json:"url"`
+		PushEvents bool   `json:"push_events"`
+	}
+	if err := c.list(projectPath(org, repo)+"/hooks", &hooks); err != nil {
+		return false, errors.Wrapf(err, "failed to list the webhooks of gitlab project '%s/%s'", org, repo)
+	}
+
+	for _, hook := range hooks {
+		if hook.URL == hookURL && hook.PushEvents {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// CreateWebhook makes the project call url on pushes, sending secret as its
+// token
+func (c *Client) CreateWebhook(org, repo, hookURL, secret string) error {
+	_, err := c.do("POST", projectPath(org, repo)+"/hooks", map[string]interface{}{
+		"url":                     hookURL,
+		"token":                   secret,
+		"push_events":             true,
+		"enable_ssl_verification": strings.HasPrefix(hookURL, "https://"),
+	}, nil)
+
+	return errors.Wrapf(err, "failed to create webhook of gitlab project '%s/%s'", org, repo)
+}
+
+// projectPath returns the API path of the project, addressed by its
+// url-encoded full path
+func projectPath(org, repo string) string {
+	return "/projects/" + url.PathEscape(org+"/"+repo)
+}
+
+// exists tells whether the API path answers, or is not found
+func (c *Client) exists(path string) (bool, error) {
+	status, err := c.do("GET", path, nil, nil)
+	if status == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// list requests all pages of a list endpoint, following the X-Next-Page
+// header, and decodes the items of all pages into result, a slice pointer
+func (c *Client) list(path string, result interface{}) error {
+	items := []json.RawMessage{}
+
+	for page := "1"; page != ""; {
+		body, header, err := c.request("GET", fmt.Sprintf("%s?per_page=%d&page=%s", path, perPage, page), nil)
+		if err != nil {
+			return err
+		}
+
+		var pageItems []json.RawMessage
+		if err := json.Unmarshal(body, &pageItems); err != nil {
+			return errors.Wrapf(err, "invalid response of gitlab %s", path)
+		}
+		items = append(items, pageItems...)
+
+		page = header.Get("X-Next-Page")
+	}
+
+	all, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(all, result)
+}
+
+// do sends a request with an optional JSON body and decodes the JSON
+// response into result, if given. It returns the status of the response.
+func (c *Client) do(method, path string, body interface{}, result interface{}) (int, error) {
+	data, _, err := c.request(method, path, body)
+	if err != nil {
+		if apiErr, ok := err.(*apiError); ok {
+			return apiErr.status, err
+		}
+		return 0, err
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(data, result); err != nil {
+			return http.StatusOK, errors.Wrapf(err, "invalid response of gitlab %s", path)
+		}
+	}
+
+	return http.StatusOK, nil
+}
+
+// apiError is a response of the GitLab API with an error status
+type apiError struct {
+	method  string
+	path    string
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("gitlab %s %s: %d %s", e.method, e.path, e.status, e.message)
+}
+
+// request sends a request to the API and returns the body of a successful
+// response, with its headers
+func (c *Client) request(method, path string, body interface{}) ([]byte, http.Header, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+"/api/v4"+path, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "gitlab %s %s failed", method, path)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "gitlab %s %s failed", method, path)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, nil, &apiError{
+			method:  method,
+			path:    path,
+			status:  resp.StatusCode,
+			message: errorMessage(data, resp.Status),
+		}
+	}
+
+	return data, resp.Header, nil
+}
+
+// errorMessage returns the message of an error response, which GitLab sends
+// as `message`, sometimes holding a map of problems, or as `error`
+func errorMessage(body []byte, status string) string {
+	var result struct {
+		Message json.RawMessage `json:"message"`
+		Error   string          `json:"error"`
+	}
+	if err := json.Unmarshal(body, &result); err == nil {
+		var message string
+		if err := json.Unmarshal(result.Message, &message); err == nil {
+			return message
+		}
+		if len(result.Message) > 0 {
+			return string(result.Message)
+		}
+		if result.Error != "" {
+			return result.Error
+		}
+	}
+
+	return status
+}
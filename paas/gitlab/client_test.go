@@ -0,0 +1,212 @@
+package gitlab_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	. "github.com/fuseml/fuseml/cli/paas/gitlab"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// standIn is a minimal GitLab API, holding groups, projects and hooks
+type standIn struct {
+	mu       sync.Mutex
+	groups   []string
+	projects map[string][]map[string]interface{}
+}
+
+func (s *standIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Header.Get("PRIVATE-TOKEN") != "token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message":"401 Unauthorized"}`))
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.EscapedPath(), "/api/v4")
+	body := map[string]interface{}{}
+	if r.Body != nil {
+		json.NewDecoder(r.Body).Decode(&body)
+	}
+
+	reply := func(status int, value interface{}) {
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(value)
+	}
+	notFound := func() { reply(http.StatusNotFound, map[string]string{"message": "404 Not Found"}) }
+	groupID := func(name string) int {
+		for i, group := range s.groups {
+			if group == name {
+				return i + 1
+			}
+		}
+		return 0
+	}
+
+	switch {
+	case path == "/version":
+		reply(http.StatusOK, map[string]string{"version": "13.12.0"})
+
+	case path == "/groups" && r.Method == "GET":
+		// One group per page, to exercise the pagination
+		page := 1
+		fmt.Sscanf(r.URL.Query().Get("page"), "%d", &page)
+		if page < len(s.groups) {
+			w.Header().Set("X-Next-Page", fmt.Sprint(page+1))
+		}
+		reply(http.StatusOK, []map[string]string{{"full_path": s.groups[page-1]}})
+
+	case path == "/groups" && r.Method == "POST":
+		s.groups = append(s.groups, body["path"].(string))
+		reply(http.StatusCreated, body)
+
+	case strings.HasPrefix(path, "/groups/") && strings.HasSuffix(path, "/projects"):
+		group := strings.TrimSuffix(strings.TrimPrefix(path, "/groups/"), "/projects")
+		projects := []map[string]string{}
+		for name := range s.projects {
+			if strings.HasPrefix(name, group+"/") {
+				projects = append(projects, map[string]string{"path": strings.TrimPrefix(name, group+"/")})
+			}
+		}
+		reply(http.StatusOK, projects)
+
+	case strings.HasPrefix(path, "/groups/"):
+		id := groupID(strings.TrimPrefix(path, "/groups/"))
+		if id == 0 {
+			notFound()
+			return
+		}
+		reply(http.StatusOK, map[string]int{"id": id})
+
+	case path == "/projects" && r.Method == "POST":
+		id := int(body["namespace_id"].(float64))
+		name := s.groups[id-1] + "/" + body["path"].(string)
+		if _, ok := s.projects[name]; ok {
+			reply(http.StatusBadRequest, map[string]interface{}{"message": map[string][]string{"path": {"has already been taken"}}})
+			return
+		}
+		s.projects[name] = []map[string]interface{}{}
+		reply(http.StatusCreated, body)
+
+	case strings.HasPrefix(path, "/projects/"):
+		parts := strings.SplitN(strings.TrimPrefix(path, "/projects/"), "/", 2)
+		name := strings.Replace(parts[0], "%2F", "/", -1)
+		hooks, ok := s.projects[name]
+		if !ok {
+			notFound()
+			return
+		}
+		switch {
+		case len(parts) == 2 && r.Method == "POST":
+			s.projects[name] = append(hooks, body)
+			reply(http.StatusCreated, body)
+		case len(parts) == 2:
+			reply(http.StatusOK, hooks)
+		case r.Method == "DELETE":
+			delete(s.projects, name)
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			reply(http.StatusOK, map[string]string{"path_with_namespace": name})
+		}
+
+	default:
+		notFound()
+	}
+}
+
+var _ = Describe("Client", func() {
+	var api *standIn
+	var server *httptest.Server
+	var client *Client
+
+	BeforeEach(func() {
+		api = &standIn{
+			groups:   []string{"ml", "data"},
+			projects: map[string][]map[string]interface{}{"ml/iris": {}},
+		}
+		server = httptest.NewServer(api)
+
+		var err error
+		client, err = New(server.URL+"/", "token")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("requires a valid url and a token", func() {
+		_, err := New("gitlab.com", "token")
+		Expect(err).To(MatchError("invalid gitlab url 'gitlab.com'"))
+
+		_, err = New("https://gitlab.com", "")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns the version", func() {
+		Expect(client.Version()).To(Equal("13.12.0"))
+	})
+
+	It("reports API errors with their message", func() {
+		client, err := New(server.URL, "wrong")
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = client.Version()
+		Expect(err).To(MatchError("gitlab GET /version: 401 401 Unauthorized"))
+	})
+
+	It("uses the token as git password", func() {
+		user, password, err := client.Credentials()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(user).To(Equal("oauth2"))
+		Expect(password).To(Equal("token"))
+
+		Expect(client.RepoURL("ml", "iris")).To(Equal(server.URL + "/ml/iris.git"))
+	})
+
+	It("lists groups across pages and creates them", func() {
+		Expect(client.OrgExists("new")).To(BeFalse())
+		Expect(client.CreateOrg("new")).To(Succeed())
+		Expect(client.OrgExists("new")).To(BeTrue())
+
+		Expect(client.Orgs()).To(Equal([]string{"ml", "data", "new"}))
+	})
+
+	It("manages the projects of a group", func() {
+		Expect(client.RepoExists("ml", "iris")).To(BeTrue())
+		Expect(client.RepoExists("ml", "wine")).To(BeFalse())
+
+		Expect(client.CreateRepo("ml", "wine", "main")).To(Succeed())
+		Expect(client.Repos("ml")).To(ConsistOf("iris", "wine"))
+
+		err := client.CreateRepo("ml", "wine", "main")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("has already been taken"))
+
+		Expect(client.DeleteRepo("ml", "iris")).To(Succeed())
+		Expect(client.Repos("ml")).To(ConsistOf("wine"))
+	})
+
+	It("fails to create projects in unknown groups", func() {
+		err := client.CreateRepo("unknown", "wine", "main")
+		Expect(err).To(MatchError(ContainSubstring("failed to find gitlab group 'unknown'")))
+	})
+
+	It("creates push webhooks once", func() {
+		hook := "http://listener.example.com"
+
+		Expect(client.HasWebhook("ml", "iris", hook)).To(BeFalse())
+		Expect(client.CreateWebhook("ml", "iris", hook, "secret")).To(Succeed())
+		Expect(client.HasWebhook("ml", "iris", hook)).To(BeTrue())
+
+		Expect(api.projects["ml/iris"]).To(ConsistOf(HaveKeyWithValue("token", "secret")))
+		Expect(api.projects["ml/iris"][0]).To(HaveKeyWithValue("push_events", true))
+	})
+})
@@ -0,0 +1,314 @@
+package paas
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fuseml/fuseml/cli/deployments"
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	"github.com/fuseml/fuseml/cli/paas/registry"
+	"github.com/pkg/errors"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	registryAPIPort        = 5000
+	registryContainer      = "registry"
+	registryConfigPath     = "/etc/docker/registry/config.yml"
+	appImageRepoPrefix     = "apps/"
+	imageDigestResult      = "IMAGE-DIGEST"
+	imageBuildPipelineTask = "build"
+)
+
+// appImage is a manifest of an app repository in the registry
+type appImage struct {
+	digest   string
+	tags     []string
+	deployed bool
+}
+
+// Images lists the app images stored in the registry, for a single app or
+// for all of them. With prune set, manifests which are neither tagged nor
+// deployed are removed, followed by the registry's blob garbage collection.
+func (c *FusemlClient) Images(app string, prune bool) error {
+	log := c.Log.WithName("Images").WithValues("Application", app)
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	c.ui.Header().Msg("Listing application images")
+
+	if prune {
+		details.Info("check for running builds")
+		if err := c.checkNoBuilds(); err != nil {
+			return err
+		}
+	}
+
+	details.Info("find registry pod")
+	pod, err := c.registryPod()
+	if err != nil {
+		return err
+	}
+
+	details.Info("connect to registry", "Pod", pod)
+	port, stop, err := c.kubeClient.PortForward(deployments.RegistryDeploymentID, pod, registryAPIPort)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to the registry")
+	}
+	defer stop()
+
+	client := registry.NewClient(fmt.Sprintf("http://127.0.0.1:%d", port),
+		deployments.RegistryUsername, deployments.RegistryPassword)
+
+	details.Info("list repositories")
+	repositories, err := client.Catalog()
+	if err != nil {
+		return err
+	}
+
+	details.Info("list deployed images")
+	deployedImages, err := c.deployedImages()
+	if err != nil {
+		return err
+	}
+
+	msg := c.ui.Success().WithTable("Repository", "Tags", "Digest", "Deployed")
+	pruned := 0
+
+	for _, repository := range repositories {
+		if !strings.HasPrefix(repository, appImageRepoPrefix) {
+			continue
+		}
+		appName := strings.TrimPrefix(repository, appImageRepoPrefix)
+		if app != "" && appName != app {
+			continue
+		}
+
+		details.Info("list images", "Repository", repository)
+		images, err := c.appImages(client, repository, appName, deployedImages)
+		if err != nil {
+			return err
+		}
+
+		for _, image := range images {
+			tags := strings.Join(image.tags, ", ")
+			if tags == "" {
+				tags = "<none>"
+			}
+			deployed := ""
+			if image.deployed {
+				deployed = "yes"
+			}
+
+			if prune && len(image.tags) == 0 && !image.deployed {
+				details.Info("delete manifest", "Repository", repository, "Digest", image.digest)
+				err := client.DeleteManifest(repository, image.digest)
+				if err != nil {
+					return err
+				}
+				pruned++
+				deployed = "pruned"
+			}
+
+			msg = msg.WithTableRow(repository, tags, image.digest, deployed)
+		}
+	}
+
+	msg.Msg("Application images:")
+
+	if !prune {
+		return nil
+	}
+
+	// The garbage collection of a live registry removes the layers of the
+	// images being pushed, so check again for builds started meanwhile
+	details.Info("check for running builds")
+	if err := c.checkNoBuilds(); err != nil {
+		return err
+	}
+
+	details.Info("registry garbage-collect")
+	out, stderr, err := c.kubeClient.Exec(deployments.RegistryDeploymentID, pod, registryContainer,
+		"registry garbage-collect "+registryConfigPath, "")
+	if err != nil {
+		c.ui.Problem().
+			WithStringValue("Stdout", out).
+			WithStringValue("Stderr", stderr).
+			Msg("Registry garbage collection failed")
+		return errors.Wrap(err, "failed to run registry garbage collection")
+	}
+	c.ui.Note().V(1).WithStringValue("Output", out).Msg("Registry garbage collection output")
+
+	c.ui.Success().
+		WithIntValue("Manifests pruned", pruned).
+		Msg("Registry garbage collected.")
+
+	return nil
+}
+
+// appImages returns the manifests of an app repository. Kaniko only ever
+// moves the `latest` tag, so the manifests of earlier builds are only known
+// through the IMAGE-DIGEST results of the app's PipelineRuns.
+func (c *FusemlClient) appImages(client *registry.Client, repository, app string, deployedImages map[string]bool) ([]appImage, error) {
+	images := map[string]*appImage{}
+
+	tags, err := client.Tags(repository)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		digest, err := client.Digest(repository, tag)
+		if err != nil {
+			return nil, err
+		}
+		if digest == "" {
+			continue
+		}
+		if images[digest] == nil {
+			images[digest] = &appImage{digest: digest}
+		}
+		images[digest].tags = append(images[digest].tags, tag)
+	}
+
+	runs, err := c.kubeClient.ListPipelineRuns(c.config.FusemlWorkloadsNamespace,
+		fmt.Sprintf("%s=%s", kubernetes.AppNameLabelKey, app))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list PipelineRuns")
+	}
+
+	for _, run := range runs.Items {
+		digest := pipelineRunImageDigest(run)
+		if digest == "" || images[digest] != nil {
+			continue
+		}
+
+		// Skip digests already removed from the registry
+		found, err := client.Digest(repository, digest)
+		if err != nil {
+			return nil, err
+		}
+		if found != "" {
+			images[digest] = &appImage{digest: digest}
+		}
+	}
+
+	result := []appImage{}
+	for digest, image := range images {
+		image.deployed = deployedImages[repository+"@"+digest]
+		result = append(result, *image)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].digest < result[j].digest
+	})
+
+	return result, nil
+}
+
+// deployedImages returns the set of app images referenced by the workloads,
+// in the form `apps/APP@DIGEST`
+func (c *FusemlClient) deployedImages() (map[string]bool, error) {
+	result := map[string]bool{}
+
+	deploymentList, err := c.kubeClient.Kubectl.AppsV1().Deployments(c.config.FusemlWorkloadsNamespace).
+		List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list deployments")
+	}
+
+	for _, deployment := range deploymentList.Items {
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			idx := strings.Index(container.Image, "/"+appImageRepoPrefix)
+			if idx < 0 || !strings.Contains(container.Image, "@") {
+				continue
+			}
+			result[container.Image[idx+1:]] = true
+		}
+	}
+
+	// Serving types not based on the app image (seldon, kfserving) still
+	// keep the image of the latest successful build.
+	runs, err := c.kubeClient.ListPipelineRuns(c.config.FusemlWorkloadsNamespace, kubernetes.AppNameLabelKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list PipelineRuns")
+	}
+	latest := map[string]tektonv1beta1.PipelineRun{}
+	for _, run := range runs.Items {
+		if pipelineRunImageDigest(run) == "" {
+			continue
+		}
+		app := run.Labels[kubernetes.AppNameLabelKey]
+		if current, found := latest[app]; !found || current.CreationTimestamp.Before(&run.CreationTimestamp) {
+			latest[app] = run
+		}
+	}
+	for app, run := range latest {
+		result[appImageRepoPrefix+app+"@"+pipelineRunImageDigest(run)] = true
+	}
+
+	return result, nil
+}
+
+// checkNoBuilds fails if PipelineRuns of the workloads are running, whose
+// pushes the registry garbage collection would break
+func (c *FusemlClient) checkNoBuilds() error {
+	runs, err := c.kubeClient.ListPipelineRuns(c.config.FusemlWorkloadsNamespace, "")
+	if err != nil {
+		return errors.Wrap(err, "failed to list PipelineRuns")
+	}
+
+	if running := runningPipelineRuns(runs.Items); len(running) > 0 {
+		return errors.Errorf("PipelineRuns are running: %s, prune the images when they are done", strings.Join(running, ", "))
+	}
+
+	return nil
+}
+
+// runningPipelineRuns returns the names of the runs not done yet, sorted
+func runningPipelineRuns(runs []tektonv1beta1.PipelineRun) []string {
+	running := []string{}
+	for i := range runs {
+		if !runs[i].IsDone() {
+			running = append(running, runs[i].Name)
+		}
+	}
+	sort.Strings(running)
+
+	return running
+}
+
+func (c *FusemlClient) registryPod() (string, error) {
+	podList, err := c.kubeClient.ListPods(deployments.RegistryDeploymentID, deployments.RegistryPodSelector)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to find the registry")
+	}
+
+	for _, pod := range podList.Items {
+		if pod.Status.Phase == v1.PodRunning {
+			return pod.Name, nil
+		}
+	}
+
+	return "", errors.New("registry is not running")
+}
+
+// pipelineRunImageDigest returns the digest of the image built by the run,
+// or an empty string if the build did not complete
+func pipelineRunImageDigest(run tektonv1beta1.PipelineRun) string {
+	for _, taskRun := range run.Status.TaskRuns {
+		if taskRun.PipelineTaskName != imageBuildPipelineTask || taskRun.Status == nil {
+			continue
+		}
+		for _, result := range taskRun.Status.TaskRunResults {
+			if result.Name == imageDigestResult {
+				return strings.TrimSpace(result.Value)
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,37 @@
+package paas_test
+
+import (
+	. "github.com/fuseml/fuseml/cli/paas"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+var _ = Describe("RunningPipelineRuns", func() {
+	run := func(name string, status corev1.ConditionStatus) tektonv1beta1.PipelineRun {
+		run := tektonv1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if status != "" {
+			run.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: status})
+		}
+		return run
+	}
+
+	It("returns the runs not done yet", func() {
+		running := RunningPipelineRuns([]tektonv1beta1.PipelineRun{
+			run("succeeded", corev1.ConditionTrue),
+			run("wine-running", corev1.ConditionUnknown),
+			run("failed", corev1.ConditionFalse),
+			run("beer-started", ""),
+		})
+		Expect(running).To(Equal([]string{"beer-started", "wine-running"}))
+	})
+
+	It("returns nothing when all runs are done", func() {
+		Expect(RunningPipelineRuns([]tektonv1beta1.PipelineRun{
+			run("succeeded", corev1.ConditionTrue),
+		})).To(BeEmpty())
+	})
+})
@@ -1,21 +1,28 @@
 package paas
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fuseml/fuseml/cli/deployments"
 	"github.com/fuseml/fuseml/cli/helpers"
 	"github.com/fuseml/fuseml/cli/kubernetes"
+	"github.com/fuseml/fuseml/cli/kubernetes/platform"
+	"github.com/fuseml/fuseml/cli/paas/bundle"
 	"github.com/fuseml/fuseml/cli/paas/config"
+	"github.com/fuseml/fuseml/cli/paas/githost"
 	"github.com/fuseml/fuseml/cli/paas/ui"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -39,7 +46,8 @@ func (c *InstallClient) Install(cmd *cobra.Command, options *kubernetes.Installa
 	defer log.Info("return")
 	details := log.V(1) // NOTE: Increment of level, not absolute.
 
-	c.ui.Note().Msg("FuseML installing...")
+	c.ui.Header().Msg("FuseML installing...")
+	c.ui.Note().V(1).Msg(c.kubeClient.GetPlatform().Describe())
 
 	var err error
 	details.Info("process cli options")
@@ -48,6 +56,34 @@ func (c *InstallClient) Install(cmd *cobra.Command, options *kubernetes.Installa
 		return err
 	}
 
+	valuesFile, err := cmd.Flags().GetString("values")
+	if err != nil {
+		return err
+	}
+	if valuesFile != "" {
+		details.Info("read values file", "File", valuesFile)
+		reader, err := kubernetes.NewFileOptionsReader(valuesFile)
+		if err != nil {
+			return err
+		}
+		options, err = options.Populate(reader)
+		if err != nil {
+			return err
+		}
+		if unused := reader.Unused(); len(unused) > 0 {
+			return errors.Errorf("unknown options in values file %s: %s", valuesFile, strings.Join(unused, ", "))
+		}
+	}
+
+	profile := c.platformProfile()
+	if len(profile.Options) > 0 {
+		details.Info("apply platform profile", "Platform", c.kubeClient.GetPlatform().String())
+		options, err = options.Populate(kubernetes.NewPlatformOptionsReader(profile))
+		if err != nil {
+			return err
+		}
+	}
+
 	interactive, err := cmd.Flags().GetBool("interactive")
 	if err != nil {
 		return err
@@ -70,34 +106,93 @@ func (c *InstallClient) Install(cmd *cobra.Command, options *kubernetes.Installa
 	details.Info("show option configuration")
 	c.showInstallConfiguration(options)
 
-	// TODO (post MVP): Run a validation phase which perform
-	// additional checks on the values. For example range limits,
-	// proper syntax of the string, etc. do it as pghase, and late
-	// to report all problems at once, instead of early and
-	// piecemal.
+	details.Info("validate options")
+	err = options.Validate()
+	if err != nil {
+		return err
+	}
 
-	deployment := deployments.Traefik{Timeout: DefaultTimeoutSec}
+	mirror, err := options.GetString("image_registry", "")
+	if err != nil {
+		return err
+	}
+
+	bundleOpt, err := options.GetOpt("bundle", "")
+	if err != nil {
+		return err
+	}
+	if bundleOpt.Value.(string) != "" && mirror == "" {
+		return errors.New("Installing from a bundle needs an image_registry to push the bundled images to")
+	}
 
-	details.Info("deploy", "Deployment", deployment.ID())
-	deployment.Deploy(c.kubeClient, c.ui, options.ForDeployment(deployment.ID()))
+	selected, err := c.selectDeployments(cmd)
 	if err != nil {
 		return err
 	}
 
-	// Try to give a omg.howdoi.website domain if the user didn't specify one
+	parallel, err := cmd.Flags().GetInt("parallel")
+	if err != nil {
+		return err
+	}
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		renderDir, err := cmd.Flags().GetString("render-dir")
+		if err != nil {
+			return err
+		}
+		return c.render(options, selected, renderDir)
+	}
+
+	if bundleOpt.Value.(string) != "" {
+		details.Info("push bundled images", "Bundle", bundleOpt.Value, "ImageRegistry", mirror)
+		b, err := pushBundle(c.ui, bundleOpt.Value.(string), mirror)
+		if err != nil {
+			return err
+		}
+		defer b.Close()
+
+		// The deployments read their charts from the unpacked bundle
+		bundleOpt.Value = b.Dir
+	}
+
+	if scc := c.platformProfile().SCC; scc != "" {
+		details.Info("grant security context constraint", "SCC", scc)
+		if err := c.kubeClient.GrantSCC(scc, deployments.Namespaces()); err != nil {
+			return err
+		}
+	}
+
+	// The ingress controller comes first, as the system domain defaults to
+	// its IP
+	if len(selected) > 0 && selected[0].ID() == deployments.TraefikDeploymentID {
+		deployment := selected[0]
+		details.Info("deploy", "Deployment", deployment.ID())
+		err = deployment.Deploy(c.kubeClient, c.ui, options.ForDeployment(deployment.ID()))
+		if err != nil {
+			return err
+		}
+		selected = selected[1:]
+	}
+
+	// Try to give a wildcard DNS domain if the user didn't specify one
 	domain, err := options.GetOpt("system_domain", "")
 	if err != nil {
 		return err
 	}
+	provider, err := options.GetString("dns_provider", "")
+	if err != nil {
+		return err
+	}
 
 	details.Info("ensure system-domain")
-	err = c.fillInMissingSystemDomain(domain)
+	err = c.fillInMissingSystemDomain(domain, provider)
 	if err != nil {
 		return err
 	}
-	if domain.Value.(string) == "" {
-		return errors.New("You didn't provide a system_domain and we were unable to setup a omg.howdoi.website domain (couldn't find an ExternalIP)")
-	}
 	if c.kubeClient.HasKnative() {
 		err = c.setDomainForKnative(domain.Value.(string))
 		if err != nil {
@@ -107,134 +202,600 @@ func (c *InstallClient) Install(cmd *cobra.Command, options *kubernetes.Installa
 
 	c.ui.Success().Msg("Created system_domain: " + domain.Value.(string))
 
-	for _, deployment := range []kubernetes.Deployment{
+	// Debug output would garble the lines of the concurrent progress display
+	if parallel != 1 && helpers.Debug() {
+		c.ui.Note().Msg("Deploying one component after another, as debug output is enabled")
+		parallel = 1
+	}
+
+	if parallel == 1 {
+		for _, deployment := range selected {
+			details.Info("deploy", "Deployment", deployment.ID())
+
+			err := deployment.Deploy(c.kubeClient, c.ui, options.ForDeployment(deployment.ID()))
+			if err != nil {
+				return err
+			}
+		}
+	} else if err := c.deployConcurrently(selected, options, parallel); err != nil {
+		return err
+	}
+
+	// fuseml status checks only the installed components
+	if err := recordComponents(c.componentsRecord(), selected, nil); err != nil {
+		c.ui.Exclamation().Msg(err.Error())
+	}
+
+	// An existing MLflow tracking server is used even when the MLflow
+	// deployment is skipped
+	if _, ok := selected.Get(deployments.MLflowDeploymentID); !ok {
+		mlflow := &deployments.MLflow{}
+		if err := mlflow.ApplyExternalConfig(c.kubeClient, c.ui, options.ForDeployment(mlflow.ID())); err != nil {
+			return err
+		}
+	}
+
+	// Applications pushed later on build on images from the mirror too
+	c.config.ImageRegistry = mirror
+	if err := c.config.Save(); err != nil {
+		return errors.Wrap(err, "failed to save configuration")
+	}
+
+	c.ui.Success().WithStringValue("System domain", domain.Value.(string)).Msg("FuseML installed.")
+
+	return nil
+}
+
+// HasGitHost returns whether the git hosting service is available for the
+// orgs: the Gitea of the cluster is installed, or GitLab is used
+func (c *InstallClient) HasGitHost() (bool, error) {
+	if c.config.GitProvider == githost.GitLab {
+		return true, nil
+	}
+
+	return deployments.GiteaInstalled(c.kubeClient)
+}
+
+// deployConcurrently deploys the selected deployments at the same time, as
+// far as their needs allow, at most limit of them at once. Their output is
+// kept aside and only shown for the failed ones, next to a progress display.
+func (c *InstallClient) deployConcurrently(selected kubernetes.Deployments, options *kubernetes.InstallationOptions, limit int) error {
+	log := c.Log.WithName("DeployConcurrently")
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	outputs := map[string]*outputBuffer{}
+	for _, deployment := range selected {
+		outputs[deployment.ID()] = &outputBuffer{}
+	}
+
+	progress := c.ui.MultiProgress(selected.IDs()...)
+
+	err := selected.Concurrently(limit, func(deployment kubernetes.Deployment) error {
+		details.Info("deploy", "Deployment", deployment.ID())
+		progress.Start(deployment.ID())
+
+		deploymentUI := c.ui.WithOutput(outputs[deployment.ID()])
+		err := deployment.Deploy(c.kubeClient, deploymentUI, options.ForDeployment(deployment.ID()))
+		if err != nil {
+			progress.Fail(deployment.ID(), err)
+			return err
+		}
+
+		progress.Done(deployment.ID())
+		return nil
+	}, func(deployment kubernetes.Deployment, err error) {
+		progress.Skip(deployment.ID(), err.Error())
+	})
+
+	progress.Stop()
+
+	if errs, ok := err.(kubernetes.DeploymentErrors); ok {
+		for _, e := range errs {
+			if output := outputs[e.ID].String(); output != "" {
+				c.ui.Problem().Msg("Output of " + e.ID + ":")
+				c.ui.Normal().Compact().Msg(output)
+			}
+		}
+	}
+
+	return err
+}
+
+// outputBuffer collects the output of a deployment, which is written by its
+// progress indicators too
+type outputBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *outputBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *outputBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// render writes everything the installation of the selected deployments
+// would apply into dir, one directory per deployment, without modifying the
+// cluster. The bundled images are not pushed.
+func (c *InstallClient) render(options *kubernetes.InstallationOptions, selected kubernetes.Deployments, dir string) error {
+	log := c.Log.WithName("Render").WithValues("Directory", dir)
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	if dir == "" {
+		return errors.New("A dry run needs a render directory")
+	}
+
+	c.ui.Header().WithStringValue("Directory", dir).Msg("Rendering FuseML installation...")
+
+	bundleOpt, err := options.GetOpt("bundle", "")
+	if err != nil {
+		return err
+	}
+	if bundleOpt.Value.(string) != "" {
+		details.Info("open bundle", "Bundle", bundleOpt.Value)
+		b, err := bundle.Open(bundleOpt.Value.(string))
+		if err != nil {
+			return err
+		}
+		defer b.Close()
+
+		bundleOpt.Value = b.Dir
+	}
+
+	// Without a system domain, the deployments can only be rendered when the
+	// ingress has its IP already, as there is no installation to wait for
+	domain, err := options.GetOpt("system_domain", "")
+	if err != nil {
+		return err
+	}
+	if domain.Value.(string) == "" {
+		provider, err := options.GetString("dns_provider", "")
+		if err != nil {
+			return err
+		}
+		ip, _, err := c.ingressAddress(c.ingressService(), 0)
+		if err != nil {
+			return errors.Wrap(err, "A dry run needs a system_domain, unless the "+c.ingressService()+" service can be reached already")
+		}
+		domain.Value = fmt.Sprintf("%s.%s", ip, provider)
+	}
+
+	if c.kubeClient.HasKnative() {
+		details.Info("render knative domain")
+		r, err := kubernetes.NewRenderer(filepath.Join(dir, "knative-serving"))
+		if err != nil {
+			return err
+		}
+		err = r.Objects("config-domain", &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "config-domain", Namespace: "knative-serving"},
+			Data:       map[string]string{domain.Value.(string): ""},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if scc := c.platformProfile().SCC; scc != "" {
+		details.Info("render security context constraint", "SCC", scc)
+		r, err := kubernetes.NewRenderer(filepath.Join(dir, "platform"))
+		if err != nil {
+			return err
+		}
+		err = r.Objects("scc", kubernetes.SCCBinding(scc, deployments.Namespaces()))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, deployment := range selected {
+		details.Info("render", "Deployment", deployment.ID())
+
+		err := deployment.Render(c.kubeClient, c.ui, options.ForDeployment(deployment.ID()), dir)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, ok := selected.Get(deployments.MLflowDeploymentID); !ok {
+		mlflow := &deployments.MLflow{}
+		if err := mlflow.RenderExternalConfig(c.ui, options.ForDeployment(mlflow.ID()), dir); err != nil {
+			return err
+		}
+	}
+
+	c.ui.Success().
+		WithStringValue("Directory", dir).
+		WithStringValue("System domain", domain.Value.(string)).
+		Msg("FuseML installation rendered.")
+
+	return nil
+}
+
+// fusemlDeployments returns all deployments of FuseML. The order is kept
+// by the installation, as far as their needs allow.
+func fusemlDeployments() kubernetes.Deployments {
+	return kubernetes.Deployments{
+		&deployments.Traefik{Timeout: DefaultTimeoutSec},
 		&deployments.Quarks{Timeout: DefaultTimeoutSec},
 		&deployments.Workloads{Timeout: DefaultTimeoutSec},
 		&deployments.MLflow{Timeout: DefaultTimeoutSec},
 		&deployments.Gitea{Timeout: DefaultTimeoutSec},
 		&deployments.Registry{Timeout: DefaultTimeoutSec},
 		&deployments.Tekton{Timeout: DefaultTimeoutSec},
-	} {
-		details.Info("deploy", "Deployment", deployment.ID())
+	}
+}
 
-		err := deployment.Deploy(c.kubeClient, c.ui, options.ForDeployment(deployment.ID()))
-		if err != nil {
-			return err
+// selectDeployments returns the deployments chosen with the --only and
+// --skip flags, in installation order. Needed deployments which are not
+// chosen have to be present already, the user is warned about them.
+func (c *InstallClient) selectDeployments(cmd *cobra.Command) (kubernetes.Deployments, error) {
+	only, err := cmd.Flags().GetStringSlice("only")
+	if err != nil {
+		return nil, err
+	}
+	skip, err := cmd.Flags().GetStringSlice("skip")
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := fusemlDeployments().Select(only, skip)
+	if err != nil {
+		return nil, err
+	}
+	selected, err = selected.Sorted()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, missing := range selected.Missing() {
+		c.ui.Exclamation().Msg(missing + ", which is not installed now: it has to be present already")
+	}
+
+	// The ingress of the platform replaces traefik
+	if ingress := c.platformProfile().Ingress; ingress != nil {
+		if _, ok := selected.Get(deployments.TraefikDeploymentID); ok {
+			c.ui.Note().
+				WithStringValue("Ingress class", ingress.Class).
+				Msg("Using the ingress of the platform, traefik is not installed")
+		}
+		remaining := kubernetes.Deployments{}
+		for _, deployment := range selected {
+			if deployment.ID() != deployments.TraefikDeploymentID {
+				remaining = append(remaining, deployment)
+			}
 		}
+		selected = remaining
 	}
 
-	c.ui.Success().WithStringValue("System domain", domain.Value.(string)).Msg("FuseML installed.")
+	return selected, nil
+}
 
-	return nil
+// platformProfile returns the install profile of the detected platform
+func (c *InstallClient) platformProfile() platform.Profile {
+	if p := c.kubeClient.GetPlatform(); p != nil {
+		return p.Profile()
+	}
+	return platform.Profile{}
+}
+
+// DeploymentOptions returns the options private to the deployments of an
+// installation, to be offered next to the shared installation options
+func DeploymentOptions() kubernetes.InstallationOptions {
+	options := kubernetes.InstallationOptions{}
+	for _, deployment := range fusemlDeployments() {
+		options = append(options, deployment.Options()...)
+	}
+
+	return options
 }
 
-// Uninstall removes fuseml from the cluster.
-func (c *InstallClient) Uninstall(cmd *cobra.Command) error {
+// Uninstall removes the given components of fuseml from the cluster, or all
+// of them, in reverse installation order.
+func (c *InstallClient) Uninstall(cmd *cobra.Command, components []string) error {
 	log := c.Log.WithName("Uninstall")
 	log.Info("start")
 	defer log.Info("return")
 	details := log.V(1) // NOTE: Increment of level, not absolute.
 
-	c.ui.Note().Msg("FuseML uninstalling...")
+	all, err := fusemlDeployments().Sorted()
+	if err != nil {
+		return err
+	}
+	removed, err := all.Select(components, nil)
+	if err != nil {
+		return err
+	}
 
-	for _, deployment := range []kubernetes.Deployment{
-		&deployments.Workloads{Timeout: DefaultTimeoutSec},
-		&deployments.Tekton{Timeout: DefaultTimeoutSec},
-		&deployments.Registry{Timeout: DefaultTimeoutSec},
-		&deployments.Gitea{Timeout: DefaultTimeoutSec},
-		&deployments.Quarks{Timeout: DefaultTimeoutSec},
-		&deployments.Traefik{Timeout: DefaultTimeoutSec},
-	} {
+	if len(components) > 0 {
+		remaining, err := all.Select(nil, components)
+		if err != nil {
+			return err
+		}
+		for _, missing := range remaining.Missing() {
+			c.ui.Exclamation().Msg(missing + ", which is being removed")
+		}
+	}
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return errors.Wrap(err, "could not read dry-run flag")
+	}
+	keepData, err := cmd.Flags().GetBool("keep-data")
+	if err != nil {
+		return errors.Wrap(err, "could not read keep-data flag")
+	}
+	options := kubernetes.DeleteOptions{DryRun: dryRun, KeepData: keepData}
+
+	if dryRun {
+		c.ui.Header().Msg("FuseML uninstall dry run, nothing is removed")
+	} else {
+		c.ui.Header().Msg("FuseML uninstalling...")
+	}
+
+	for _, deployment := range removed.Reversed() {
 		details.Info("remove", "Deployment", deployment.ID())
-		err := deployment.Delete(c.kubeClient, c.ui)
+		err := deployment.Delete(c.kubeClient, c.ui, options)
 		if err != nil {
 			return err
 		}
 	}
 
+	// The record of the installed components is removed with the workloads
+	// namespace
+	if len(components) > 0 && !dryRun {
+		if err := recordComponents(c.componentsRecord(), nil, removed); err != nil {
+			c.ui.Exclamation().Msg(err.Error())
+		}
+	}
+
+	// The system domain is only set up for a complete installation
+	if len(components) == 0 {
+		details.Info("revert knative domain")
+		if err := c.unsetDomainForKnative(dryRun); err != nil {
+			return err
+		}
+
+		if scc := c.platformProfile().SCC; scc != "" {
+			details.Info("revoke security context constraint", "SCC", scc)
+			if err := c.revokeSCC(scc, dryRun); err != nil {
+				return err
+			}
+		}
+	}
+
+	if dryRun {
+		return nil
+	}
+
 	c.ui.Success().Msg("FuseML uninstalled.")
 
 	return nil
 }
 
 // showInstallConfiguration prints the options and their values to stdout, to
-// inform the user of the detected and chosen configuration
+// inform the user of the detected and chosen configuration. The values of
+// sensitive options are masked.
 func (c *InstallClient) showInstallConfiguration(opts *kubernetes.InstallationOptions) {
 	m := c.ui.Normal()
 	for _, opt := range *opts {
 		name := "  :compass: " + opt.Name
-		switch opt.Type {
-		case kubernetes.BooleanType:
-			m = m.WithBoolValue(name, opt.Value.(bool))
-		case kubernetes.StringType:
-			m = m.WithStringValue(name, opt.Value.(string))
-		case kubernetes.IntType:
-			m = m.WithIntValue(name, opt.Value.(int))
+		if opt.DeploymentID != "" {
+			name = "  :compass: " + opt.DeploymentID + "." + opt.Name
+		}
+		if opt.Source != "" {
+			name += " (" + string(opt.Source) + ")"
+		}
+		if opt.Sensitive && opt.Value != "" {
+			m = m.WithStringValue(name, "********")
+			continue
+		}
+		// Shown before validation, the value may not match the Type
+		switch value := opt.Value.(type) {
+		case bool:
+			m = m.WithBoolValue(name, value)
+		case string:
+			m = m.WithStringValue(name, value)
+		case int:
+			m = m.WithIntValue(name, value)
+		default:
+			m = m.WithStringValue(name, fmt.Sprintf("%v", value))
 		}
 	}
 	m.Msg("Configuration...")
 }
 
-func (c *InstallClient) fillInMissingSystemDomain(domain *kubernetes.InstallationOption) error {
-	if domain.Value.(string) == "" {
-		service := "traefik"
-		if c.kubeClient.HasIstio() {
-			service = "istio-ingressgateway"
-		}
-		ip := ""
-		s := c.ui.Progressf(fmt.Sprintf("Waiting for LoadBalancer IP on %s service.", service))
-		defer s.Stop()
+// loadBalancerTimeout is how long the installation waits for a load balancer
+// IP on the ingress service, before falling back to its node port
+const loadBalancerTimeout = 2 * time.Minute
+
+// fillInMissingSystemDomain sets the system domain, if it is not given, to
+// the wildcard DNS domain of the address the ingress is reached at
+func (c *InstallClient) fillInMissingSystemDomain(domain *kubernetes.InstallationOption, provider string) error {
+	if domain.Value.(string) != "" {
+		return nil
+	}
+
+	ip, port, err := c.ingressAddress(c.ingressService(), loadBalancerTimeout)
+	if err != nil {
+		return err
+	}
+
+	domain.Value = fmt.Sprintf("%s.%s", ip, provider)
+
+	// The URLs of the cli, e.g. of Gitea, carry the node port
+	c.config.IngressPort = 0
+	if port != kubernetes.HTTPPort {
+		c.config.IngressPort = int(port)
+	}
+
+	return nil
+}
+
+// ingressService returns the name of the service exposing the ingress
+func (c *InstallClient) ingressService() string {
+	if c.kubeClient.HasIstio() {
+		return "istio-ingressgateway"
+	}
+	if ingress := c.platformProfile().Ingress; ingress != nil {
+		return ingress.Service
+	}
+	return "traefik"
+}
+
+// ingressAddress returns the IP and port the ingress service is reached at:
+// the IP of its load balancer, waiting up to timeout for one to be
+// provisioned, or else a node IP detected by the platform, with the node
+// port of the service
+func (c *InstallClient) ingressAddress(name string, timeout time.Duration) (string, int32, error) {
+	service, err := c.findService(name)
+	if err != nil {
+		return "", 0, err
+	}
+
+	ip := kubernetes.LoadBalancerIP(service)
+	if ip == "" && service.Spec.Type == v1.ServiceTypeLoadBalancer && timeout > 0 {
+		s := c.ui.Progressf("Waiting for LoadBalancer IP on %s service.", name)
 		err := helpers.RunToSuccessWithTimeout(
 			func() error {
-				return c.fetchIP(&ip, service)
-			}, time.Duration(2)*time.Minute, 3*time.Second)
-		if err != nil {
-			if strings.Contains(err.Error(), "Timed out after") {
-				return errors.New("Timed out waiting for LoadBalancer IP on " + service + " service.\n" +
-					"Ensure your kubernetes platform has the ability to provision LoadBalancer IP address.\n\n" +
-					"Follow these steps to enable this ability\n" +
-					"https://github.com/fuseml/fuseml/blob/main/docs/install.md")
-			}
-			return err
-		}
-
-		if ip != "" {
-			domain.Value = fmt.Sprintf("%s.omg.howdoi.website", ip)
+				service, err = c.findService(name)
+				if err != nil {
+					return err
+				}
+				if ip = kubernetes.LoadBalancerIP(service); ip == "" {
+					return errors.Errorf("no LoadBalancer IP on %s service", name)
+				}
+				return nil
+			}, timeout, 3*time.Second)
+		s.Stop()
+		if err != nil && !strings.Contains(err.Error(), "Timed out after") {
+			return "", 0, err
 		}
+	}
+	if ip != "" {
+		return ip, kubernetes.HTTPPort, nil
+	}
 
+	nodeIPs := []string{}
+	if platform := c.kubeClient.GetPlatform(); platform != nil {
+		nodeIPs = platform.ExternalIPs()
+	}
+	ip, port, err := kubernetes.NodePortAddress(service, nodeIPs)
+	if err != nil {
+		return "", 0, errors.Wrapf(err, "No LoadBalancer IP on %s service, and no node port to fall back to.\n"+
+			"Ensure your kubernetes platform has the ability to provision LoadBalancer IP address, or give a system_domain.\n\n"+
+			"Follow these steps to enable this ability\n"+
+			"https://github.com/fuseml/fuseml/blob/main/docs/install.md", name)
 	}
 
-	return nil
+	c.ui.Exclamation().
+		WithStringValue("Node IP", ip).
+		WithIntValue("Node port", int(port)).
+		Msgf("No LoadBalancer IP on %s service, using its node port", name)
+
+	return ip, port, nil
 }
 
-func (c *InstallClient) fetchIP(ip *string, service string) error {
+// findService returns the named service, in any namespace
+func (c *InstallClient) findService(name string) (*v1.Service, error) {
 	serviceList, err := c.kubeClient.Kubectl.CoreV1().Services("").List(context.Background(), metav1.ListOptions{
-		FieldSelector: "metadata.name=" + service,
+		FieldSelector: "metadata.name=" + name,
 	})
-	if len(serviceList.Items) == 0 {
-		return errors.New(fmt.Sprintf("couldn't find the %s service", service))
-	}
 	if err != nil {
-		return err
+		return nil, err
 	}
-	ingress := serviceList.Items[0].Status.LoadBalancer.Ingress
-	if len(ingress) <= 0 {
-		return errors.New(fmt.Sprintf("ingress list is empty in %s service", service))
+	if len(serviceList.Items) == 0 {
+		return nil, errors.New(fmt.Sprintf("couldn't find the %s service", name))
 	}
-	*ip = ingress[0].IP
 
-	return nil
+	return &serviceList.Items[0], nil
 }
 
+// knativeDomainAnnotation records the domain FuseML added to the Knative
+// domain configuration, for uninstall to remove it again
+const knativeDomainAnnotation = "fuseml/domain"
+
 func (c *InstallClient) setDomainForKnative(domain string) error {
 	knDomainConfig, err := c.kubeClient.Kubectl.CoreV1().ConfigMaps("knative-serving").Get(context.Background(), "config-domain", metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
+	if knDomainConfig.Data == nil {
+		knDomainConfig.Data = map[string]string{}
+	}
+	if knDomainConfig.Annotations == nil {
+		knDomainConfig.Annotations = map[string]string{}
+	}
 	knDomainConfig.Data[domain] = ""
+	knDomainConfig.Annotations[knativeDomainAnnotation] = domain
 	_, err = c.kubeClient.Kubectl.CoreV1().ConfigMaps("knative-serving").Update(context.Background(), knDomainConfig, metav1.UpdateOptions{})
 	if err != nil {
 		return errors.New("could not update Knative domain configuration")
 	}
 	return nil
 }
+
+// unsetDomainForKnative removes the domain added by setDomainForKnative
+// from the Knative domain configuration, or with dryRun only shows it
+func (c *InstallClient) unsetDomainForKnative(dryRun bool) error {
+	if !c.kubeClient.HasKnative() {
+		return nil
+	}
+
+	configMaps := c.kubeClient.Kubectl.CoreV1().ConfigMaps("knative-serving")
+	knDomainConfig, err := configMaps.Get(context.Background(), "config-domain", metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not read Knative domain configuration")
+	}
+
+	domain, ok := knDomainConfig.Annotations[knativeDomainAnnotation]
+	if !ok {
+		return nil
+	}
+
+	if dryRun {
+		c.ui.Normal().WithTable("Would remove").
+			WithTableRow("domain " + domain + " from configmap knative-serving/config-domain").
+			Msg("Knative:")
+		return nil
+	}
+
+	delete(knDomainConfig.Data, domain)
+	delete(knDomainConfig.Annotations, knativeDomainAnnotation)
+	_, err = configMaps.Update(context.Background(), knDomainConfig, metav1.UpdateOptions{})
+	if err != nil {
+		return errors.New("could not update Knative domain configuration")
+	}
+
+	c.ui.Success().WithStringValue("Domain", domain).Msg("Removed from the Knative domain configuration")
+
+	return nil
+}
+
+// revokeSCC removes the security context constraint granted to the FuseML
+// namespaces by the installation, or with dryRun only shows it
+func (c *InstallClient) revokeSCC(scc string, dryRun bool) error {
+	if dryRun {
+		c.ui.Normal().WithTable("Would remove").
+			WithTableRow("clusterrolebinding " + kubernetes.SCCBinding(scc, nil).Name).
+			Msg("Platform:")
+		return nil
+	}
+
+	if err := c.kubeClient.RevokeSCC(scc); err != nil {
+		return err
+	}
+
+	c.ui.Success().WithStringValue("SCC", scc).Msg("Revoked the security context constraint")
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package paas_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	. "github.com/fuseml/fuseml/cli/paas"
+	"github.com/fuseml/fuseml/cli/paas/config"
+	"github.com/fuseml/fuseml/cli/paas/githost"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	k8s "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+)
+
+// helmReleaseSecret returns the secret helm stores the release in
+func helmReleaseSecret(rel *release.Release) corev1.Secret {
+	data, err := json.Marshal(rel)
+	Expect(err).ToNot(HaveOccurred())
+
+	var zipped bytes.Buffer
+	w := gzip.NewWriter(&zipped)
+	_, err = w.Write(data)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(w.Close()).To(Succeed())
+
+	return corev1.Secret{
+		TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sh.helm.release.v1." + rel.Name + ".v1",
+			Namespace: rel.Namespace,
+			Labels: map[string]string{
+				"name":    rel.Name,
+				"owner":   "helm",
+				"status":  rel.Info.Status.String(),
+				"version": "1",
+			},
+		},
+		Type: "helm.sh/release.v1",
+		Data: map[string][]byte{
+			"release": []byte(base64.StdEncoding.EncodeToString(zipped.Bytes())),
+		},
+	}
+}
+
+var _ = Describe("HasGitHost", func() {
+	var (
+		api    kubeStandIn
+		server *httptest.Server
+		cfg    *config.Config
+		client *InstallClient
+	)
+
+	BeforeEach(func() {
+		api = kubeStandIn{
+			// helm checks that the cluster is reachable
+			"/version": version.Info{Major: "1", Minor: "20", GitVersion: "v1.20.0"},
+			"/api/v1/namespaces/gitea/secrets": corev1.SecretList{
+				TypeMeta: metav1.TypeMeta{Kind: "SecretList", APIVersion: "v1"},
+			},
+		}
+		server = httptest.NewServer(api)
+
+		restConfig := &restclient.Config{Host: server.URL}
+		clientset, err := k8s.NewForConfig(restConfig)
+		Expect(err).ToNot(HaveOccurred())
+
+		cfg = &config.Config{GitProvider: githost.Gitea}
+		client = NewTestInstallClient(&kubernetes.Cluster{Kubectl: clientset, RestConfig: restConfig}, cfg)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("is false without Gitea installed", func() {
+		Expect(client.HasGitHost()).To(BeFalse())
+	})
+
+	It("is true with Gitea installed", func() {
+		api["/api/v1/namespaces/gitea/secrets"] = corev1.SecretList{
+			TypeMeta: metav1.TypeMeta{Kind: "SecretList", APIVersion: "v1"},
+			Items: []corev1.Secret{helmReleaseSecret(&release.Release{
+				Name:      "gitea",
+				Namespace: "gitea",
+				Version:   1,
+				Info:      &release.Info{Status: release.StatusDeployed},
+			})},
+		}
+
+		Expect(client.HasGitHost()).To(BeTrue())
+	})
+
+	It("is true with GitLab, without checking the cluster", func() {
+		server.Close()
+		cfg.GitProvider = githost.GitLab
+
+		Expect(client.HasGitHost()).To(BeTrue())
+	})
+})
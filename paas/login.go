@@ -0,0 +1,285 @@
+package paas
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fuseml/fuseml/cli/paas/config"
+	paasgitea "github.com/fuseml/fuseml/cli/paas/gitea"
+	"github.com/fuseml/fuseml/cli/paas/ui"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/term"
+)
+
+// OAuth2Timeout is how long fuseml login waits for the user to authorize
+// the cli in the browser
+const OAuth2Timeout = 5 * time.Minute
+
+// giteaUserKeys are the settings holding the Gitea user logged in
+var giteaUserKeys = []string{
+	"gitea_username",
+	"gitea_token",
+	"gitea_token_name",
+	"gitea_refresh_token",
+	"gitea_token_expiry",
+}
+
+// LoginClient logs the user in to the installed Gitea. Unlike the
+// FusemlClient, it does not need the admin credentials of Gitea.
+type LoginClient struct {
+	ui            *ui.UI
+	config        *config.Config
+	giteaResolver *paasgitea.Resolver
+	Log           logr.Logger
+}
+
+// Login authenticates the user to Gitea with their password, and keeps a
+// new personal access token of theirs in the config. The password is read
+// from the terminal, or from stdin if it is not one.
+func (c *LoginClient) Login(username, otp string) error {
+	log := c.Log.WithName("Login").WithValues("User", username)
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	url, err := c.giteaResolver.GetGiteaURL()
+	if err != nil {
+		return err
+	}
+	c.ui.Header().WithStringValue("Gitea", url).Msg("Logging in...")
+
+	if username == "" {
+		username, err = readLine("Username: ")
+		if err != nil {
+			return err
+		}
+	}
+	password, err := readPassword("Password: ")
+	if err != nil {
+		return err
+	}
+
+	details.Info("create token")
+	name, token, err := c.giteaResolver.CreateToken(username, password, otp)
+	if err != nil {
+		return err
+	}
+
+	c.config.GiteaUsername = username
+	c.config.GiteaToken = token
+	c.config.GiteaTokenName = name
+	c.config.GiteaRefreshToken = ""
+	c.config.GiteaTokenExpiry = ""
+	if err := c.config.Save(); err != nil {
+		return err
+	}
+
+	c.ui.Success().
+		WithStringValue("User", username).
+		WithStringValue("Token", name).
+		Msg("Logged in")
+
+	return nil
+}
+
+// LoginOAuth2 authenticates the user to Gitea with the OAuth2 application
+// of the cli, and keeps the token in the config. The user authorizes the
+// cli in the browser, which is redirected to a local server on port.
+func (c *LoginClient) LoginOAuth2(port int) error {
+	log := c.Log.WithName("LoginOAuth2").WithValues("Port", port)
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	redirectURL := fmt.Sprintf("http://127.0.0.1:%d/callback", port)
+	oauthConfig, err := c.giteaResolver.OAuth2Config(redirectURL)
+	if err != nil {
+		return err
+	}
+
+	state, err := randomState()
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen for the OAuth2 redirect on port %d", port)
+	}
+
+	codes := make(chan string, 1)
+	failures := make(chan error, 1)
+	server := &http.Server{Handler: callbackHandler(state, codes, failures)}
+	go server.Serve(listener)
+	defer server.Close()
+
+	// PKCE, against intercepted codes
+	verifier := oauth2.GenerateVerifier()
+
+	c.ui.Header().
+		WithStringValue("URL", oauthConfig.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))).
+		Msg("Open the URL in your browser to authorize the cli...")
+
+	var code string
+	select {
+	case code = <-codes:
+	case err := <-failures:
+		return err
+	case <-time.After(OAuth2Timeout):
+		return errors.Errorf("no authorization after %s", OAuth2Timeout)
+	}
+
+	details.Info("exchange code")
+	token, err := oauthConfig.Exchange(context.Background(), code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return errors.Wrap(err, "failed to get the OAuth2 token")
+	}
+
+	username, err := c.giteaResolver.TokenUser(token.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	c.giteaResolver.SetOAuth2Token(username, token)
+	if err := c.config.Save(); err != nil {
+		return err
+	}
+
+	c.ui.Success().WithStringValue("User", username).Msg("Logged in")
+
+	return nil
+}
+
+// callbackHandler handles the redirect of the browser after the user
+// authorized the cli, or not, passing on the authorization code or the
+// failure. Only the first redirect with the right state is passed on, the
+// later ones are answered without waiting for them to be read.
+func callbackHandler(state string, codes chan<- string, failures chan<- error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/callback" {
+			http.NotFound(w, r)
+			return
+		}
+		query := r.URL.Query()
+		switch {
+		case query.Get("state") != state:
+			http.Error(w, "Invalid state", http.StatusBadRequest)
+			return
+		case query.Get("error") != "":
+			select {
+			case failures <- errors.Errorf("authorization denied: %s", query.Get("error")):
+			default:
+			}
+		default:
+			select {
+			case codes <- query.Get("code"):
+			default:
+			}
+		}
+		fmt.Fprintln(w, "You can close this window and go back to the fuseml cli.")
+	})
+}
+
+// Logout removes the token of the user logged in from the config. With
+// revoke, their personal access token is deleted in Gitea too, which needs
+// their password.
+func (c *LoginClient) Logout(revoke bool, otp string) error {
+	log := c.Log.WithName("Logout")
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	username, tokenName := c.config.GiteaUsername, c.config.GiteaTokenName
+	if c.config.GiteaToken == "" {
+		c.ui.Exclamation().Msg("Not logged in")
+		return nil
+	}
+
+	c.ui.Header().WithStringValue("User", username).Msg("Logging out...")
+
+	if revoke && tokenName != "" {
+		password, err := readPassword("Password: ")
+		if err != nil {
+			return err
+		}
+
+		details.Info("delete token", "Token", tokenName)
+		err = c.giteaResolver.DeleteToken(username, password, otp, tokenName)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, key := range giteaUserKeys {
+		if err := c.config.Unset(key); err != nil {
+			return err
+		}
+	}
+	if err := c.config.Save(); err != nil {
+		return err
+	}
+
+	if !revoke && tokenName != "" {
+		c.ui.Note().
+			WithStringValue("Token", tokenName).
+			Msg("The token is still valid, delete it in the Gitea settings, or log out with --revoke")
+	}
+
+	c.ui.Success().WithStringValue("User", username).Msg("Logged out")
+
+	return nil
+}
+
+// stdin is shared by the prompts, so that none reads ahead the input of the
+// next one
+var stdin = bufio.NewReader(os.Stdin)
+
+// readLine asks the user for a line of input
+func readLine(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", errors.Wrap(err, "failed to read input")
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readPassword asks the user for a password, without echoing it on a
+// terminal
+func readPassword(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return readLine(prompt)
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read password")
+	}
+
+	return string(password), nil
+}
+
+// randomState returns the random state of an OAuth2 authorization, against
+// forged redirects
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to generate OAuth2 state")
+	}
+
+	return hex.EncodeToString(b), nil
+}
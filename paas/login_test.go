@@ -0,0 +1,143 @@
+package paas_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/fuseml/fuseml/cli/paas"
+	"github.com/fuseml/fuseml/cli/paas/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+)
+
+var _ = Describe("OAuth2 callback", func() {
+	var (
+		codes    chan string
+		failures chan error
+		handler  http.Handler
+	)
+
+	BeforeEach(func() {
+		codes = make(chan string, 1)
+		failures = make(chan error, 1)
+		handler = CallbackHandler("s3cr3t", codes, failures)
+	})
+
+	redirect := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/callback?"+query, nil))
+		return w
+	}
+
+	It("passes on the authorization code", func() {
+		w := redirect("state=s3cr3t&code=c0de")
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(codes).To(Receive(Equal("c0de")))
+		Expect(failures).ToNot(Receive())
+	})
+
+	It("passes on the denied authorization", func() {
+		w := redirect("state=s3cr3t&error=access_denied")
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(failures).To(Receive(MatchError("authorization denied: access_denied")))
+		Expect(codes).ToNot(Receive())
+	})
+
+	It("rejects redirects with another state", func() {
+		w := redirect("state=forged&code=c0de")
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+		Expect(codes).ToNot(Receive())
+		Expect(failures).ToNot(Receive())
+	})
+
+	It("rejects other paths", func() {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/?state=s3cr3t&code=c0de", nil))
+		Expect(w.Code).To(Equal(http.StatusNotFound))
+		Expect(codes).ToNot(Receive())
+	})
+
+	It("answers later redirects without blocking", func() {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			redirect("state=s3cr3t&code=first")
+			redirect("state=s3cr3t&code=second")
+			redirect("state=s3cr3t&error=access_denied")
+			redirect("state=s3cr3t&error=access_denied")
+		}()
+
+		Eventually(done).Should(BeClosed())
+		Expect(codes).To(Receive(Equal("first")))
+	})
+})
+
+var _ = Describe("Logout", func() {
+	var dir, file string
+
+	load := func() *config.Config {
+		cfg, err := config.Load(nil)
+		Expect(err).ToNot(HaveOccurred())
+		return cfg
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "fuseml-config")
+		Expect(err).ToNot(HaveOccurred())
+		file = filepath.Join(dir, "config.yaml")
+
+		err = ioutil.WriteFile(file, []byte(`
+version: 1
+current_context: dev
+contexts:
+  dev:
+    org: team
+    gitea_username: dev
+    gitea_token: t0ken
+    gitea_token_name: fuseml-laptop
+    gitea_refresh_token: refr3sh
+    gitea_token_expiry: "2026-10-18T12:00:00Z"
+`), 0600)
+		Expect(err).ToNot(HaveOccurred())
+
+		viper.Set("config-file", file)
+		viper.Set("context", "")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("forgets the user logged in, in the active context", func() {
+		Expect(NewTestLoginClient(load()).Logout(false, "")).To(Succeed())
+
+		cfg := load()
+		_, ctx := cfg.Context()
+		Expect(ctx).ToNot(BeNil())
+		Expect(ctx.Org).To(Equal("team"))
+		Expect(ctx.GiteaUsername).To(BeEmpty())
+		Expect(ctx.GiteaToken).To(BeEmpty())
+		Expect(ctx.GiteaTokenName).To(BeEmpty())
+		Expect(ctx.GiteaRefreshToken).To(BeEmpty())
+		Expect(ctx.GiteaTokenExpiry).To(BeEmpty())
+
+		for _, key := range []string{"gitea_username", "gitea_token", "gitea_token_name", "gitea_refresh_token", "gitea_token_expiry"} {
+			value, _, err := cfg.Get(key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(value).To(BeEmpty(), key)
+		}
+	})
+
+	It("does nothing without user logged in", func() {
+		cfg := load()
+		cfg.GiteaToken = ""
+		Expect(NewTestLoginClient(cfg).Logout(false, "")).To(Succeed())
+
+		Expect(load().GiteaToken).To(Equal("t0ken"))
+	})
+})
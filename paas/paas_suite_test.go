@@ -0,0 +1,13 @@
+package paas_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestPaas(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Paas Suite")
+}
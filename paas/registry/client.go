@@ -0,0 +1,163 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ManifestMediaTypes are the manifest types accepted when resolving digests.
+	// Kaniko pushes docker v2 schema 2 manifests, the OCI types are there for
+	// completeness.
+	ManifestMediaTypes = "application/vnd.docker.distribution.manifest.v2+json, " +
+		"application/vnd.docker.distribution.manifest.list.v2+json, " +
+		"application/vnd.oci.image.manifest.v1+json, " +
+		"application/vnd.oci.image.index.v1+json"
+)
+
+// Client talks to a docker distribution registry using the v2 HTTP API
+type Client struct {
+	url      string
+	username string
+	password string
+	http     *http.Client
+}
+
+// NewClient creates a new registry client for the registry at url
+func NewClient(url, username, password string) *Client {
+	return &Client{
+		url:      strings.TrimSuffix(url, "/"),
+		username: username,
+		password: password,
+		http:     http.DefaultClient,
+	}
+}
+
+// Catalog returns the names of all repositories stored in the registry
+func (c *Client) Catalog() ([]string, error) {
+	result := []string{}
+
+	next := "/v2/_catalog?n=100"
+	for next != "" {
+		resp, err := c.do("GET", next, "")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list repositories")
+		}
+
+		body := struct {
+			Repositories []string `json:"repositories"`
+		}{}
+		err = decode(resp, &body)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list repositories")
+		}
+
+		result = append(result, body.Repositories...)
+		next = nextLink(resp)
+	}
+
+	return result, nil
+}
+
+// Tags returns the tags of the given repository
+func (c *Client) Tags(repository string) ([]string, error) {
+	resp, err := c.do("GET", fmt.Sprintf("/v2/%s/tags/list", repository), "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list tags of %s", repository)
+	}
+
+	body := struct {
+		Tags []string `json:"tags"`
+	}{}
+	err = decode(resp, &body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list tags of %s", repository)
+	}
+
+	return body.Tags, nil
+}
+
+// Digest resolves a tag or digest reference in the repository to the digest
+// of its manifest. It returns an empty string if the manifest does not exist.
+func (c *Client) Digest(repository, reference string) (string, error) {
+	resp, err := c.do("HEAD", fmt.Sprintf("/v2/%s/manifests/%s", repository, reference), ManifestMediaTypes)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get manifest %s:%s", repository, reference)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("failed to get manifest %s:%s: %s", repository, reference, resp.Status)
+	}
+
+	return resp.Header.Get("Docker-Content-Digest"), nil
+}
+
+// DeleteManifest removes the manifest with the given digest from the
+// repository. The registry needs to run with deletion enabled. Deleting an
+// unknown manifest is not an error.
+func (c *Client) DeleteManifest(repository, digest string) error {
+	resp, err := c.do("DELETE", fmt.Sprintf("/v2/%s/manifests/%s", repository, digest), "")
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete manifest %s@%s", repository, digest)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusAccepted, http.StatusOK, http.StatusNotFound:
+		return nil
+	}
+
+	return errors.Errorf("failed to delete manifest %s@%s: %s", repository, digest, resp.Status)
+}
+
+func (c *Client) do(method, path, accept string) (*http.Response, error) {
+	req, err := http.NewRequest(method, c.url+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	return c.http.Do(req)
+}
+
+func decode(resp *http.Response, into interface{}) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(into)
+}
+
+// nextLink extracts the path of the next page from the Link header of a
+// paginated response, e.g. `</v2/_catalog?last=b&n=100>; rel="next"`
+func nextLink(resp *http.Response) string {
+	link := resp.Header.Get("Link")
+	if link == "" || !strings.Contains(link, `rel="next"`) {
+		return ""
+	}
+
+	start := strings.Index(link, "<")
+	end := strings.Index(link, ">")
+	if start < 0 || end <= start {
+		return ""
+	}
+
+	return link[start+1 : end]
+}
@@ -0,0 +1,92 @@
+package registry_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/fuseml/fuseml/cli/paas/registry"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var server *httptest.Server
+	var client *Client
+	var deleted []string
+
+	BeforeEach(func() {
+		deleted = []string{}
+		mux := http.NewServeMux()
+		mux.HandleFunc("/v2/_catalog", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("last") == "" {
+				w.Header().Set("Link", `</v2/_catalog?last=apps%2Fa&n=100>; rel="next"`)
+				w.Write([]byte(`{"repositories":["apps/a"]}`))
+				return
+			}
+			w.Write([]byte(`{"repositories":["apps/b"]}`))
+		})
+		mux.HandleFunc("/v2/apps/a/tags/list", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"name":"apps/a","tags":["latest"]}`))
+		})
+		mux.HandleFunc("/v2/apps/a/manifests/", func(w http.ResponseWriter, r *http.Request) {
+			user, pass, ok := r.BasicAuth()
+			if !ok || user != "admin" || pass != "password" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			switch r.Method {
+			case "HEAD":
+				if r.URL.Path != "/v2/apps/a/manifests/latest" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				Expect(r.Header.Get("Accept")).To(ContainSubstring("manifest.v2+json"))
+				w.Header().Set("Docker-Content-Digest", "sha256:aaaa")
+			case "DELETE":
+				deleted = append(deleted, r.URL.Path)
+				w.WriteHeader(http.StatusAccepted)
+			}
+		})
+		server = httptest.NewServer(mux)
+		client = NewClient(server.URL+"/", "admin", "password")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("follows the pagination of the catalog", func() {
+		repositories, err := client.Catalog()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(repositories).To(Equal([]string{"apps/a", "apps/b"}))
+	})
+
+	It("lists the tags of a repository", func() {
+		tags, err := client.Tags("apps/a")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tags).To(Equal([]string{"latest"}))
+	})
+
+	It("resolves tags to digests", func() {
+		digest, err := client.Digest("apps/a", "latest")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest).To(Equal("sha256:aaaa"))
+	})
+
+	It("returns an empty digest for unknown manifests", func() {
+		digest, err := client.Digest("apps/a", "missing")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest).To(BeEmpty())
+	})
+
+	It("deletes manifests by digest", func() {
+		Expect(client.DeleteManifest("apps/a", "sha256:bbbb")).To(Succeed())
+		Expect(deleted).To(Equal([]string{"/v2/apps/a/manifests/sha256:bbbb"}))
+	})
+
+	It("reports authentication problems", func() {
+		_, err := NewClient(server.URL, "admin", "wrong").Digest("apps/a", "latest")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("401"))
+	})
+})
@@ -0,0 +1,73 @@
+package paas
+
+import (
+	"fmt"
+
+	"github.com/fuseml/fuseml/cli/paas/config"
+	"github.com/fuseml/fuseml/cli/paas/ui"
+)
+
+// ViewConfig prints all settings of the configuration, with where their
+// values come from. Credentials are hidden.
+func ViewConfig(ui *ui.UI, cfg *config.Config) error {
+	ui.Header().WithStringValue("File", config.Location()).Msg("Showing configuration")
+
+	msg := ui.Success().WithTable("Key", "Value", "Source")
+	for _, key := range config.Keys() {
+		value, source, err := cfg.Get(key)
+		if err != nil {
+			return err
+		}
+		shown := fmt.Sprint(value)
+		if config.IsSecret(key) && shown != "" {
+			shown = "********"
+		}
+		msg = msg.WithTableRow(key, shown, cfg.SourceName(source))
+	}
+	msg.Msg("Configuration:")
+
+	return nil
+}
+
+// GetConfig prints the value of the setting alone, for scripts
+func GetConfig(cfg *config.Config, key string) error {
+	value, _, err := cfg.Get(key)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(value)
+
+	return nil
+}
+
+// SetConfig changes the setting and saves the configuration
+func SetConfig(ui *ui.UI, cfg *config.Config, key, value string) error {
+	if err := cfg.Set(key, value); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	if config.IsSecret(key) {
+		value = "********"
+	}
+	ui.Success().WithStringValue(key, value).Msg("Setting saved")
+
+	return nil
+}
+
+// UnsetConfig removes the setting and saves the configuration
+func UnsetConfig(ui *ui.UI, cfg *config.Config, key string) error {
+	if err := cfg.Unset(key); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	ui.Success().WithStringValue("Key", key).Msg("Setting removed")
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package paas
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fuseml/fuseml/cli/kubernetes"
+	"github.com/pkg/errors"
+)
+
+// ErrDegraded is returned by Status when any check failed
+var ErrDegraded = errors.New("FuseML is degraded")
+
+// Status checks the health of the installed components, and shows the
+// results as a table, or as JSON. It returns an error if any of them is
+// degraded.
+func (c *InstallClient) Status(output string) error {
+	log := c.Log.WithName("Status")
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	if output != "table" && output != "json" {
+		return errors.Errorf("unknown output format %s, use table or json", output)
+	}
+
+	if output == "table" {
+		c.ui.Header().Msg("Checking the health of FuseML...")
+		c.ui.Note().V(1).Msg(c.kubeClient.GetPlatform().Describe())
+	}
+
+	all, err := fusemlDeployments().Sorted()
+	if err != nil {
+		return err
+	}
+
+	// Components skipped at install are not checked
+	installed, recorded, err := installedComponents(c.componentsRecord())
+	if err != nil {
+		return err
+	}
+	skipped := []string{}
+	if recorded {
+		isInstalled := map[string]bool{}
+		for _, id := range installed {
+			isInstalled[id] = true
+		}
+		checked := kubernetes.Deployments{}
+		for _, deployment := range all {
+			if isInstalled[deployment.ID()] {
+				checked = append(checked, deployment)
+			} else {
+				skipped = append(skipped, deployment.ID())
+			}
+		}
+		all = checked
+	}
+
+	// The checks of a component are independent of the others, and mostly
+	// wait for the network
+	results := make([][]kubernetes.HealthCheck, len(all))
+	fns := []func() error{}
+	for i, deployment := range all {
+		i, deployment := i, deployment
+		fns = append(fns, func() error {
+			details.Info("check", "Deployment", deployment.ID())
+			results[i] = deployment.Health(c.kubeClient)
+			return nil
+		})
+	}
+	if err := concurrently(fns...); err != nil {
+		return err
+	}
+
+	checks := []kubernetes.HealthCheck{}
+	for i, deployment := range all {
+		for _, check := range results[i] {
+			check.Deployment = deployment.ID()
+			checks = append(checks, check)
+		}
+	}
+	degraded := kubernetes.Degraded(checks)
+
+	if output == "json" {
+		status := "healthy"
+		if degraded > 0 {
+			status = "degraded"
+		}
+		contents, err := json.MarshalIndent(struct {
+			Status  string                   `json:"status"`
+			Checks  []kubernetes.HealthCheck `json:"checks"`
+			Skipped []string                 `json:"skipped"`
+		}{status, checks, skipped}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(contents))
+	} else {
+		msg := c.ui.Normal().WithTable("Component", "Check", "Status", "Details")
+		for _, check := range checks {
+			status := "ok"
+			if !check.Healthy {
+				status = "degraded"
+			}
+			msg = msg.WithTableRow(check.Deployment, check.Check, status, check.Details)
+		}
+		msg.Msg("FuseML status:")
+
+		if len(skipped) > 0 {
+			c.ui.Note().Msg("Not installed, hence not checked: " + strings.Join(skipped, ", "))
+		}
+
+		if degraded == 0 {
+			c.ui.Success().Msg("FuseML is healthy")
+		} else {
+			c.ui.Problem().Msgf("%d checks failed, FuseML is degraded", degraded)
+		}
+	}
+
+	if degraded > 0 {
+		return ErrDegraded
+	}
+
+	return nil
+}
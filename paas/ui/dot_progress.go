@@ -86,18 +86,12 @@ func (p *DotProgress) ChangeMessagef(message string, a ...interface{}) {
 }
 
 // ChangeMessage extends the dot-based progress with the ability to
-// change the message mid-flight
+// change the message mid-flight. Unlike the initial message, it is shown
+// at the default verbosity, as it reports the state of what is waited for.
 func (p *DotProgress) ChangeMessage(message string) {
-	// Prevent the restart dance if the message would not be shown
-	// anyway, due to a low verbosity level. This keeps the dots
-	// nicer.
-	if p.ui.verbosity < 1 {
-		return
-	}
-
 	message = mfinal(message)
 	p.Stop()
-	p.ui.ProgressNote().V(1).KeepLine().Msg(message)
+	p.ui.ProgressNote().KeepLine().Msg(message)
 	p.Start()
 }
 
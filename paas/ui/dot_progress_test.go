@@ -0,0 +1,29 @@
+package ui_test
+
+import (
+	"bytes"
+
+	"github.com/fatih/color"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fuseml/fuseml/cli/paas/ui"
+)
+
+var _ = Describe("DotProgress", func() {
+	var out *bytes.Buffer
+
+	BeforeEach(func() {
+		color.NoColor = true
+		out = &bytes.Buffer{}
+	})
+
+	It("shows the changed messages at the default verbosity", func() {
+		progress := NewUI().WithOutput(out).Progress("Starting pods in gitea")
+		progress.ChangeMessagef("  Starting pod %s in %s: %s", "gitea-0", "gitea", "ContainerCreating")
+		progress.Stop()
+
+		Expect(out.String()).ToNot(ContainSubstring("Starting pods in gitea"))
+		Expect(out.String()).To(ContainSubstring("  Starting pod gitea-0 in gitea: ContainerCreating "))
+	})
+})
@@ -0,0 +1,213 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/kyokomi/emoji"
+	"github.com/mattn/go-isatty"
+)
+
+// This file implements a progress display for several tasks running at
+// the same time, e.g. components deployed concurrently. Each task has one
+// line showing its state.
+
+// TaskState is the state of a task shown by a MultiProgress
+type TaskState int
+
+const (
+	TaskWaiting TaskState = iota
+	TaskRunning
+	TaskDone
+	TaskFailed
+	TaskSkipped
+)
+
+func (s TaskState) String() string {
+	switch s {
+	case TaskRunning:
+		return "running"
+	case TaskDone:
+		return "done"
+	case TaskFailed:
+		return "failed"
+	case TaskSkipped:
+		return "skipped"
+	}
+	return "waiting"
+}
+
+// Standard refresh rate of the lines of a multi progress on a terminal
+const multiProgressTime = 1 * time.Second
+
+// MultiProgress shows the state of several tasks. On a terminal the lines of
+// the tasks are redrawn in place, otherwise each change of state is printed
+// as a new line.
+type MultiProgress struct {
+	ui       *UI
+	mu       sync.Mutex
+	names    []string
+	tasks    map[string]*task
+	live     bool
+	drawn    int
+	stopped  bool
+	stopChan chan struct{}
+}
+
+type task struct {
+	state   TaskState
+	detail  string
+	started time.Time
+	elapsed time.Duration
+}
+
+// MultiProgress creates and returns an active progress display for the
+// named tasks, all of them waiting
+func (u *UI) MultiProgress(names ...string) *MultiProgress {
+	p := &MultiProgress{
+		ui:       u,
+		names:    names,
+		tasks:    map[string]*task{},
+		live:     u.out == nil && isatty.IsTerminal(os.Stdout.Fd()),
+		stopChan: make(chan struct{}),
+	}
+	for _, name := range names {
+		p.tasks[name] = &task{state: TaskWaiting}
+	}
+
+	fmt.Fprintln(u.output())
+
+	if p.live {
+		p.draw()
+		go func() {
+			ticker := time.NewTicker(multiProgressTime)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-p.stopChan:
+					return
+				case <-ticker.C:
+					p.mu.Lock()
+					p.draw()
+					p.mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	return p
+}
+
+// Start marks the task as running
+func (p *MultiProgress) Start(name string) {
+	p.change(name, TaskRunning, "")
+}
+
+// Done marks the task as successfully finished
+func (p *MultiProgress) Done(name string) {
+	p.change(name, TaskDone, "")
+}
+
+// Fail marks the task as failed, because of err
+func (p *MultiProgress) Fail(name string, err error) {
+	p.change(name, TaskFailed, firstLine(err.Error()))
+}
+
+// Skip marks the task as not run, for the given reason
+func (p *MultiProgress) Skip(name string, reason string) {
+	p.change(name, TaskSkipped, reason)
+}
+
+// Stop ends the display, leaving the final state of the tasks on screen
+func (p *MultiProgress) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stopped {
+		return
+	}
+	p.stopped = true
+
+	if p.live {
+		close(p.stopChan)
+		p.draw()
+	}
+}
+
+func (p *MultiProgress) change(name string, state TaskState, detail string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	t, ok := p.tasks[name]
+	if !ok || p.stopped {
+		return
+	}
+
+	switch state {
+	case TaskRunning:
+		t.started = time.Now()
+	case TaskDone, TaskFailed:
+		t.elapsed = time.Since(t.started)
+	}
+	t.state = state
+	t.detail = detail
+
+	if p.live {
+		p.draw()
+	} else {
+		fmt.Fprintln(p.ui.output(), p.line(name))
+	}
+}
+
+// draw redraws the lines of all tasks, replacing those drawn before
+func (p *MultiProgress) draw() {
+	out := p.ui.output()
+
+	if p.drawn > 0 {
+		fmt.Fprintf(out, "\033[%dA", p.drawn)
+	}
+	for _, name := range p.names {
+		fmt.Fprintf(out, "\r\033[2K%s\n", p.line(name))
+	}
+	p.drawn = len(p.names)
+}
+
+// line returns the line describing the state of the task
+func (p *MultiProgress) line(name string) string {
+	t := p.tasks[name]
+
+	var line string
+	switch t.state {
+	case TaskWaiting:
+		line = emoji.Sprintf(":hourglass: %s: %s", name, t.state)
+	case TaskRunning:
+		line = emoji.Sprintf(":three-thirty: %s: %s", name, t.state)
+		if p.live {
+			line += fmt.Sprintf(" (%s)", time.Since(t.started).Round(time.Second))
+		}
+	case TaskDone:
+		line = color.GreenString(emoji.Sprintf(":heavy_check_mark: %s: %s (%s)", name, t.state, t.elapsed.Round(time.Second)))
+	case TaskFailed:
+		line = color.RedString(emoji.Sprintf(":forbidden:%s: %s (%s)", name, t.state, t.elapsed.Round(time.Second)))
+	case TaskSkipped:
+		line = color.YellowString(emoji.Sprintf(":warning: %s: %s", name, t.state))
+	}
+
+	if t.detail != "" {
+		line += " - " + t.detail
+	}
+
+	return line
+}
+
+// firstLine returns the first line of s, so that a task keeps to one line
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i] + " ..."
+	}
+	return s
+}
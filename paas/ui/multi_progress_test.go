@@ -0,0 +1,48 @@
+package ui_test
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/fatih/color"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/fuseml/fuseml/cli/paas/ui"
+)
+
+var _ = Describe("MultiProgress", func() {
+	var out *bytes.Buffer
+	var progress *MultiProgress
+
+	BeforeEach(func() {
+		color.NoColor = true
+		out = &bytes.Buffer{}
+		progress = NewUI().WithOutput(out).MultiProgress("quarks", "gitea", "registry")
+	})
+
+	It("prints a line for each change of state when not on a terminal", func() {
+		progress.Start("quarks")
+		progress.Start("gitea")
+		progress.Done("quarks")
+		progress.Fail("gitea", errors.New("timed out\nwaiting for the pods"))
+		progress.Skip("registry", "needs quarks")
+		progress.Stop()
+
+		lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+		Expect(lines).To(HaveLen(5))
+		Expect(string(lines[0])).To(HaveSuffix("quarks: running"))
+		Expect(string(lines[1])).To(HaveSuffix("gitea: running"))
+		Expect(string(lines[2])).To(HaveSuffix("quarks: done (0s)"))
+		Expect(string(lines[3])).To(HaveSuffix("gitea: failed (0s) - timed out ..."))
+		Expect(string(lines[4])).To(HaveSuffix("registry: skipped - needs quarks"))
+	})
+
+	It("ignores unknown tasks and changes after stopping", func() {
+		progress.Start("tekton")
+		progress.Stop()
+		progress.Start("quarks")
+
+		Expect(bytes.TrimSpace(out.Bytes())).To(BeEmpty())
+	})
+})
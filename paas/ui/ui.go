@@ -3,6 +3,7 @@ package ui
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"time"
 
@@ -39,7 +40,9 @@ const (
 // UI contains functionality for dealing with the user
 // on the CLI
 type UI struct {
-	verbosity int // Verbosity level for user messages.
+	verbosity int       // Verbosity level for user messages.
+	out       io.Writer // Destination of the messages, stdout if nil.
+	context   string    // Active context, shown by Header.
 }
 
 // Message represents a piece of information we want displayed to the user
@@ -80,6 +83,31 @@ func NewUI() *UI {
 	}
 }
 
+// WithOutput returns a UI with the same verbosity, which prints its
+// messages to out instead of stdout, e.g. to keep the output of tasks
+// running at the same time apart.
+func (u *UI) WithOutput(out io.Writer) *UI {
+	return &UI{
+		verbosity: u.verbosity,
+		out:       out,
+		context:   u.context,
+	}
+}
+
+// SetContext sets the name of the active context, to be shown by the
+// header of every command
+func (u *UI) SetContext(name string) {
+	u.context = name
+}
+
+// output returns the destination of the messages
+func (u *UI) output() io.Writer {
+	if u.out == nil {
+		return os.Stdout
+	}
+	return u.out
+}
+
 // Progress creates, configures, and returns an active progress
 // meter. It accepts a formatted message.
 func (u *UI) Progressf(message string, a ...interface{}) Progress {
@@ -123,6 +151,16 @@ func (u *UI) Note() *Message {
 	}
 }
 
+// Header returns a UIMessage that prints the note starting the output of a
+// command, showing the active context, if any
+func (u *UI) Header() *Message {
+	m := u.Note()
+	if u.context != "" {
+		m = m.WithStringValue("Context", u.context)
+	}
+	return m
+}
+
 // Success returns a UIMessage that prints a success message
 func (u *UI) Success() *Message {
 	return &Message{
@@ -168,8 +206,10 @@ func (u *Message) Msg(message string) {
 	message = emoji.Sprint(message)
 
 	// Print a newline before starting output, if not compact.
+	out := u.ui.output()
+
 	if message != "" && !u.compact {
-		fmt.Println()
+		fmt.Fprintln(out)
 	}
 
 	if !u.keepline {
@@ -194,12 +234,12 @@ func (u *Message) Msg(message string) {
 		message = color.RedString(message)
 	}
 
-	fmt.Printf("%s", message)
+	fmt.Fprintf(out, "%s", message)
 
 	for _, interaction := range u.interactions {
 		switch interaction.variant {
 		case ask:
-			fmt.Printf("> ")
+			fmt.Fprintf(out, "> ")
 			switch interaction.valueType {
 			case tBool:
 				interaction.value = readBool()
@@ -211,17 +251,17 @@ func (u *Message) Msg(message string) {
 		case show:
 			switch interaction.valueType {
 			case tBool:
-				fmt.Printf("%s: %s\n", emoji.Sprint(interaction.name), color.MagentaString("%b", interaction.value))
+				fmt.Fprintf(out, "%s: %s\n", emoji.Sprint(interaction.name), color.MagentaString("%b", interaction.value))
 			case tInt:
-				fmt.Printf("%s: %s\n", emoji.Sprint(interaction.name), color.CyanString("%d", interaction.value))
+				fmt.Fprintf(out, "%s: %s\n", emoji.Sprint(interaction.name), color.CyanString("%d", interaction.value))
 			case tString:
-				fmt.Printf("%s: %s\n", emoji.Sprint(interaction.name), color.GreenString("%s", interaction.value))
+				fmt.Fprintf(out, "%s: %s\n", emoji.Sprint(interaction.name), color.GreenString("%s", interaction.value))
 			}
 		}
 	}
 
 	for idx, headers := range u.tableHeaders {
-		table := tablewriter.NewWriter(os.Stdout)
+		table := tablewriter.NewWriter(out)
 		table.SetHeader(headers)
 		table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
 		table.SetCenterSeparator("|")
@@ -0,0 +1,241 @@
+package paas
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/fuseml/fuseml/cli/paas/githost"
+	"github.com/pkg/errors"
+)
+
+// initialPasswordBytes is the amount of random bytes of the generated
+// initial passwords
+const initialPasswordBytes = 12
+
+// users returns the user management of the git host, if it has one
+func (c *FusemlClient) users() (githost.Users, error) {
+	users, ok := c.gitHost.(githost.Users)
+	if !ok {
+		return nil, errors.Errorf("the users of %s are not managed by fuseml", c.gitHost.Name())
+	}
+
+	return users, nil
+}
+
+// Users lists the users of the git host
+func (c *FusemlClient) Users() error {
+	log := c.Log.WithName("Users")
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	c.ui.Header().Msg("Listing users")
+
+	users, err := c.users()
+	if err != nil {
+		return err
+	}
+
+	details.Info("list users")
+	list, err := users.Users()
+	if err != nil {
+		return errors.Wrap(err, "failed to list users")
+	}
+
+	msg := c.ui.Success().WithTable("Name", "Full Name", "Email", "Admin")
+	for _, user := range list {
+		admin := ""
+		if user.Admin {
+			admin = "yes"
+		}
+		msg = msg.WithTableRow(user.Username, user.FullName, user.Email, admin)
+	}
+	msg.Msg("Users:")
+
+	return nil
+}
+
+// CreateUser creates a user on the git host. Without a password, a random
+// initial password is generated and shown. The user has to change it when
+// logging in first.
+func (c *FusemlClient) CreateUser(user githost.User, password string) error {
+	log := c.Log.WithName("CreateUser").WithValues("User", user.Username)
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	c.ui.Header().
+		WithStringValue("Name", user.Username).
+		WithStringValue("Email", user.Email).
+		Msg("Creating user...")
+
+	users, err := c.users()
+	if err != nil {
+		return err
+	}
+
+	generated := password == ""
+	if generated {
+		b := make([]byte, initialPasswordBytes)
+		if _, err := rand.Read(b); err != nil {
+			return errors.Wrap(err, "failed to generate password")
+		}
+		password = base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	details.Info("create user")
+	if err := users.CreateUser(user, password); err != nil {
+		return errors.Wrap(err, "failed to create user")
+	}
+
+	msg := c.ui.Success().WithStringValue("Name", user.Username)
+	if generated {
+		msg = msg.WithStringValue("Initial password", password)
+	}
+	msg.Msg("User created, the password has to be changed when logging in first.")
+
+	return nil
+}
+
+// DeleteUser removes a user from the git host
+func (c *FusemlClient) DeleteUser(username string) error {
+	log := c.Log.WithName("DeleteUser").WithValues("User", username)
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	c.ui.Header().
+		WithStringValue("Name", username).
+		Msg("Deleting user...")
+
+	users, err := c.users()
+	if err != nil {
+		return err
+	}
+
+	details.Info("delete user")
+	if err := users.DeleteUser(username); err != nil {
+		return errors.Wrap(err, "failed to delete user")
+	}
+
+	c.ui.Success().WithStringValue("Name", username).Msg("User deleted.")
+
+	return nil
+}
+
+// OrgMembers lists the members of the org, with their roles, only those
+// with the role if it is not empty. The targeted org is used if org is
+// empty.
+func (c *FusemlClient) OrgMembers(org, role string) error {
+	if org == "" {
+		org = c.config.Org
+	}
+
+	log := c.Log.WithName("OrgMembers").WithValues("Organization", org, "Role", role)
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	header := c.ui.Header().WithStringValue("Organization", org)
+	if role != "" {
+		header = header.WithStringValue("Role", role)
+	}
+	header.Msg("Listing members")
+
+	if role != "" && role != githost.RoleOwner {
+		if err := githost.ValidateRole(role); err != nil {
+			return err
+		}
+	}
+
+	users, err := c.users()
+	if err != nil {
+		return err
+	}
+
+	details.Info("list members")
+	members, err := users.Members(org)
+	if err != nil {
+		return errors.Wrap(err, "failed to list members")
+	}
+
+	msg := c.ui.Success().WithTable("Name", "Role")
+	for _, member := range members {
+		if role != "" && member.Role != role {
+			continue
+		}
+		msg = msg.WithTableRow(member.Username, member.Role)
+	}
+	msg.Msg("Members:")
+
+	return nil
+}
+
+// AddOrgMember gives the user the role in the org, adding them to it if
+// needed. The targeted org is used if org is empty.
+func (c *FusemlClient) AddOrgMember(org, username, role string) error {
+	if org == "" {
+		org = c.config.Org
+	}
+
+	log := c.Log.WithName("AddOrgMember").WithValues("Organization", org, "User", username, "Role", role)
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	c.ui.Header().
+		WithStringValue("Organization", org).
+		WithStringValue("Name", username).
+		WithStringValue("Role", role).
+		Msg("Adding member...")
+
+	if err := githost.ValidateRole(role); err != nil {
+		return err
+	}
+
+	users, err := c.users()
+	if err != nil {
+		return err
+	}
+
+	details.Info("add member")
+	if err := users.AddMember(org, username, role); err != nil {
+		return errors.Wrap(err, "failed to add member")
+	}
+
+	c.ui.Success().Msg("Member added.")
+
+	return nil
+}
+
+// RemoveOrgMember removes the user from the org. The targeted org is used
+// if org is empty.
+func (c *FusemlClient) RemoveOrgMember(org, username string) error {
+	if org == "" {
+		org = c.config.Org
+	}
+
+	log := c.Log.WithName("RemoveOrgMember").WithValues("Organization", org, "User", username)
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	c.ui.Header().
+		WithStringValue("Organization", org).
+		WithStringValue("Name", username).
+		Msg("Removing member...")
+
+	users, err := c.users()
+	if err != nil {
+		return err
+	}
+
+	details.Info("remove member")
+	if err := users.RemoveMember(org, username); err != nil {
+		return errors.Wrap(err, "failed to remove member")
+	}
+
+	c.ui.Success().Msg("Member removed.")
+
+	return nil
+}
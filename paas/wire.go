@@ -7,7 +7,6 @@ import (
 	kubeconfig "github.com/fuseml/fuseml/cli/kubernetes/config"
 	"github.com/fuseml/fuseml/cli/paas/config"
 	"github.com/fuseml/fuseml/cli/paas/gitea"
-	"github.com/fuseml/fuseml/cli/paas/ui"
 	"github.com/google/wire"
 	"github.com/spf13/pflag"
 )
@@ -17,10 +16,11 @@ func NewFusemlClient(flags *pflag.FlagSet, configOverrides func(*config.Config))
 	wire.Build(
 		wire.Struct(new(FusemlClient), "*"),
 		config.Load,
-		ui.NewUI,
-		gitea.NewGiteaClient,
+		NewContextUI,
+		NewGitHost,
 		gitea.NewResolver,
 		kubernetes.NewClusterFromClient,
+		kubeLocation,
 		kubeconfig.KubeConfig,
 		kubeconfig.NewClientLogger,
 	)
@@ -33,11 +33,28 @@ func NewInstallClient(flags *pflag.FlagSet, configOverrides func(*config.Config)
 	wire.Build(
 		wire.Struct(new(InstallClient), "*"),
 		config.Load,
-		ui.NewUI,
+		NewContextUI,
 		kubernetes.NewClusterFromClient,
+		kubeLocation,
 		kubeconfig.KubeConfig,
 		kubeconfig.NewInstallClientLogger,
 	)
 
 	return &InstallClient{}, func() {}, nil
 }
+
+// NewLoginClient creates the Fuseml Client for logging in to Gitea
+func NewLoginClient(flags *pflag.FlagSet, configOverrides func(*config.Config)) (*LoginClient, func(), error) {
+	wire.Build(
+		wire.Struct(new(LoginClient), "*"),
+		config.Load,
+		NewContextUI,
+		gitea.NewResolver,
+		kubernetes.NewClusterFromClient,
+		kubeLocation,
+		kubeconfig.KubeConfig,
+		kubeconfig.NewLoginClientLogger,
+	)
+
+	return &LoginClient{}, func() {}, nil
+}
@@ -10,7 +10,6 @@ import (
 	config2 "github.com/fuseml/fuseml/cli/kubernetes/config"
 	"github.com/fuseml/fuseml/cli/paas/config"
 	"github.com/fuseml/fuseml/cli/paas/gitea"
-	"github.com/fuseml/fuseml/cli/paas/ui"
 	"github.com/spf13/pflag"
 )
 
@@ -21,7 +20,8 @@ func NewFusemlClient(flags *pflag.FlagSet, configOverrides func(*config.Config))
 	if err != nil {
 		return nil, nil, err
 	}
-	restConfig, err := config2.KubeConfig()
+	location := kubeLocation(flags, configConfig)
+	restConfig, err := config2.KubeConfig(location)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -30,14 +30,14 @@ func NewFusemlClient(flags *pflag.FlagSet, configOverrides func(*config.Config))
 		return nil, nil, err
 	}
 	resolver := gitea.NewResolver(configConfig, cluster)
-	client, err := gitea.NewGiteaClient(resolver)
+	host, err := NewGitHost(configConfig, resolver)
 	if err != nil {
 		return nil, nil, err
 	}
-	uiUI := ui.NewUI()
+	uiUI := NewContextUI(configConfig)
 	logger := config2.NewClientLogger()
 	fusemlClient := &FusemlClient{
-		giteaClient:   client,
+		gitHost:       host,
 		kubeClient:    cluster,
 		ui:            uiUI,
 		config:        configConfig,
@@ -49,19 +49,20 @@ func NewFusemlClient(flags *pflag.FlagSet, configOverrides func(*config.Config))
 }
 
 func NewInstallClient(flags *pflag.FlagSet, configOverrides func(*config.Config)) (*InstallClient, func(), error) {
-	restConfig, err := config2.KubeConfig()
+	configConfig, err := config.Load(flags)
 	if err != nil {
 		return nil, nil, err
 	}
-	cluster, err := kubernetes.NewClusterFromClient(restConfig)
+	location := kubeLocation(flags, configConfig)
+	restConfig, err := config2.KubeConfig(location)
 	if err != nil {
 		return nil, nil, err
 	}
-	uiUI := ui.NewUI()
-	configConfig, err := config.Load(flags)
+	cluster, err := kubernetes.NewClusterFromClient(restConfig)
 	if err != nil {
 		return nil, nil, err
 	}
+	uiUI := NewContextUI(configConfig)
 	logger := config2.NewInstallClientLogger()
 	installClient := &InstallClient{
 		kubeClient: cluster,
@@ -72,3 +73,30 @@ func NewInstallClient(flags *pflag.FlagSet, configOverrides func(*config.Config)
 	return installClient, func() {
 	}, nil
 }
+
+func NewLoginClient(flags *pflag.FlagSet, configOverrides func(*config.Config)) (*LoginClient, func(), error) {
+	configConfig, err := config.Load(flags)
+	if err != nil {
+		return nil, nil, err
+	}
+	uiUI := NewContextUI(configConfig)
+	location := kubeLocation(flags, configConfig)
+	restConfig, err := config2.KubeConfig(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	cluster, err := kubernetes.NewClusterFromClient(restConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	resolver := gitea.NewResolver(configConfig, cluster)
+	logger := config2.NewLoginClientLogger()
+	loginClient := &LoginClient{
+		ui:            uiUI,
+		config:        configConfig,
+		giteaResolver: resolver,
+		Log:           logger,
+	}
+	return loginClient, func() {
+	}, nil
+}